@@ -59,6 +59,77 @@ func (*OverloadFuncDecl) declNode() {}
 
 // -----------------------------------------------------------------------------
 
+// EnumDecl node represents a closed enum declaration:
+//
+//	enum Color {
+//		Red
+//		Green
+//		Blue
+//	}
+//
+//	enum Shape {
+//		Circle(r float64)
+//		Rect(w, h float64)
+//		Empty
+//	}
+//
+// An enum whose variants have no fields is a set of typed constants.
+// Otherwise it is a sum type: a sealed interface implemented by a tuple
+// type of each variant.
+type EnumDecl struct {
+	Doc      *CommentGroup  // associated documentation; or nil
+	Enum     token.Pos      // position of "enum"
+	Name     *Ident         // enum name
+	Lbrace   token.Pos      // position of "{"
+	Variants []*EnumVariant // variants of the enum
+	Rbrace   token.Pos      // position of "}"
+}
+
+// Pos - position of first character belonging to the node.
+func (p *EnumDecl) Pos() token.Pos {
+	return p.Enum
+}
+
+// End - position of first character immediately after the node.
+func (p *EnumDecl) End() token.Pos {
+	return p.Rbrace + 1
+}
+
+// IsSumType reports whether any variant of the enum has fields.
+func (p *EnumDecl) IsSumType() bool {
+	for _, v := range p.Variants {
+		if v.Fields != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (*EnumDecl) declNode() {}
+
+// EnumVariant node represents a variant of an enum declaration.
+type EnumVariant struct {
+	Doc     *CommentGroup // associated documentation; or nil
+	Name    *Ident        // variant name
+	Fields  *FieldList    // fields of the variant, eg. `(w, h float64)`; or nil
+	Comment *CommentGroup // line comments; or nil
+}
+
+// Pos - position of first character belonging to the node.
+func (p *EnumVariant) Pos() token.Pos {
+	return p.Name.Pos()
+}
+
+// End - position of first character immediately after the node.
+func (p *EnumVariant) End() token.Pos {
+	if p.Fields != nil {
+		return p.Fields.End()
+	}
+	return p.Name.End()
+}
+
+// -----------------------------------------------------------------------------
+
 // A CallExpr node represents an expression followed by an argument list.
 // The argument list may include positional arguments (Args) and/or
 // keyword arguments (Kwargs).
@@ -142,9 +213,9 @@ func (*DomainTextLit) exprNode() {}
 // A BasicLit node represents a literal of basic type.
 type BasicLit struct {
 	ValuePos token.Pos    // literal position
-	Kind     token.Token  // token.INT, token.FLOAT, token.IMAG, token.RAT, token.CHAR, token.STRING, token.CSTRING
-	Value    string       // literal string; e.g. 42, 0x7f, 3.14, 1e-9, 2.4i, 3r, 'a', '\x7f', "foo" or `\m\n\o`
-	Extra    *StringLitEx // optional (only available when Kind == token.STRING)
+	Kind     token.Token  // token.INT, token.FLOAT, token.IMAG, token.RAT, token.CHAR, token.STRING, token.CSTRING or token.PYSTRING
+	Value    string       // literal string (without c or py prefix); e.g. 42, 0x7f, 3.14, 1e-9, 2.4i, 3r, 'a', '\x7f', "foo" or `\m\n\o`
+	Extra    *StringLitEx // optional (only available when Kind == token.STRING, token.CSTRING or token.PYSTRING)
 }
 
 type StringLitEx struct {
@@ -167,7 +238,16 @@ func NextPartPos(pos token.Pos, part any) (nextPos token.Pos) {
 func (x *BasicLit) Pos() token.Pos { return x.ValuePos }
 
 // End returns position of first character immediately after the node.
-func (x *BasicLit) End() token.Pos { return token.Pos(int(x.ValuePos) + len(x.Value)) }
+func (x *BasicLit) End() token.Pos {
+	n := len(x.Value)
+	switch x.Kind {
+	case token.CSTRING: // c"..."
+		n++
+	case token.PYSTRING: // py"..."
+		n += 2
+	}
+	return token.Pos(int(x.ValuePos) + n)
+}
 
 func (*BasicLit) exprNode() {}
 
@@ -385,6 +465,57 @@ func (*ErrWrapExpr) exprNode() {}
 
 // -----------------------------------------------------------------------------
 
+// OptChainExpr represents the operand `x?` of an optional selector `x?.sel`.
+// The optional chain that contains it evaluates to the zero value of its type
+// if x is nil:
+//
+//	a?.b.c?.d // SelectorExpr{X: OptChainExpr{X: SelectorExpr{X: SelectorExpr{X: OptChainExpr{X: a}, Sel: b}, Sel: c}}, Sel: d}
+type OptChainExpr struct {
+	X     Expr
+	Quest token.Pos // position of "?."
+}
+
+// Pos - position of first character belonging to the node.
+func (p *OptChainExpr) Pos() token.Pos {
+	return p.X.Pos()
+}
+
+// End - position of first character immediately after the node.
+func (p *OptChainExpr) End() token.Pos {
+	return p.Quest + 1
+}
+
+func (*OptChainExpr) exprNode() {}
+
+// -----------------------------------------------------------------------------
+
+// GoExpr represents a go expression, which runs a list of calls concurrently
+// and waits for all of them:
+//
+//	go [call1, call2, ...]
+//	go [call for k, v in container if cond]
+//
+// A call can be a lambda expression `ctx => call` to receive the context of the
+// group of calls.
+type GoExpr struct {
+	Go token.Pos // position of "go"
+	X  Expr      // *SliceLit or *ComprehensionExpr
+}
+
+// Pos - position of first character belonging to the node.
+func (p *GoExpr) Pos() token.Pos {
+	return p.Go
+}
+
+// End - position of first character immediately after the node.
+func (p *GoExpr) End() token.Pos {
+	return p.X.End()
+}
+
+func (*GoExpr) exprNode() {}
+
+// -----------------------------------------------------------------------------
+
 // LambdaExpr represents one of the following expressions:
 //
 //	`(x, y, ...) => exprOrExprTuple`
@@ -485,6 +616,7 @@ func (*RangeExpr) exprNode() {}
 type ForPhrase struct {
 	For        token.Pos // position of "for" keyword
 	Key, Value *Ident    // Key may be nil
+	Tuple      *TupleLit // destructuring pattern of value, eg. `for k, (a, b) in container`; or nil
 	TokPos     token.Pos // position of "in" operator
 	X          Expr      // value to range over
 	IfPos      token.Pos // position of if or comma; or NoPos
@@ -553,6 +685,66 @@ func (*ForPhraseStmt) stmtNode() {}
 
 // -----------------------------------------------------------------------------
 
+// A MatchStmt node represents a match statement:
+//
+//	match x {
+//	case (0, y):
+//		...
+//	case Point{x: a} if a > 0:
+//		...
+//	case _:
+//		...
+//	}
+//
+// A pattern is an expression: `_` matches any value, an identifier that
+// doesn't denote a constant binds a new variable, tuple literals and
+// composite literals destructure the value, and any other expression is
+// compared with the value.
+type MatchStmt struct {
+	Match token.Pos  // position of "match" keyword
+	X     Expr       // value to match
+	Body  *BlockStmt // MatchClauses only
+}
+
+// Pos - position of first character belonging to the node.
+func (p *MatchStmt) Pos() token.Pos {
+	return p.Match
+}
+
+// End - position of first character immediately after the node.
+func (p *MatchStmt) End() token.Pos {
+	return p.Body.End()
+}
+
+func (*MatchStmt) stmtNode() {}
+
+// A MatchClause represents a case of a match statement.
+type MatchClause struct {
+	Case  token.Pos // position of "case" or "default" keyword
+	List  []Expr    // list of patterns; nil means default case
+	If    token.Pos // position of "if" keyword; or NoPos
+	Guard Expr      // guard condition; or nil
+	Colon token.Pos // position of ":"
+	Body  []Stmt    // statement list; or nil
+}
+
+// Pos - position of first character belonging to the node.
+func (p *MatchClause) Pos() token.Pos {
+	return p.Case
+}
+
+// End - position of first character immediately after the node.
+func (p *MatchClause) End() token.Pos {
+	if n := len(p.Body); n > 0 {
+		return p.Body[n-1].End()
+	}
+	return p.Colon + 1
+}
+
+func (*MatchClause) stmtNode() {}
+
+// -----------------------------------------------------------------------------
+
 // A SendStmt node represents a send statement.
 type SendStmt struct {
 	Chan     Expr
@@ -130,6 +130,14 @@ func (obj *Object) Pos() token.Pos {
 		if d.Label.Name == name {
 			return d.Label.Pos()
 		}
+	case *EnumDecl:
+		if d.Name.Name == name {
+			return d.Name.Pos()
+		}
+	case *EnumVariant:
+		if d.Name.Name == name {
+			return d.Name.Pos()
+		}
 	case *AssignStmt:
 		for _, x := range d.Lhs {
 			if ident, isIdent := x.(*Ident); isIdent && ident.Name == name {
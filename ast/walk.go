@@ -417,6 +417,9 @@ func Walk(v Visitor, node Node) {
 		if n.Value != nil {
 			Walk(v, n.Value)
 		}
+		if n.Tuple != nil {
+			Walk(v, n.Tuple)
+		}
 		if n.Init != nil {
 			Walk(v, n.Init)
 		}
@@ -435,6 +438,17 @@ func Walk(v Visitor, node Node) {
 		Walk(v, n.ForPhrase)
 		Walk(v, n.Body)
 
+	case *MatchStmt:
+		Walk(v, n.X)
+		Walk(v, n.Body)
+
+	case *MatchClause:
+		walkList(v, n.List)
+		if n.Guard != nil {
+			Walk(v, n.Guard)
+		}
+		walkList(v, n.Body)
+
 	case *RangeExpr:
 		if n.First != nil {
 			Walk(v, n.First)
@@ -452,6 +466,12 @@ func Walk(v Visitor, node Node) {
 			Walk(v, n.Default)
 		}
 
+	case *OptChainExpr:
+		Walk(v, n.X)
+
+	case *GoExpr:
+		Walk(v, n.X)
+
 	case *OverloadFuncDecl:
 		if n.Doc != nil {
 			Walk(v, n.Doc)
@@ -462,6 +482,25 @@ func Walk(v Visitor, node Node) {
 		Walk(v, n.Name)
 		walkList(v, n.Funcs)
 
+	case *EnumDecl:
+		if n.Doc != nil {
+			Walk(v, n.Doc)
+		}
+		Walk(v, n.Name)
+		walkList(v, n.Variants)
+
+	case *EnumVariant:
+		if n.Doc != nil {
+			Walk(v, n.Doc)
+		}
+		Walk(v, n.Name)
+		if n.Fields != nil {
+			Walk(v, n.Fields)
+		}
+		if n.Comment != nil {
+			Walk(v, n.Comment)
+		}
+
 	case *EnvExpr:
 		Walk(v, n.Name)
 
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/goplus/lib/c"
+	"github.com/qiniu/x/stringutil"
+	"strconv"
+)
+
+func main() {
+	name := "XGo"
+	n := 3
+	c.Printf(c.AllocaCStr(stringutil.Concat("Hello, ", name, "!\n")))
+	c.Printf(c.AllocaCStr(stringutil.Concat(name, " has ", strconv.Itoa(n), " letters, price: $", "1\n")))
+	c.Printf(c.Str("100$\n"))
+}
@@ -0,0 +1,69 @@
+package main
+
+import "fmt"
+
+type Point struct {
+	X_0 int
+	X_1 int
+}
+type Range struct {
+	X_0 float64
+	X_1 float64
+}
+
+func bounds() (int, Range) {
+	return 3, Range{0.5, 1.5}
+}
+func main() {
+	pt := Point{1, 2}
+	_xgo_t := pt
+	x := _xgo_t.X_0
+	y := _xgo_t.X_1
+	fmt.Println(x, y)
+	n, r := bounds()
+	_xgo_t1 := r
+	lo := _xgo_t1.X_0
+	fmt.Println(n, lo)
+	_xgo_t2 := struct {
+		X_0 string
+		X_1 struct {
+			X_0 int
+			X_1 float64
+		}
+	}{"a", struct {
+		X_0 int
+		X_1 float64
+	}{1, 2.5}}
+	a := _xgo_t2.X_0
+	b := _xgo_t2.X_1.X_0
+	c := _xgo_t2.X_1.X_1
+	fmt.Println(a, b, c)
+	pairs := []struct {
+		X_0 string
+		X_1 int
+	}{struct {
+		X_0 string
+		X_1 int
+	}{"a", 1}, struct {
+		X_0 string
+		X_1 int
+	}{"b", 2}}
+	for _, _xgo_v := range pairs {
+		k := _xgo_v.X_0
+		v := _xgo_v.X_1
+		fmt.Println(k, v)
+	}
+	for i, _xgo_v := range pairs {
+		k := _xgo_v.X_0
+		if i > 0 {
+			fmt.Println(i, k)
+		}
+	}
+	fmt.Println(func() (_xgo_ret []string) {
+		for _, _xgo_v := range pairs {
+			k := _xgo_v.X_0
+			_xgo_ret = append(_xgo_ret, k)
+		}
+		return
+	}())
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+// Color is a color.
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+func (v Color) String() string {
+	switch v {
+	case Red:
+		return "Red"
+	case Green:
+		return "Green"
+	case Blue:
+		return "Blue"
+	}
+	return "Color(" + strconv.Itoa(int(v)) + ")"
+}
+func (v Color) MarshalJSON() ([]byte, error) {
+	switch v {
+	case Red, Green, Blue:
+		return json.Marshal(v.String())
+	}
+	return nil, fmt.Errorf("invalid Color: %d", int(v))
+}
+func (v *Color) UnmarshalJSON(data []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(data, &s); err == nil {
+		*v, err = ParseColor(s)
+	}
+	return
+}
+func ParseColor(s string) (Color, error) {
+	switch s {
+	case "Red":
+		return Red, nil
+	case "Green":
+		return Green, nil
+	case "Blue":
+		return Blue, nil
+	}
+	return 0, fmt.Errorf("invalid Color: %q", s)
+}
+func name(c Color) string {
+	switch _xgo_m := c; {
+	case _xgo_m == Red:
+		return "warm"
+	case _xgo_m == Green || _xgo_m == Blue:
+		return "cool"
+	}
+	return ""
+}
+func main() {
+	c, err := ParseColor("Green")
+	fmt.Println(c, err, name(c), Color(5))
+}
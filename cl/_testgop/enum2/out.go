@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Shape interface {
+	isShape()
+}
+type Circle struct {
+	X_0 float64
+}
+type Rect struct {
+	X_0 float64
+	X_1 float64
+}
+type Empty struct {
+}
+
+func (Circle) isShape() {
+}
+func (v Circle) String() string {
+	return fmt.Sprintf("Circle(%v)", v.X_0)
+}
+func (v Circle) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]any{"Circle": []any{v.X_0}})
+}
+func (Rect) isShape() {
+}
+func (v Rect) String() string {
+	return fmt.Sprintf("Rect(%v, %v)", v.X_0, v.X_1)
+}
+func (v Rect) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]any{"Rect": []any{v.X_0, v.X_1}})
+}
+func (Empty) isShape() {
+}
+func (v Empty) String() string {
+	return "Empty"
+}
+func (v Empty) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string][]any{"Empty": []any{}})
+}
+func UnmarshalShape(data []byte) (Shape, error) {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	if args, ok := m["Circle"]; ok {
+		var v Circle
+		err := json.Unmarshal(args, &[]any{&v.X_0})
+		return v, err
+	}
+	if args, ok := m["Rect"]; ok {
+		var v Rect
+		err := json.Unmarshal(args, &[]any{&v.X_0, &v.X_1})
+		return v, err
+	}
+	if args, ok := m["Empty"]; ok {
+		var v Empty
+		err := json.Unmarshal(args, &[]any{})
+		return v, err
+	}
+	return nil, fmt.Errorf("invalid Shape: %s", data)
+}
+func area(s Shape) float64 {
+	switch s := s.(type) {
+	case Circle:
+		return 3.14 * s.X_0 * s.X_0
+	case Rect:
+		return s.X_0 * s.X_1
+	}
+	return 0
+}
+func main() {
+	shapes := []Shape{Circle{1}, Rect{2, 3}, Empty{}}
+	for _, s := range shapes {
+		fmt.Println(s, area(s))
+	}
+	s, err := UnmarshalShape([]byte(`{"Rect":[1,2]}`))
+	fmt.Println(s, err)
+}
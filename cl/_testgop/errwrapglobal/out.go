@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"github.com/goplus/xgo/encoding/regexp"
+	"github.com/qiniu/x/errors"
+	"os"
+	"strconv"
+)
+
+func load() (map[string]int, error) {
+	return map[string]int{"port": port}, nil
+}
+
+var port = func() (_xgo_ret int) {
+	var _xgo_err error
+	_xgo_ret, _xgo_err = strconv.Atoi("8080")
+	if _xgo_err != nil {
+		_xgo_err = errors.NewFrame(_xgo_err, "strconv.Atoi(\"8080\")", "cl/_testgop/errwrapglobal/in.xgo", 11, "main.init")
+		panic(_xgo_err)
+	}
+	return
+}()
+var cfg = func() (_xgo_ret map[string]int) {
+	var _xgo_err error
+	_xgo_ret, _xgo_err = load()
+	if _xgo_err != nil {
+		_xgo_err = errors.NewFrame(_xgo_err, "load()", "cl/_testgop/errwrapglobal/in.xgo", 10, "main.init")
+		panic(_xgo_err)
+	}
+	return
+}()
+var debug = func() (_xgo_ret bool) {
+	var _xgo_err error
+	_xgo_ret, _xgo_err = strconv.ParseBool(os.Getenv("XGO_DEBUG"))
+	if _xgo_err != nil {
+		return false
+	}
+	return
+}()
+var re = func() (_xgo_ret regexp.Object) {
+	var _xgo_err error
+	_xgo_ret, _xgo_err = regexp.New(`^[a-z]+$`)
+	if _xgo_err != nil {
+		_xgo_err = errors.NewFrame(_xgo_err, "regexp`^[a-z]+$`", "cl/_testgop/errwrapglobal/in.xgo", 13, "main.init")
+		panic(_xgo_err)
+	}
+	return
+}()
+
+func main() {
+	fmt.Println(cfg, port, debug, re.MatchString("xgo"))
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/goplus/xgo/parallel"
+	"strconv"
+)
+
+func square(ctx context.Context, n int) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return n * n, nil
+}
+func check(s string) error {
+	_, err := strconv.Atoi(s)
+	return err
+}
+func main() {
+	results, err := parallel.Run(func(ctx context.Context) (_xgo_ret []func() (int, error)) {
+		for _, n := range []int{1, 2, 3} {
+			_xgo_ret = append(_xgo_ret, func() (int, error) {
+				return square(ctx, n)
+			})
+		}
+		return
+	})
+	fmt.Println(results, err)
+	fmt.Println(parallel.Wait(func(_ context.Context) (_xgo_ret []func() error) {
+		for _, s := range []string{"1", "x"} {
+			if s != "" {
+				_xgo_ret = append(_xgo_ret, func() error {
+					return check(s)
+				})
+			}
+		}
+		return
+	}))
+	fmt.Println(parallel.Map(func(_ context.Context) (_xgo_ret []func() string) {
+		_xgo_ret = append(_xgo_ret, func() string {
+			return strconv.Itoa(100)
+		}, func() string {
+			return "xgo"
+		})
+		return
+	}))
+	parallel.Run(func(_ context.Context) (_xgo_ret []func() (int, error)) {
+		_xgo_ret = append(_xgo_ret, func() (int, error) {
+			return fmt.Println("hello")
+		}, func() (int, error) {
+			return fmt.Println("world")
+		})
+		return
+	})
+}
@@ -0,0 +1,42 @@
+package main
+
+import "fmt"
+
+type Point struct {
+	x int
+	y int
+}
+
+func keys(pt struct {
+	X_0 int
+	X_1 int
+}) {
+	switch _xgo_m := pt; {
+	case _xgo_m.X_1 == 0:
+		x := _xgo_m.X_0
+		_ = x
+		fmt.Println(Point{x: 1})
+	case _xgo_m.X_0 == 0:
+		y := _xgo_m.X_1
+		fmt.Println(Point{x: 1, y: y})
+	case true:
+		x := _xgo_m.X_0
+		_ = x
+		y := _xgo_m.X_1
+		fmt.Println(map[int]int{x: y})
+	}
+}
+func main() {
+	keys(struct {
+		X_0 int
+		X_1 int
+	}{2, 0})
+	keys(struct {
+		X_0 int
+		X_1 int
+	}{0, 3})
+	keys(struct {
+		X_0 int
+		X_1 int
+	}{4, 5})
+}
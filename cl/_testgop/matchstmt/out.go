@@ -0,0 +1,69 @@
+package main
+
+import "fmt"
+
+type Point struct {
+	X_0 int
+	X_1 int
+}
+type Shape struct {
+	Kind string
+	Pos  Point
+}
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+func where(pt Point) string {
+	switch _xgo_m := pt; {
+	case _xgo_m.X_0 == 0 && _xgo_m.X_1 == 0:
+		return "origin"
+	case _xgo_m.X_0 == 0:
+		y := _xgo_m.X_1
+		return fmt.Sprint("on y axis at ", y)
+	case _xgo_m.X_1 == 0:
+		x := _xgo_m.X_0
+		return fmt.Sprint("on x axis at ", x)
+	case _xgo_m.X_0 == _xgo_m.X_1:
+		return "on diagonal"
+	}
+	return "elsewhere"
+}
+func name(c Color) string {
+	switch _xgo_m := c; {
+	case _xgo_m == Red:
+		return "red"
+	case _xgo_m == Green || _xgo_m == Blue:
+		return "green or blue"
+	}
+	return ""
+}
+func describe(s Shape) {
+	switch _xgo_m := s; {
+	case _xgo_m.Kind == "circle":
+		x := _xgo_m.Pos.X_0
+		y := _xgo_m.Pos.X_1
+		fmt.Println("circle at", x, y)
+	case _xgo_m.Kind != "":
+		kind := _xgo_m.Kind
+		fmt.Println(kind)
+	default:
+		fmt.Println("unknown shape")
+	}
+}
+func main() {
+	fmt.Println(where(Point{0, 3}), where(Point{2, 2}))
+	fmt.Println(name(Blue))
+	describe(Shape{Kind: "circle", Pos: Point{1, 2}})
+	switch _xgo_m := len(where(Point{1, 0})); {
+	case _xgo_m == 0:
+		fmt.Println("empty")
+	case true:
+		n := _xgo_m
+		fmt.Println(n)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"github.com/goplus/xgo/mat"
+)
+
+func rotate() mat.Dense {
+	return mat.Dense_Cast([][]float64{[]float64{0, -1}, []float64{1, 0}})
+}
+func norm(m mat.Dense) float64 {
+	r, c := m.Dims()
+	sum := 0.0
+	for i := 0; i < r; i += 1 {
+		for j := 0; j < c; j += 1 {
+			sum += m.At(i, j) * m.At(i, j)
+		}
+	}
+	return sum
+}
+func main() {
+	row := []int{4, 5, 6}
+	a := [][]float64{[]float64{1, 2, 3}, []float64{4, 5.5, 6}}
+	b := [][]int{[]int{1, 2, 3}, append([]int{}, row...), append(append([]int{0}, row...), 7)}
+	fmt.Println(a, b)
+	var m mat.Dense = mat.Dense_Cast([][]float64{[]float64{1, 2}, []float64{3, 4}})
+	m = (mat.Dense).XGo_Sub((mat.Dense).XGo_Add((mat.Dense).XGo_Mul__0(m, rotate()), m.MulElem(m)), (mat.Dense).XGo_Mul__1(m, 2))
+	fmt.Println(m, (mat.Dense).XGo_Quo(m.XGo_Neg(), 2), (mat.Dense).XGo_EQ(m, m.T()))
+	fmt.Println(norm(mat.Dense_Cast([][]float64{[]float64{1, 2}, []float64{3, 4}})))
+}
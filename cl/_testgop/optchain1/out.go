@@ -0,0 +1,120 @@
+package main
+
+import "fmt"
+
+type Server struct {
+	Host string
+	Port int
+}
+type Config struct {
+	Servers []*Server
+	Env     map[string]string
+	Owner   *User
+}
+type User struct {
+	Name string
+}
+
+func (u *User) Hello() {
+	fmt.Println("hello", u.Name)
+}
+
+var cfg *Config
+
+func main() {
+	fmt.Println(func() (_xgo_ret int) {
+		if _xgo_1 := cfg; _xgo_1 != nil {
+			if _xgo_2, _xgo_3 := _xgo_1.Servers, 0; _xgo_3 >= 0 && _xgo_3 < len(_xgo_2) {
+				if _xgo_4 := _xgo_2[_xgo_3]; _xgo_4 != nil {
+					_xgo_ret = _xgo_4.Port
+					return
+				}
+			}
+		}
+		return 8080
+	}())
+	if _xgo_1 := cfg; _xgo_1 != nil {
+		if _xgo_2 := _xgo_1.Owner; _xgo_2 != nil {
+			_xgo_2.Hello()
+		}
+	}
+	cfg = &Config{Servers: []*Server{&Server{Host: "localhost", Port: 80}}, Env: map[string]string{"HOME": "/root"}}
+	fmt.Println(func() (_xgo_ret int) {
+		if _xgo_1 := cfg; _xgo_1 != nil {
+			if _xgo_2, _xgo_3 := _xgo_1.Servers, 0; _xgo_3 >= 0 && _xgo_3 < len(_xgo_2) {
+				if _xgo_4 := _xgo_2[_xgo_3]; _xgo_4 != nil {
+					_xgo_ret = _xgo_4.Port
+					return
+				}
+			}
+		}
+		return 8080
+	}(), func() (_xgo_ret string) {
+		if _xgo_1 := cfg; _xgo_1 != nil {
+			if _xgo_2, _xgo_3 := _xgo_1.Servers, 1; _xgo_3 >= 0 && _xgo_3 < len(_xgo_2) {
+				if _xgo_4 := _xgo_2[_xgo_3]; _xgo_4 != nil {
+					_xgo_ret = _xgo_4.Host
+				}
+			}
+		}
+		return
+	}())
+	fmt.Println(func() (_xgo_ret string) {
+		if _xgo_1 := cfg; _xgo_1 != nil {
+			if _xgo_2, _xgo_ok := _xgo_1.Env["HOME"]; _xgo_ok {
+				_xgo_ret = _xgo_2
+				return
+			}
+		}
+		return "/"
+	}(), func() (_xgo_ret string) {
+		if _xgo_1 := cfg; _xgo_1 != nil {
+			if _xgo_2, _xgo_ok := _xgo_1.Env["USER"]; _xgo_ok {
+				_xgo_ret = _xgo_2
+				return
+			}
+		}
+		return "nobody"
+	}())
+	fmt.Println(func() (_xgo_ret *User) {
+		_xgo_ret = cfg.Owner
+		if _xgo_ret != nil {
+			return
+		}
+		return &User{Name: "guest"}
+	}().Name)
+	var i interface{} = cfg
+	fmt.Println(func() (_xgo_ret string) {
+		if _xgo_1 := i; _xgo_1 != nil {
+			if _xgo_2, _xgo_ok := _xgo_1.(*Config); _xgo_ok && _xgo_2 != nil {
+				if _xgo_3, _xgo_ok := _xgo_2.Env["HOME"]; _xgo_ok {
+					_xgo_ret = _xgo_3
+				}
+			}
+		}
+		return
+	}())
+	var j interface{} = (*Config)(nil)
+	var k interface{} = 1
+	fmt.Println(func() (_xgo_ret string) {
+		if _xgo_1 := j; _xgo_1 != nil {
+			if _xgo_2, _xgo_ok := _xgo_1.(*Config); _xgo_ok && _xgo_2 != nil {
+				if _xgo_3, _xgo_ok := _xgo_2.Env["HOME"]; _xgo_ok {
+					_xgo_ret = _xgo_3
+					return
+				}
+			}
+		}
+		return "nil"
+	}(), func() (_xgo_ret string) {
+		if _xgo_1 := k; _xgo_1 != nil {
+			if _xgo_2, _xgo_ok := _xgo_1.(*Config); _xgo_ok && _xgo_2 != nil {
+				if _xgo_3, _xgo_ok := _xgo_2.Env["HOME"]; _xgo_ok {
+					_xgo_ret = _xgo_3
+					return
+				}
+			}
+		}
+		return "int"
+	}())
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"github.com/qiniu/x/errors"
+)
+
+type File struct {
+	name string
+}
+
+func (f *File) Name() string {
+	return f.name
+}
+func open(name string) (*File, error) {
+	return &File{name}, nil
+}
+func show(name string) error {
+	var _autoGo_1 *File
+	{
+		var _xgo_err error
+		_autoGo_1, _xgo_err = open(name)
+		if _xgo_err != nil {
+			_xgo_err = errors.NewFrame(_xgo_err, "open(name)", "cl/_testgop/optchain2/in.xgo", 14, "main.show")
+			return _xgo_err
+		}
+		goto _autoGo_2
+	_autoGo_2:
+	}
+	fmt.Println(_autoGo_1.Name())
+	var _autoGo_3 *File
+	{
+		var _xgo_err error
+		_autoGo_3, _xgo_err = open(name)
+		if _xgo_err != nil {
+			_xgo_err = errors.NewFrame(_xgo_err, "open(name)", "cl/_testgop/optchain2/in.xgo", 15, "main.show")
+			return _xgo_err
+		}
+		goto _autoGo_4
+	_autoGo_4:
+	}
+	_autoGo_3.Name()
+	var f *File
+	fmt.Println(func() (_xgo_ret string) {
+		if _xgo_1 := f; _xgo_1 != nil {
+			_xgo_ret = _xgo_1.Name()
+			return
+		}
+		return "-"
+	}())
+	return nil
+}
+func main() {
+	show("hello")
+}
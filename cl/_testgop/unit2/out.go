@@ -0,0 +1,18 @@
+package main
+
+import (
+	"fmt"
+	"github.com/goplus/xgo/cl/internal/unit"
+)
+
+func travel(d unit.Distance, t unit.Time) unit.Speed {
+	return unit.Speed(float64(d) / float64(t))
+}
+func main() {
+	d := unit.Distance(1000000) + 300000
+	speed := unit.Speed(5)
+	area := unit.Area(6000000)
+	var t unit.Time = 60000
+	fmt.Println(d, speed, area, t, travel(d, 120000))
+	fmt.Println(d > 500000, unit.Time(3600000)/1800000)
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/goplus/lib/py"
+	"github.com/goplus/lib/py/std"
+	"github.com/qiniu/x/stringutil"
+)
+
+func main() {
+	name := "XGo"
+	std.Print(py.FromGoString(stringutil.Concat("Hello, ", name, "!")))
+	std.Print(py.Str("Cost: $5"))
+	s := py.FromGoString(name)
+	std.Print(s)
+}
@@ -229,12 +229,9 @@ func TestErrPreloadFile(t *testing.T) {
 }
 
 func TestErrParseTypeEmbedName(t *testing.T) {
-	defer func() {
-		if e := recover(); e == nil {
-			t.Fatal("TestErrParseTypeEmbedName: no panic?")
-		}
-	}()
-	parseTypeEmbedName(&ast.StructType{})
+	if parseTypeEmbedName(&ast.StructType{}) != nil {
+		t.Fatal("TestErrParseTypeEmbedName: not nil?")
+	}
 }
 
 func TestGmxCheckProjs(t *testing.T) {
@@ -325,6 +322,8 @@ func TestFileClassType(t *testing.T) {
 		{true, true, false, "abc_test.gox", "case_abc", true, true},
 		{true, true, false, "Abc_test.gox", "caseAbc", true, true},
 		{true, true, false, "main_test.gox", "case_main", true, true},
+		{true, true, false, "Fib_bench_test.gox", "benchFib", true, true},
+		{true, true, false, "abc_fuzz_test.gox", "fuzz_abc", true, true},
 
 		{true, false, false, "get.yap", "get", false, true},
 		{true, false, false, "get_p_#id.yap", "get_p_id", false, true},
@@ -340,6 +339,8 @@ func TestFileClassType(t *testing.T) {
 
 		{true, false, false, "abc_ytest.gox", "case_abc", true, true},
 		{true, false, false, "Abc_ytest.gox", "caseAbc", true, true},
+		{true, false, false, "foo_bench_ytest.gox", "case_foo_bench", true, true},
+		{true, false, false, "foo_fuzz_xtest.gox", "fuzz_foo", true, true},
 		{true, false, true, "main_ytest.gox", "App", true, true},
 	}
 	lookupClass := func(ext string) (c *Project, ok bool) {
@@ -353,6 +354,11 @@ func TestFileClassType(t *testing.T) {
 			return &modfile.Project{
 				Ext: "_yap.gox", Class: "App",
 				PkgPaths: []string{"github.com/goplus/yap"}}, true
+		case "_xtest.gox":
+			return &modfile.Project{
+				Ext: "_xtest.gox", Class: "App",
+				Works:    []*modfile.Class{{Ext: "_xtest.gox", Class: "Case"}},
+				PkgPaths: []string{"github.com/goplus/xgo/test", "testing"}}, true
 		case "_ytest.gox":
 			return &modfile.Project{
 				Ext: "_ytest.gox", Class: "App",
@@ -424,36 +430,22 @@ func TestErrPanicToRecv(t *testing.T) {
 	}()
 }
 
-func TestCompileErrWrapExpr(t *testing.T) {
-	defer func() {
-		if e := recover(); e != "TODO: can't use expr? in global" {
-			t.Fatal("TestCompileErrWrapExpr failed")
-		}
-	}()
-	pkg := gogen.NewPackage("", "foo", goxConf)
-	ctx := &blockCtx{pkg: pkg, cb: pkg.CB()}
-	compileErrWrapExpr(ctx, 0, &ast.ErrWrapExpr{Tok: token.QUESTION}, 0)
-}
-
 func TestToString(t *testing.T) {
 	defer func() {
 		if e := recover(); e == nil {
 			t.Fatal("toString: no error?")
 		}
 	}()
-	toString(&ast.BasicLit{Kind: token.INT, Value: "1"})
+	toString(&blockCtx{pkgCtx: &pkgCtx{}}, &ast.BasicLit{Kind: token.INT, Value: "1"})
 }
 
 func TestGetTypeName(t *testing.T) {
-	if getTypeName(types.Typ[types.Int]) != "int" {
+	if name, ok := getTypeName(types.Typ[types.Int]); !ok || name != "int" {
 		t.Fatal("getTypeName int failed")
 	}
-	defer func() {
-		if e := recover(); e == nil {
-			t.Fatal("getTypeName: no error?")
-		}
-	}()
-	getTypeName(types.NewSlice(types.Typ[types.Int]))
+	if _, ok := getTypeName(types.NewSlice(types.Typ[types.Int])); ok {
+		t.Fatal("getTypeName: no error?")
+	}
 }
 
 func TestHandleRecover(t *testing.T) {
@@ -622,10 +614,13 @@ func TestErrNewType(t *testing.T) {
 }
 
 func TestErrCompileBasicLit(t *testing.T) {
-	testPanic(t, "compileBasicLit: invalid syntax\n", func() {
-		ctx := &blockCtx{cb: new(gogen.CodeBuilder)}
-		compileBasicLit(ctx, &ast.BasicLit{Kind: token.CSTRING, Value: `\\x`})
-	})
+	defer func() {
+		if e, ok := recover().(*gogen.CodeError); !ok || e.Msg != `invalid string literal \\x: invalid syntax` {
+			t.Fatal("TestErrCompileBasicLit:", e)
+		}
+	}()
+	ctx := &blockCtx{cb: new(gogen.CodeBuilder), pkgCtx: &pkgCtx{}}
+	compileBasicLit(ctx, &ast.BasicLit{Kind: token.CSTRING, Value: `\\x`})
 }
 
 func testPanic(t *testing.T, panicMsg string, doPanic func()) {
@@ -42,6 +42,7 @@ type gmxClass struct {
 	ext     string
 	proj    *gmxProject
 	sp      *spxObj
+	test    *testKind // kind of a test work class
 }
 
 func (p *gmxClass) getName(ctx *pkgCtx) string {
@@ -236,7 +237,14 @@ func GetFileClassType(file *ast.File, filename string, lookupClass func(ext stri
 				classType = gt.Class
 			}
 		} else if isTest {
-			classType = casePrefix + testNameSuffix(classType)
+			pkgPath := testPkgPath // a normal _test.gox file of the XGo test framework
+			if !file.IsNormalGox {
+				pkgPath = ""
+				if gt, ok := lookupClass(ext); ok {
+					pkgPath = gt.PkgPaths[0]
+				}
+			}
+			classType = testClassType(pkgPath, classType)
 		}
 	} else if strings.HasSuffix(filename, "_test.xgo") || strings.HasSuffix(filename, "_test.gop") {
 		isTest = true
@@ -317,6 +325,9 @@ func loadClass(ctx *pkgCtx, pkg *gogen.Package, file string, f *ast.File, conf *
 		}
 	} else {
 		sp := getSpxObj(p, ext)
+		if p.isTest {
+			sp, tname, cls.test = p.testClass(sp, tname)
+		}
 		tname := spName(sp, tname)
 		sp.types = append(sp.types, tname)
 		cls.sp = sp
@@ -406,7 +417,8 @@ func setBodyHandler(ctx *blockCtx) {
 }
 
 const (
-	casePrefix = "case"
+	casePrefix  = "case"
+	testPkgPath = "github.com/goplus/xgo/test"
 )
 
 func testNameSuffix(testType string) string {
@@ -416,12 +428,65 @@ func testNameSuffix(testType string) string {
 	return "_" + testType
 }
 
-func gmxTestFunc(pkg *gogen.Package, testType string, isProj bool) {
-	if isProj {
+// testKind represents a kind of work classes of a test classfile. A work class
+// of the XGo test framework whose name ends with the suffix of a kind (eg.
+// Fib_bench_test.gox) is based on the class of the kind (eg. Bench).
+type testKind struct {
+	suffix    string // suffix of class name
+	class     string // work base class
+	prefix    string // prefix of class type
+	fn        string // prefix of testing function
+	param     string // parameter of testing function
+	paramType string // type of the parameter, in package testing
+}
+
+var (
+	caseKind  = testKind{"", "Case", casePrefix, "Test", "t", "T"}
+	testKinds = [...]testKind{
+		{"_bench", "Bench", "bench", "Benchmark", "b", "B"},
+		{"_fuzz", "Fuzz", "fuzz", "Fuzz", "f", "F"},
+	}
+)
+
+// testKindOf returns the kind of a test work class named name of the class
+// framework whose package is pkgPath, and the name without the suffix of the
+// kind. Only the XGo test framework has kinds other than Case.
+func testKindOf(pkgPath, name string) (string, *testKind) {
+	if pkgPath == testPkgPath {
+		for i := range testKinds {
+			kind := &testKinds[i]
+			if tname, ok := strings.CutSuffix(name, kind.suffix); ok && tname != "" {
+				return tname, kind
+			}
+		}
+	}
+	return name, &caseKind
+}
+
+// testClass returns the base class, the name and the kind of a test work
+// class named name.
+func (p *gmxProject) testClass(sp *spxObj, name string) (*spxObj, string, *testKind) {
+	tname, kind := testKindOf(p.pkgPaths[0], name)
+	if kind == &caseKind {
+		return sp, name, kind
+	}
+	obj, _ := spxRef(p.pkgImps[0], kind.class)
+	return &spxObj{obj: obj, ext: sp.ext}, tname, kind
+}
+
+// testClassType returns the class type of a test work class named name of the
+// class framework whose package is pkgPath.
+func testClassType(pkgPath, name string) string {
+	tname, kind := testKindOf(pkgPath, name)
+	return kind.prefix + testNameSuffix(tname)
+}
+
+func gmxTestFunc(pkg *gogen.Package, testType string, kind *testKind) {
+	if kind == nil { // project class
 		genTestFunc(pkg, "TestMain", testType, "m", "M")
 	} else {
 		name := testNameSuffix(testType)
-		genTestFunc(pkg, "Test"+name, casePrefix+name, "t", "T")
+		genTestFunc(pkg, kind.fn+name, kind.prefix+name, kind.param, kind.paramType)
 	}
 }
 
@@ -23,7 +23,6 @@ import (
 	gotoken "go/token"
 	"go/types"
 	"log"
-	"reflect"
 	"sort"
 	"strconv"
 	"strings"
@@ -205,6 +204,10 @@ type Config struct {
 
 	// Outline = true means to skip compiling function bodies.
 	Outline bool
+
+	// Warning is called to report a warning, eg. a non-exhaustive match
+	// statement (optional).
+	Warning func(err error)
 }
 
 type nodeInterp struct {
@@ -353,6 +356,13 @@ type pkgCtx struct {
 	inits    []func()
 	tylds    []*typeLoader
 	errs     errors.List
+	warn     func(err error)
+	variants map[*ast.TupleType]none // tuple types of sum type variants
+	units    map[*types.TypeName]*unitType
+	optVals  map[ast.Expr]optChainVal // links of optional chains being compiled
+
+	errWrapOpts map[*ast.OptChainExpr]bool // f()?.sel: is f()? an error wrapping expression
+	optChecked  map[ast.Expr]none          // operands of chains checked by isOptChain
 
 	generics map[string]bool // generic type record
 	idents   []*ast.Ident    // toType ident recored
@@ -387,6 +397,11 @@ type blockCtx struct {
 	fileScope *types.Scope // available when isXGoFile
 	rec       *goxRecorder
 
+	guardBinds map[string]*matchVal // available when compiling a guard of match statement
+
+	initFn *gogen.Func // available when inInit
+	inInit bool        // is compiling a package-level var initializer or not
+
 	fileLine  bool
 	isClass   bool
 	isXgoFile bool // is XGo file or not
@@ -433,6 +448,12 @@ func (p *pkgCtx) handleErrorf(pos, end token.Pos, format string, args ...any) {
 	p.handleErr(p.newCodeErrorf(pos, end, format, args...))
 }
 
+func (p *pkgCtx) warnf(pos, end token.Pos, format string, args ...any) {
+	if p.warn != nil {
+		p.warn(p.newCodeErrorf(pos, end, format, args...))
+	}
+}
+
 func (p *pkgCtx) handleErr(err error) {
 	p.errs = append(p.errs, err)
 }
@@ -581,6 +602,7 @@ func NewPackage(pkgPath string, pkg *ast.Package, conf *Config) (p *gogen.Packag
 	ctx := &pkgCtx{
 		fset:       fset,
 		nodeInterp: interp,
+		warn:       conf.Warning,
 		projs:      make(map[string]*gmxProject),
 		classes:    make(map[*ast.File]*gmxClass),
 		overpos:    make(map[string]token.Pos),
@@ -774,6 +796,8 @@ func loadFile(ctx *pkgCtx, f *ast.File) {
 					}
 				}
 			}
+		case *ast.EnumDecl:
+			loadEnumDecl(ctx, d)
 		case *ast.FuncDecl:
 			if d.Recv == nil {
 				name := d.Name.Name
@@ -827,7 +851,7 @@ func preloadXGoFile(p *gogen.Package, ctx *blockCtx, file string, f *ast.File, c
 			if goxTestFile { // test classfile
 				testType = classType
 				if !f.IsProj {
-					classType = casePrefix + testNameSuffix(testType)
+					classType = c.test.prefix + testNameSuffix(testType)
 				}
 			}
 			if f.IsProj {
@@ -890,7 +914,7 @@ func preloadXGoFile(p *gogen.Package, ctx *blockCtx, file string, f *ast.File, c
 							typ := toType(ctx, &ast.Ident{Name: gameClass})
 							getUnderlying(ctx, typ) // ensure type is loaded
 							typ = types.NewPointer(typ)
-							name := getTypeName(typ)
+							name, _ := getTypeName(typ)
 							if !chk.chkRedecl(ctx, name, pos, end, fieldKindClass) {
 								fld := types.NewField(pos, pkg, name, typ, true)
 								flds = append(flds, fld)
@@ -911,7 +935,10 @@ func preloadXGoFile(p *gogen.Package, ctx *blockCtx, file string, f *ast.File, c
 					defs := p.ClassDefsStart(recv, func(idx int, name string, typ types.Type, embed bool) {
 						var id *ast.Ident
 						if embed {
-							id = parseTypeEmbedName(spec.Type)
+							if id = parseTypeEmbedName(spec.Type); id == nil {
+								ctx.handleErrorf(spec.Type.Pos(), spec.Type.End(), "invalid embedded field type %s", ctx.LoadExpr(spec.Type))
+								return
+							}
 						} else {
 							id = spec.Names[idx]
 						}
@@ -924,7 +951,7 @@ func preloadXGoFile(p *gogen.Package, ctx *blockCtx, file string, f *ast.File, c
 							rec.Def(id, fld)
 						}
 						flds = append(flds, fld)
-						tags = append(tags, toFieldTag(spec.Tag))
+						tags = append(tags, toFieldTag(ctx, spec.Tag))
 					})
 					for _, v := range classDecl.Specs {
 						var pos token.Pos
@@ -994,12 +1021,14 @@ func preloadXGoFile(p *gogen.Package, ctx *blockCtx, file string, f *ast.File, c
 	if goxTestFile {
 		parent.inits = append(parent.inits, func() {
 			old, _ := p.SetCurFile(testingGoFile, true)
-			gmxTestFunc(p, testType, f.IsProj)
+			gmxTestFunc(p, testType, c.test)
 			p.RestoreCurFile(old)
 		})
 	}
 }
 
+// parseTypeEmbedName returns the name of an embedded field, or nil if typ
+// isn't a (pointer to a) type name.
 func parseTypeEmbedName(typ ast.Expr) *ast.Ident {
 retry:
 	switch t := typ.(type) {
@@ -1011,7 +1040,7 @@ retry:
 		typ = t.X
 		goto retry
 	}
-	panic("TODO: parseTypeEmbedName unexpected")
+	return nil
 }
 
 func preloadFile(p *gogen.Package, ctx *blockCtx, f *ast.File, goFile string, genFnBody bool) {
@@ -1104,7 +1133,7 @@ func preloadFile(p *gogen.Package, ctx *blockCtx, f *ast.File, goFile string, ge
 		}
 	}
 
-	for _, decl := range f.Decls {
+	for _, decl := range expandEnumDecls(parent, f.Decls) {
 		switch d := decl.(type) {
 		case *ast.GenDecl:
 			switch d.Tok {
@@ -1206,12 +1235,15 @@ func preloadFile(p *gogen.Package, ctx *blockCtx, f *ast.File, goFile string, ge
 					})
 				}
 			default:
-				log.Panicln("TODO - tok:", d.Tok, "spec:", reflect.TypeOf(d.Specs).Elem())
+				ctx.handleErrorf(d.Pos(), d.End(), "unexpected %v declaration", d.Tok)
 			}
 
 		case *ast.FuncDecl:
 			preloadFuncDecl(d)
 
+		case *ast.EnumDecl:
+			preloadEnumDecl(p, ctx, d, goFile)
+
 		case *ast.OverloadFuncDecl:
 			var recv *ast.Ident
 			if ctx.classRecv != nil { // in class file (.spx/.gmx)
@@ -1593,7 +1625,7 @@ func loadImport(ctx *blockCtx, spec *ast.ImportSpec) {
 			}
 		}()
 	}
-	pkgPath := simplifyPkgPath(toString(spec.Path))
+	pkgPath := simplifyPkgPath(toString(ctx, spec.Path))
 	pkg := ctx.pkg.Import(pkgPath, spec)
 
 	var pos token.Pos
@@ -1681,6 +1713,9 @@ func loadVars(ctx *blockCtx, v *ast.ValueSpec, doc *ast.CommentGroup, global boo
 	}
 	varDefs := ctx.pkg.NewVarDefs(scope).SetComments(doc)
 	initExpr := makeInitExpr(ctx, v, typ, names)
+	if global && initExpr != nil {
+		initExpr = makeGlobalInitExpr(ctx, initExpr)
+	}
 	varDefs.NewAndInit(initExpr, v.Names[0].Pos(), typ, names...)
 	defNames(ctx, v.Names, scope)
 }
@@ -1710,6 +1745,10 @@ func makeInitExpr(ctx *blockCtx, v *ast.ValueSpec, typ types.Type, names []strin
 					compileSliceLit(ctx, e, typ)
 				case *ast.CompositeLit:
 					compileCompositeLit(ctx, e, typ, false)
+				case *ast.NumberUnitLit:
+					compileNumberUnitLit(ctx, e, typ)
+				case *ast.MatrixLit:
+					compileMatrixLit(ctx, e, typ)
 				default:
 					compileExpr(ctx, 1, val)
 				}
@@ -1719,6 +1758,20 @@ func makeInitExpr(ctx *blockCtx, v *ast.ValueSpec, typ types.Type, names []strin
 	}
 }
 
+// makeGlobalInitExpr marks initExpr as a package-level var initializer. Globals
+// are loaded lazily, so the initializer may be compiled while cb is still in
+// the body of another function.
+func makeGlobalInitExpr(ctx *blockCtx, initExpr gogen.F) gogen.F {
+	return func(cb *gogen.CodeBuilder) int {
+		initFn, inInit := ctx.initFn, ctx.inInit
+		ctx.initFn, ctx.inInit = cb.Func(), true
+		defer func() {
+			ctx.initFn, ctx.inInit = initFn, inInit
+		}()
+		return initExpr(cb)
+	}
+}
+
 func defNames(ctx *blockCtx, names []*ast.Ident, scope *types.Scope) {
 	if rec := ctx.recorder(); rec != nil {
 		if scope == nil {
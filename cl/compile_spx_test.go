@@ -1048,6 +1048,121 @@ func Test_foo(t *testing.T) {
 `, "main.gox", "foo_xtest.gox", "_test")
 }
 
+func TestTestClassFileBench(t *testing.T) {
+	gopSpxTestEx2(t, `
+println "Hi"
+`, `
+for b.loop {
+	println "x"
+}
+`, `package main
+
+import (
+	"fmt"
+	"github.com/goplus/xgo/test"
+	"testing"
+)
+
+type benchFib struct {
+	test.Bench
+}
+type App struct {
+	test.App
+}
+
+func (this *App) MainEntry() {
+	fmt.Println("Hi")
+}
+func (this *benchFib) Main() {
+	for this.B().Loop() {
+		fmt.Println("x")
+	}
+}
+func BenchmarkFib(b *testing.B) {
+	test.Gopt_Bench_TestMain(new(benchFib), b)
+}
+func TestMain(m *testing.M) {
+	test.Gopt_App_TestMain(new(App), m)
+}
+`, "main_xtest.gox", "Fib_bench_xtest.gox", "_test")
+}
+
+func TestTestClassFileFuzz(t *testing.T) {
+	gopSpxTestEx2(t, `
+println "Hi"
+`, `
+import "testing"
+
+f.add 1
+f.fuzz func(t *testing.T, a int) {
+	t.log a
+}
+`, `package main
+
+import (
+	"github.com/goplus/xgo/test"
+	"testing"
+)
+
+type fuzz_foo struct {
+	test.Fuzz
+}
+
+func (this *fuzz_foo) Main() {
+	this.F().Add(1)
+	this.F().Fuzz(func(t *testing.T, a int) {
+		t.Log(a)
+	})
+}
+func Fuzz_foo(f *testing.F) {
+	test.Gopt_Fuzz_TestMain(new(fuzz_foo), f)
+}
+`, "main.gox", "foo_fuzz_xtest.gox", "_test")
+}
+
+func TestTestClassFileHelpers(t *testing.T) {
+	gopSpxTestEx2(t, `
+println "Hi"
+`, `
+subtest "sub", => {
+	t.log "hi"
+}
+run "sub2", t => {
+	t.log "hi"
+}
+table map[string]int{"two": 2, "ten": 10}, func(v int) {
+	t.log v
+}
+golden "out", "hello"
+`, `package main
+
+import (
+	"github.com/goplus/xgo/test"
+	"testing"
+)
+
+type case_foo struct {
+	test.Case
+}
+
+func (this *case_foo) Main() {
+	this.Subtest("sub", func() {
+		this.T().Log("hi")
+	})
+	this.Run("sub2", func(t *testing.T) {
+		t.Log("hi")
+	})
+	test.Gopt_Case_Table(this, map[string]int{"two": 2, "ten": 10}, func(v int) {
+		this.T().Log(v)
+	})
+	this.Golden("out", "hello")
+}
+func Test_foo(t *testing.T) {
+	test.Gopt_Case_TestMain(new(case_foo), t)
+}
+`, "main.gox", "foo_xtest.gox", "_test")
+}
+
 func TestGoxNoFunc(t *testing.T) {
 	gopClTestFile(t, `
 var (
@@ -1373,7 +1373,7 @@ var ret int = func() (_xgo_ret int) {
 	var _xgo_err error
 	_xgo_ret, _xgo_err = fmt.Println("Hi")
 	if _xgo_err != nil {
-		_xgo_err = errors.NewFrame(_xgo_err, "println(\"Hi\")", "/foo/bar.xgo", 2, "main.main")
+		_xgo_err = errors.NewFrame(_xgo_err, "println(\"Hi\")", "/foo/bar.xgo", 2, "main.init")
 		panic(_xgo_err)
 	}
 	return
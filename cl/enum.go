@@ -0,0 +1,405 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cl
+
+import (
+	gotoken "go/token"
+	"go/types"
+	"strings"
+
+	"github.com/goplus/gogen"
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/token"
+)
+
+// -----------------------------------------------------------------------------
+
+// expandEnumDecls replaces each enum declaration in decls with the type, const
+// and func declarations it is lowered to. The enum declaration itself is kept
+// to generate its methods.
+//
+//	enum Color { Red; Green; Blue }
+//
+// is lowered to:
+//
+//	type Color int
+//
+//	const (
+//		Red Color = iota
+//		Green
+//		Blue
+//	)
+//
+// and
+//
+//	enum Shape { Circle(r float64); Empty }
+//
+// is lowered to:
+//
+//	type Shape interface { isShape() }
+//
+//	type (
+//		Circle (r float64)
+//		Empty ()
+//	)
+//
+//	func (Circle) isShape() {}
+//	func (Empty) isShape() {}
+func expandEnumDecls(ctx *pkgCtx, decls []ast.Decl) []ast.Decl {
+	var ret []ast.Decl
+	for i, decl := range decls {
+		if d, ok := decl.(*ast.EnumDecl); ok {
+			if ret == nil {
+				ret = append(make([]ast.Decl, 0, len(decls)+8), decls[:i]...)
+			}
+			ret = append(ret, enumDecls(ctx, d)...)
+			ret = append(ret, d)
+		} else if ret != nil {
+			ret = append(ret, decl)
+		}
+	}
+	if ret == nil {
+		return decls
+	}
+	return ret
+}
+
+func enumDecls(ctx *pkgCtx, d *ast.EnumDecl) []ast.Decl {
+	pos := d.Pos()
+	name := d.Name
+	if !d.IsSumType() {
+		typ := &ast.TypeSpec{Doc: d.Doc, Name: name, Type: &ast.Ident{NamePos: pos, Name: "int"}}
+		specs := make([]ast.Spec, len(d.Variants))
+		for i, v := range d.Variants {
+			spec := &ast.ValueSpec{Doc: v.Doc, Names: []*ast.Ident{v.Name}, Comment: v.Comment}
+			if i == 0 {
+				spec.Type = &ast.Ident{NamePos: pos, Name: name.Name}
+				spec.Values = []ast.Expr{&ast.Ident{NamePos: pos, Name: "iota"}}
+			}
+			specs[i] = spec
+		}
+		return []ast.Decl{
+			&ast.GenDecl{TokPos: pos, Tok: token.TYPE, Specs: []ast.Spec{typ}},
+			&ast.GenDecl{TokPos: pos, Tok: token.CONST, Lparen: d.Lbrace, Specs: specs, Rparen: d.Rbrace},
+		}
+	}
+	sealed := enumSealedMethod(name.Name)
+	iface := &ast.InterfaceType{
+		Interface: pos,
+		Methods: &ast.FieldList{List: []*ast.Field{{
+			Names: []*ast.Ident{{NamePos: pos, Name: sealed}},
+			Type:  &ast.FuncType{Func: token.NoPos, Params: &ast.FieldList{}},
+		}}},
+	}
+	typs := make([]ast.Spec, 1, len(d.Variants)+1)
+	typs[0] = &ast.TypeSpec{Doc: d.Doc, Name: name, Type: iface}
+	ret := make([]ast.Decl, 1, len(d.Variants)+1)
+	for _, v := range d.Variants {
+		fields := v.Fields
+		if fields == nil {
+			fields = &ast.FieldList{}
+		}
+		vpos := v.Name.Pos()
+		tuple := &ast.TupleType{Lparen: fields.Opening, Fields: fields, Rparen: fields.Closing}
+		if ctx.variants == nil {
+			ctx.variants = make(map[*ast.TupleType]none)
+		}
+		ctx.variants[tuple] = none{}
+		typs = append(typs, &ast.TypeSpec{Doc: v.Doc, Name: v.Name, Comment: v.Comment, Type: tuple})
+		ret = append(ret, &ast.FuncDecl{
+			Recv: &ast.FieldList{List: []*ast.Field{{
+				Type: &ast.Ident{NamePos: vpos, Name: v.Name.Name},
+			}}},
+			Name: &ast.Ident{NamePos: vpos, Name: sealed},
+			Type: &ast.FuncType{Func: token.NoPos, Params: &ast.FieldList{}},
+			Body: &ast.BlockStmt{Lbrace: vpos, Rbrace: vpos},
+		})
+	}
+	ret[0] = &ast.GenDecl{TokPos: pos, Tok: token.TYPE, Specs: typs}
+	return ret
+}
+
+// enumSealedMethod returns name of the unexported method that variants of a
+// sum type implement.
+func enumSealedMethod(name string) string {
+	return "is" + name
+}
+
+// enumParseFunc returns name of the function that parses an enum value.
+func enumParseFunc(d *ast.EnumDecl) string {
+	if d.IsSumType() {
+		return "Unmarshal" + d.Name.Name
+	}
+	return "Parse" + d.Name.Name
+}
+
+// preloadEnumDecl registers loaders of methods and functions generated for an
+// enum declaration.
+func preloadEnumDecl(p *gogen.Package, ctx *blockCtx, d *ast.EnumDecl, goFile string) {
+	parent := ctx.pkgCtx
+	syms := parent.syms
+	name := d.Name
+	fn := func() {
+		old, _ := p.SetCurFile(goFile, true)
+		defer p.RestoreCurFile(old)
+		if d.IsSumType() {
+			genEnumUnmarshal(ctx, d)
+		} else {
+			genEnumParse(ctx, d)
+		}
+	}
+	initLoader(parent, syms, name.Pos(), name.End(), enumParseFunc(d), fn, true)
+	if d.IsSumType() {
+		for _, v := range d.Variants {
+			v := v
+			ld := getTypeLoader(parent, syms, token.NoPos, token.NoPos, v.Name.Name)
+			ld.methods = append(ld.methods, func() {
+				old, _ := p.SetCurFile(goFile, true)
+				defer p.RestoreCurFile(old)
+				doInitType(ld)
+				genVariantMethods(ctx, v)
+			})
+		}
+		return
+	}
+	ld := getTypeLoader(parent, syms, token.NoPos, token.NoPos, name.Name)
+	ld.methods = append(ld.methods, func() {
+		old, _ := p.SetCurFile(goFile, true)
+		defer p.RestoreCurFile(old)
+		doInitType(ld)
+		genEnumMethods(ctx, d)
+	})
+}
+
+// loadEnumDecl loads all symbols of an enum declaration.
+func loadEnumDecl(ctx *pkgCtx, d *ast.EnumDecl) {
+	ctx.loadType(d.Name.Name)
+	for _, v := range d.Variants {
+		if d.IsSumType() {
+			ctx.loadType(v.Name.Name)
+		} else {
+			ctx.loadSymbol(v.Name.Name)
+		}
+	}
+	ctx.loadSymbol(enumParseFunc(d))
+}
+
+func enumObject(ctx *blockCtx, name string) types.Object {
+	ctx.loadSymbol(name)
+	return ctx.pkg.Types.Scope().Lookup(name)
+}
+
+var (
+	tyBytes = types.NewSlice(types.Universe.Lookup("byte").Type())
+	tyAny   = types.Universe.Lookup("any").Type()
+)
+
+// genEnumMethods generates methods of an enum of typed constants:
+//
+//	func (v Color) String() string
+//	func (v Color) MarshalJSON() ([]byte, error)
+//	func (v *Color) UnmarshalJSON(data []byte) (err error)
+func genEnumMethods(ctx *blockCtx, d *ast.EnumDecl) {
+	pkg := ctx.pkg
+	name := d.Name.Name
+	typ := enumObject(ctx, name).Type()
+	pkgJSON := pkg.Import("encoding/json")
+
+	// func (v Color) String() string
+	recv := pkg.NewParam(token.NoPos, "v", typ)
+	ret := types.NewTuple(pkg.NewParam(token.NoPos, "", types.Typ[types.String]))
+	cb := pkg.NewFunc(recv, "String", nil, ret, false).BodyStart(pkg).
+		Switch().Val(recv).Then()
+	for _, v := range d.Variants {
+		vname := v.Name.Name
+		cb.Case().Val(enumObject(ctx, vname)).Then().Val(vname).Return(1).End()
+	}
+	cb.End().Val(name + "(").
+		Val(pkg.Import("strconv").Ref("Itoa")).Typ(types.Typ[types.Int]).Val(recv).Call(1).Call(1).
+		BinaryOp(gotoken.ADD).Val(")").BinaryOp(gotoken.ADD).Return(1).
+		End()
+
+	// func (v Color) MarshalJSON() ([]byte, error) {
+	//	switch v {
+	//	case Red, Green, Blue:
+	//		return json.Marshal(v.String())
+	//	}
+	//	return nil, fmt.Errorf("invalid Color: %d", int(v))
+	// }
+	recv = pkg.NewParam(token.NoPos, "v", typ)
+	ret = types.NewTuple(pkg.NewParam(token.NoPos, "", tyBytes), pkg.NewParam(token.NoPos, "", tyError))
+	cb = pkg.NewFunc(recv, "MarshalJSON", nil, ret, false).BodyStart(pkg)
+	if len(d.Variants) > 0 {
+		cb.Switch().Val(recv).Then().Case()
+		for _, v := range d.Variants {
+			cb.Val(enumObject(ctx, v.Name.Name))
+		}
+		cb.Then().
+			Val(pkgJSON.Ref("Marshal")).Val(recv).MemberVal("String", 0).Call(0).Call(1).Return(1).
+			End().End()
+	}
+	cb.Val(nil).
+		Val(pkg.Import("fmt").Ref("Errorf")).Val("invalid " + name + ": %d").
+		Typ(types.Typ[types.Int]).Val(recv).Call(1).Call(2).Return(2).
+		End()
+
+	// func (v *Color) UnmarshalJSON(data []byte) (err error) {
+	//	var s string
+	//	if err = json.Unmarshal(data, &s); err == nil {
+	//		*v, err = ParseColor(s)
+	//	}
+	//	return
+	// }
+	recv = pkg.NewParam(token.NoPos, "v", types.NewPointer(typ))
+	data := pkg.NewParam(token.NoPos, "data", tyBytes)
+	err := pkg.NewParam(token.NoPos, "err", tyError)
+	pkg.NewFunc(recv, "UnmarshalJSON", types.NewTuple(data), types.NewTuple(err), false).BodyStart(pkg).
+		NewVar(types.Typ[types.String], "s").
+		If().VarRef(err).Val(pkgJSON.Ref("Unmarshal")).Val(data).VarVal("s").UnaryOp(gotoken.AND).Call(2).Assign(1).
+		Val(err).Val(nil).BinaryOp(gotoken.EQL).Then().
+		Val(recv).ElemRef().VarRef(err).Val(enumObject(ctx, enumParseFunc(d))).VarVal("s").Call(1).Assign(2, 1).
+		End().
+		Return(0).
+		End()
+}
+
+// genEnumParse generates the function that parses an enum value by name:
+//
+//	func ParseColor(s string) (Color, error)
+func genEnumParse(ctx *blockCtx, d *ast.EnumDecl) {
+	pkg := ctx.pkg
+	name := d.Name.Name
+	typ := enumObject(ctx, name).Type()
+	s := pkg.NewParam(token.NoPos, "s", types.Typ[types.String])
+	ret := types.NewTuple(pkg.NewParam(token.NoPos, "", typ), pkg.NewParam(token.NoPos, "", tyError))
+	cb := pkg.NewFunc(nil, enumParseFunc(d), types.NewTuple(s), ret, false).BodyStart(pkg).
+		Switch().Val(s).Then()
+	for _, v := range d.Variants {
+		vname := v.Name.Name
+		cb.Case().Val(vname).Then().Val(enumObject(ctx, vname)).Val(nil).Return(2).End()
+	}
+	cb.End().Val(0).
+		Val(pkg.Import("fmt").Ref("Errorf")).Val("invalid " + name + ": %q").Val(s).Call(2).Return(2).
+		End()
+}
+
+// genVariantMethods generates methods of a variant of a sum type:
+//
+//	func (v Circle) String() string
+//	func (v Circle) MarshalJSON() ([]byte, error)
+func genVariantMethods(ctx *blockCtx, v *ast.EnumVariant) {
+	pkg := ctx.pkg
+	name := v.Name.Name
+	typ := enumObject(ctx, name).Type()
+	t := typ.Underlying().(*types.Struct)
+	n := t.NumFields()
+
+	// func (v Circle) String() string {
+	//	return fmt.Sprintf("Circle(%v)", v.X_0)
+	// }
+	recv := pkg.NewParam(token.NoPos, "v", typ)
+	ret := types.NewTuple(pkg.NewParam(token.NoPos, "", types.Typ[types.String]))
+	cb := pkg.NewFunc(recv, "String", nil, ret, false).BodyStart(pkg)
+	if n == 0 {
+		cb.Val(name)
+	} else {
+		format := name + "(" + strings.Repeat("%v, ", n-1) + "%v)"
+		cb.Val(pkg.Import("fmt").Ref("Sprintf")).Val(format)
+		for i := 0; i < n; i++ {
+			cb.Val(recv).MemberVal(t.Field(i).Name(), 0)
+		}
+		cb.Call(n + 1)
+	}
+	cb.Return(1).End()
+
+	// func (v Circle) MarshalJSON() ([]byte, error) {
+	//	return json.Marshal(map[string][]any{"Circle": []any{v.X_0}})
+	// }
+	recv = pkg.NewParam(token.NoPos, "v", typ)
+	ret = types.NewTuple(pkg.NewParam(token.NoPos, "", tyBytes), pkg.NewParam(token.NoPos, "", tyError))
+	tyArgs := types.NewSlice(tyAny)
+	cb = pkg.NewFunc(recv, "MarshalJSON", nil, ret, false).BodyStart(pkg).
+		Val(pkg.Import("encoding/json").Ref("Marshal")).Val(name)
+	for i := 0; i < n; i++ {
+		cb.Val(recv).MemberVal(t.Field(i).Name(), 0)
+	}
+	cb.SliceLit(tyArgs, n).MapLit(types.NewMap(types.Typ[types.String], tyArgs), 2).Call(1).Return(1).
+		End()
+}
+
+// genEnumUnmarshal generates the function that unmarshals a value of a sum
+// type from JSON:
+//
+//	func UnmarshalShape(data []byte) (Shape, error) {
+//		var m map[string]json.RawMessage
+//		if err := json.Unmarshal(data, &m); err != nil {
+//			return nil, err
+//		}
+//		if args, ok := m["Circle"]; ok {
+//			var v Circle
+//			err := json.Unmarshal(args, &[]any{&v.X_0})
+//			return v, err
+//		}
+//		...
+//		return nil, fmt.Errorf("invalid Shape: %s", data)
+//	}
+func genEnumUnmarshal(ctx *blockCtx, d *ast.EnumDecl) {
+	const (
+		nameM    = "m"
+		nameErr  = "err"
+		nameArgs = "args"
+		nameOk   = "ok"
+		nameV    = "v"
+	)
+	pkg := ctx.pkg
+	name := d.Name.Name
+	typ := enumObject(ctx, name).Type()
+	pkgJSON := pkg.Import("encoding/json")
+	data := pkg.NewParam(token.NoPos, "data", tyBytes)
+	ret := types.NewTuple(pkg.NewParam(token.NoPos, "", typ), pkg.NewParam(token.NoPos, "", tyError))
+	tyRaw := pkgJSON.Ref("RawMessage").Type()
+	cb := pkg.NewFunc(nil, enumParseFunc(d), types.NewTuple(data), ret, false).BodyStart(pkg).
+		NewVar(types.NewMap(types.Typ[types.String], tyRaw), nameM).
+		If().DefineVarStart(token.NoPos, nameErr).
+		Val(pkgJSON.Ref("Unmarshal")).Val(data).VarVal(nameM).UnaryOp(gotoken.AND).Call(2).EndInit(1).
+		VarVal(nameErr).Val(nil).BinaryOp(gotoken.NEQ).Then().
+		Val(nil).VarVal(nameErr).Return(2).
+		End()
+	for _, v := range d.Variants {
+		vname := v.Name.Name
+		vtyp := enumObject(ctx, vname).Type()
+		t := vtyp.Underlying().(*types.Struct)
+		n := t.NumFields()
+		cb.If().DefineVarStart(token.NoPos, nameArgs, nameOk).
+			VarVal(nameM).Val(vname).Index(1, 2).EndInit(1).
+			VarVal(nameOk).Then().
+			NewVar(vtyp, nameV).
+			DefineVarStart(token.NoPos, nameErr).
+			Val(pkgJSON.Ref("Unmarshal")).VarVal(nameArgs)
+		for i := 0; i < n; i++ {
+			cb.VarVal(nameV).MemberVal(t.Field(i).Name(), 0).UnaryOp(gotoken.AND)
+		}
+		cb.SliceLit(types.NewSlice(tyAny), n).UnaryOp(gotoken.AND).Call(2).EndInit(1).
+			VarVal(nameV).VarVal(nameErr).Return(2).
+			End()
+	}
+	cb.Val(nil).
+		Val(pkg.Import("fmt").Ref("Errorf")).Val("invalid " + name + ": %s").Val(data).Call(2).Return(2).
+		End()
+}
+
+// -----------------------------------------------------------------------------
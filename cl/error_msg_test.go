@@ -19,10 +19,16 @@ package cl_test
 import (
 	"fmt"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"testing"
 
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/cl"
 	"github.com/goplus/xgo/cl/cltest"
+	"github.com/goplus/xgo/parser"
+	"github.com/goplus/xgo/parser/fsx/memfs"
+	"github.com/goplus/xgo/token"
 )
 
 func codeErrorTest(t *testing.T, msg, src string) {
@@ -1148,3 +1154,306 @@ var a = struct{v int}{(x => x)}
 var a = struct{v int}{v: (x => x)}
 `)
 }
+
+func TestErrTypeSwitchGuard(t *testing.T) {
+	codeErrorTest(t, `bar.xgo:4:6: int (type) is not an expression`, `
+var x any = 1
+switch x.(int) {
+case int:
+}
+`)
+}
+
+func TestErrTypeSwitchGuardInvalid(t *testing.T) {
+	codeErrorTestAst(t, "main", "bar.xgo", `bar.xgo:3:8: invalid type switch guard: x = y.(type)
+bar.xgo:5:7: undefined: undefinedA`, `
+var y any = 1
+switch x = y.(type) {
+case int:
+	echo undefinedA
+}
+`)
+	codeErrorTestAst(t, "main", "bar.xgo", `bar.xgo:4:8: non-name a.b on left side of :=`, `
+var y any = 1
+var a struct{ b any }
+switch a.b := y.(type) {
+case int:
+}
+`)
+}
+
+func TestErrComprehensionInvalid(t *testing.T) {
+	fs := memfs.SingleFile("/foo", "bar.xgo", `echo [x for x in [1, 2]]`)
+	pkgs, err := parser.ParseFSDir(cltest.Conf.Fset, fs, "/foo", parser.Config{})
+	if err != nil {
+		t.Fatal("parser.ParseFSDir failed:", err)
+	}
+	ast.Inspect(pkgs["main"].Files["/foo/bar.xgo"], func(n ast.Node) bool {
+		if v, ok := n.(*ast.ComprehensionExpr); ok {
+			v.Tok = token.ILLEGAL
+		}
+		return true
+	})
+	conf := *cltest.Conf
+	conf.NoFileLine = false
+	conf.RelativeBase = "/foo"
+	_, err = cl.NewPackage("", pkgs["main"], &conf)
+	if err == nil || err.Error() != "bar.xgo:1:6: invalid comprehension expression" {
+		t.Fatal("cl.NewPackage:", err)
+	}
+}
+
+func TestErrTypeAssertOutsideSwitch(t *testing.T) {
+	codeErrorTest(t, `bar.xgo:3:6: use of .(type) outside type switch
+bar.xgo:4:6: undefined: y`, `
+var x any = 1
+y := x.(type)
+echo y
+`)
+}
+
+func TestErrAssignOp(t *testing.T) {
+	codeErrorTest(t, `bar.xgo:3:1: assignment operation += requires single-valued expressions`, `
+a, b := 1, 2
+a, b += 1, 2
+echo a, b
+`)
+}
+
+func TestErrNonNameDefine(t *testing.T) {
+	codeErrorTestAst(t, "main", "bar.xgo", `bar.xgo:3:1: non-name a[0] on left side of :=`, `
+a := [1]
+a[0] := 2
+`)
+}
+
+func TestErrMultiStmts(t *testing.T) {
+	codeErrorTest(t, `bar.xgo:4:6: int (type) is not an expression
+bar.xgo:7:1: assignment operation -= requires single-valued expressions
+bar.xgo:8:6: undefined: foo
+bar.xgo:9:6: use of .(type) outside type switch`, `
+var x any = 1
+switch x.(int) {
+case int:
+}
+a, b := 1, 2
+a, b -= 1, 2
+echo foo
+echo x.(type)
+`)
+}
+
+func TestErrMatchStmt(t *testing.T) {
+	codeErrorTest(t, `bar.xgo:5:6: cannot match int with tuple pattern (x, y)`, `
+var n int
+match n {
+case 1:
+case (x, y):
+}`)
+	codeErrorTest(t, `bar.xgo:5:6: cannot match Point with tuple pattern (x, y, z): expected 2 elements, got 3`, `
+type Point (x, y int)
+var pt Point
+match pt {
+case (x, y, z):
+}`)
+	codeErrorTest(t, `bar.xgo:5:12: unknown field z in struct pattern of type Point`, `
+type Point (x, y int)
+var pt Point
+match pt {
+case Point{z: 1}:
+}`)
+	codeErrorTest(t, `bar.xgo:5:6: cannot bind variables in a case with multiple patterns`, `
+type Point (x, y int)
+var pt Point
+match pt {
+case (0, y), (y, 0):
+	echo y
+}`)
+	codeErrorTest(t, `bar.xgo:5:10: x repeated in pattern`, `
+type Point (x, y int)
+var pt Point
+match pt {
+case (x, x):
+	echo x
+}`)
+	codeErrorTest(t, `bar.xgo:5:2: multiple defaults in match (first at bar.xgo:4:2)`, `
+var n int
+match n {
+	default:
+	default:
+}`)
+}
+
+func TestErrDestructure(t *testing.T) {
+	codeErrorTest(t, `bar.xgo:2:1: cannot destructure int with tuple pattern (a, b)`, `
+(a, b) := 1
+`)
+	codeErrorTest(t, `bar.xgo:2:5: cannot destructure range value with tuple pattern (a, b)`, `
+for (a, b) in 1:3 {
+	echo a, b
+}
+`)
+}
+
+func TestMatchNotExhaustive(t *testing.T) {
+	const src = `
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+
+func name(c Color, ok bool) string {
+	match ok {
+	case true:
+		return "ok"
+	}
+	match c {
+	case Red:
+		return "red"
+	case Green if ok:
+		return "green"
+	}
+	return ""
+}
+`
+	warnTest(t, src,
+		"bar.xgo:11:2: match is not exhaustive: missing case false",
+		"bar.xgo:15:2: match is not exhaustive: missing case Blue, Green",
+	)
+}
+
+func TestTypeSwitchNotExhaustive(t *testing.T) {
+	const src = `
+enum Shape {
+	Circle(r float64)
+	Rect(w, h float64)
+	Empty
+}
+
+func area(s Shape) float64 {
+	switch s := s.(type) {
+	case Circle:
+		return s.r * s.r
+	}
+	switch s.(type) {
+	case Circle, Rect, Empty:
+	}
+	switch s.(type) {
+	case Rect:
+	default:
+	}
+	var x any = s
+	switch x.(type) {
+	case Rect:
+	}
+	return 0
+}
+`
+	warnTest(t, src,
+		"bar.xgo:9:2: type switch is not exhaustive: missing case Empty, Rect",
+	)
+}
+
+func warnTest(t *testing.T, src string, expected ...string) {
+	t.Helper()
+	fs := memfs.SingleFile("/foo", "bar.xgo", src)
+	pkgs, err := parser.ParseFSDir(cltest.Conf.Fset, fs, "/foo", parser.Config{})
+	if err != nil {
+		t.Fatal("parser.ParseFSDir failed:", err)
+	}
+	var warns []string
+	conf := *cltest.Conf
+	conf.NoFileLine = false
+	conf.RelativeBase = "/foo"
+	conf.Warning = func(err error) {
+		warns = append(warns, err.Error())
+	}
+	if _, err = cl.NewPackage("", pkgs["main"], &conf); err != nil {
+		t.Fatal("cl.NewPackage failed:", err)
+	}
+	if !reflect.DeepEqual(warns, expected) {
+		t.Fatalf("warnings: %q\nexpected: %q", warns, expected)
+	}
+}
+
+func TestErrUnit(t *testing.T) {
+	codeErrorTest(t, `bar.xgo:4:6: invalid operation: 3m + 1s (mismatched units unit.Distance and unit.Time)`, `
+import "github.com/goplus/xgo/cl/internal/unit"
+
+echo 3m + 1s
+`)
+	codeErrorTest(t, `bar.xgo:4:6: undefined unit kg`, `
+import "github.com/goplus/xgo/cl/internal/unit"
+
+echo 3kg
+`)
+	codeErrorTest(t, `bar.xgo:7:6: ambiguous unit m: unit.Distance or time.Duration`, `
+import (
+	"time"
+	"github.com/goplus/xgo/cl/internal/unit"
+)
+
+echo 1m
+`)
+	codeErrorTest(t, `bar.xgo:4:6: invalid operation: 3m * 1min (no unit type for unit.Distance * unit.Time)`, `
+import "github.com/goplus/xgo/cl/internal/unit"
+
+echo 3m * 1min
+`)
+	codeErrorTest(t, `bar.xgo:4:6: cannot use 0.5mm as unit.Distance value (truncated)`, `
+import "github.com/goplus/xgo/cl/internal/unit"
+
+echo 0.5mm
+`)
+}
+
+func TestErrMatrixLit(t *testing.T) {
+	codeErrorTest(t, `bar.xgo:4:2: inconsistent matrix column count: got 2, want 3`, `
+echo [
+	1, 2, 3
+	4, 5
+]
+`)
+	codeErrorTest(t, `bar.xgo:4:2: cannot use 1 (type untyped int) as type string in slice literal`, `
+row := ["a"]
+echo [
+	1, 2
+	0, row...
+]
+`)
+}
+
+func TestErrGoExpr(t *testing.T) {
+	codeErrorTest(t, `bar.xgo:2:9: go expression requires a list of calls`, `
+echo go []
+`)
+	codeErrorTest(t, `bar.xgo:4:10: can't use expr? in go expression`, `
+import "strconv"
+
+echo go [strconv.Atoi("1")?]
+`)
+	codeErrorTest(t, `bar.xgo:2:25: mismatched lambda parameters ctx and c in go expression`, `
+echo go [ctx => f(ctx), c => g(c)]
+`)
+	codeErrorTest(t, `bar.xgo:2:10: lambda in go expression must be of the form ctx => call`, `
+echo go [ctx => {}]
+`)
+}
+
+func TestErrOptChain(t *testing.T) {
+	codeErrorTest(t, `bar.xgo:3:6: invalid operation: operator ?. not defined on x (type int)`, `
+var x int
+echo x?.y
+`)
+	codeErrorTest(t, `bar.xgo:3:6: invalid operation: operator ?? not defined on x (type int)`, `
+var x int
+echo x ?? 1
+`)
+	codeErrorTest(t, `bar.xgo:3:1: cannot use optional chaining p?. here`, `
+var p *struct{ x int }
+p?.x = 1
+`)
+}
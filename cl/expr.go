@@ -22,7 +22,6 @@ import (
 	goast "go/ast"
 	gotoken "go/token"
 	"go/types"
-	"log"
 	"math/big"
 	"strconv"
 	"strings"
@@ -90,6 +89,10 @@ func compileIdent(ctx *blockCtx, lhs int, ident *ast.Ident, flags int) (pkg goge
 		cb.VarRef(nil)
 		return
 	}
+	if v, ok := ctx.guardBinds[name]; ok && fvalue { // variable bound by a match pattern
+		v.push(cb)
+		return
+	}
 
 	var recv *types.Var
 	var oldo types.Object
@@ -184,34 +187,157 @@ find:
 	return
 }
 
-/*
-func compileMatrixLit(ctx *blockCtx, v *ast.MatrixLit) {
+// compileMatrixLit compiles a matrix literal into a slice of rows:
+//
+//	[a, b; c, d]        =>  [][]T{{a, b}, {c, d}}
+//	[a, row...; c, d]   =>  [][]T{append([]T{a}, row...), {c, d}}
+//
+// If typ is a named type T and T's package provides a T_Cast([][]E) function,
+// the rows are converted to T by T([][]E{...}).
+func compileMatrixLit(ctx *blockCtx, v *ast.MatrixLit, typ types.Type) {
 	cb := ctx.cb
-	ncol := -1
+	rowsTyp, castTo := matrixRowsType(typ)
+	ncol, total := -1, 0
 	for _, elts := range v.Elts {
-		switch n := len(elts); n {
-		case 1:
-			elt := elts[0]
-			if e, ok := elt.(*ast.Ellipsis); ok {
-				compileExpr(ctx, e.Elt)
-				panic("TODO") // TODO(xsw): matrixLit with ellipsis
-			}
-			fallthrough
-		default:
-			if ncol < 0 {
-				ncol = n
-			} else if ncol != n {
-				ctx.handleErrorf(elts[0].Pos(), "inconsistent matrix column count: got %v, want %v", n, ncol)
+		n, ellipsis := len(elts), false
+		for _, elt := range elts {
+			if e, ok := elt.(*ast.ElemEllipsis); ok {
+				compileExpr(ctx, 1, e.Elt)
+				ellipsis = true
+			} else {
+				compileExpr(ctx, 1, elt)
 			}
-			for _, elt := range elts {
-				compileExpr(ctx, elt)
+		}
+		total += n
+		if ellipsis { // column count is unknown until run time
+			continue
+		}
+		if ncol < 0 {
+			ncol = n
+		} else if ncol != n {
+			panic(ctx.newCodeErrorf(elts[0].Pos(), elts[n-1].End(),
+				"inconsistent matrix column count: got %v, want %v", n, ncol))
+		}
+	}
+	stk := cb.InternalStack()
+	elems := append([]*gogen.Element(nil), stk.GetArgs(total)...)
+	stk.PopN(total)
+
+	var rowTyp types.Type
+	if rowsTyp != nil {
+		rowTyp = rowsTyp.Underlying().(*types.Slice).Elem()
+	} else {
+		rowTyp = types.NewSlice(matrixElemType(v, elems))
+		rowsTyp = types.NewSlice(rowTyp)
+	}
+	if castTo != nil {
+		cb.Typ(castTo)
+	}
+	i := 0
+	for _, elts := range v.Elts {
+		compileMatrixRow(ctx, rowTyp, elts, elems[i:i+len(elts)])
+		i += len(elts)
+	}
+	cb.SliceLitEx(rowsTyp, len(v.Elts), false, v)
+	if castTo != nil {
+		cb.CallWith(1, 0, 0, v)
+	}
+}
+
+// compileMatrixRow pushes a row of a matrix literal. Elements of the form
+// row... are appended to the row at run time.
+func compileMatrixRow(ctx *blockCtx, rowTyp types.Type, elts []ast.Expr, elems []*gogen.Element) {
+	cb := ctx.cb
+	stk := cb.InternalStack()
+	i, n := 0, len(elts)
+	for i < n && !isElemEllipsis(elts[i]) {
+		stk.Push(elems[i])
+		i++
+	}
+	cb.SliceLitEx(rowTyp, i, false)
+	for i < n {
+		row := stk.Pop()
+		cb.Val(ctx.pkg.Builtin().Ref("append"))
+		stk.Push(row)
+		if e := elts[i]; isElemEllipsis(e) {
+			stk.Push(elems[i])
+			cb.CallWith(2, 0, gogen.InstrFlagEllipsis, e)
+			i++
+			continue
+		}
+		start := i
+		for i < n && !isElemEllipsis(elts[i]) {
+			stk.Push(elems[i])
+			i++
+		}
+		cb.CallWith(1+i-start, 0, 0, elts[i-1])
+	}
+}
+
+func isElemEllipsis(elt ast.Expr) bool {
+	_, ok := elt.(*ast.ElemEllipsis)
+	return ok
+}
+
+// matrixRowsType returns the slice of rows type that a matrix literal of type
+// typ is compiled to. castTo is not nil if the rows need to be converted to
+// typ by its T_Cast function.
+func matrixRowsType(typ types.Type) (rowsTyp, castTo types.Type) {
+	if typ == nil {
+		return
+	}
+	if isMatrixRowsType(typ) {
+		return typ, nil
+	}
+	if t, ok := typ.(*types.Named); ok {
+		o := t.Obj()
+		if pkg := o.Pkg(); pkg != nil {
+			if cast, ok := pkg.Scope().Lookup(o.Name() + "_Cast").(*types.Func); ok {
+				sig := cast.Type().(*types.Signature)
+				if params := sig.Params(); params.Len() == 1 && !sig.Variadic() {
+					if ptyp := params.At(0).Type(); isMatrixRowsType(ptyp) {
+						return ptyp, typ
+					}
+				}
 			}
-			cb.SliceLitEx(...)
 		}
 	}
+	return
 }
-*/
 
+func isMatrixRowsType(typ types.Type) bool {
+	if t, ok := typ.Underlying().(*types.Slice); ok {
+		_, ok = t.Elem().Underlying().(*types.Slice)
+		return ok
+	}
+	return false
+}
+
+// matrixElemType infers the element type of a matrix literal without an
+// expected type: the type of its first typed element, or the default type of
+// its untyped constants.
+func matrixElemType(v *ast.MatrixLit, elems []*gogen.Element) types.Type {
+	var kind types.BasicKind
+	i := 0
+	for _, elts := range v.Elts {
+		for _, elt := range elts {
+			t := elems[i].Type
+			i++
+			if isElemEllipsis(elt) {
+				if st, ok := t.Underlying().(*types.Slice); ok {
+					return st.Elem()
+				}
+				continue
+			}
+			if bt, ok := t.(*types.Basic); ok && bt.Info()&types.IsUntyped != 0 {
+				kind = max(kind, bt.Kind())
+				continue
+			}
+			return t
+		}
+	}
+	return types.Default(types.Typ[kind])
+}
 func compileEnvExpr(ctx *blockCtx, lhs int, v *ast.EnvExpr) {
 	cb := ctx.cb
 	if _, self := cb.Scope().LookupParent("self", 0); self != nil { // self.$attr
@@ -323,6 +449,12 @@ func callCmdNoArgs(ctx *blockCtx, src ast.Node, panicErr bool) (err error) {
 // compileExpr compiles expr.
 // lhs indicates how many values are expected on the left-hand side.
 func compileExpr(ctx *blockCtx, lhs int, expr ast.Expr, inFlags ...int) {
+	if compileOptChainExpr(ctx, lhs, expr) { // a?.b.c, m[k] in a?.b[k], etc.
+		if rec := ctx.recorder(); rec != nil {
+			rec.recordExpr(ctx, expr, false)
+		}
+		return
+	}
 	switch v := expr.(type) {
 	case *ast.Ident:
 		flags, cmdNoArgs := identOrSelectorFlags(inFlags)
@@ -347,6 +479,8 @@ func compileExpr(ctx *blockCtx, lhs int, expr ast.Expr, inFlags ...int) {
 		}
 	case *ast.BasicLit:
 		compileBasicLit(ctx, v)
+	case *ast.NumberUnitLit:
+		compileNumberUnitLit(ctx, v, nil)
 	case *ast.CallExpr:
 		flags := 0
 		if inFlags != nil {
@@ -400,12 +534,14 @@ func compileExpr(ctx *blockCtx, lhs int, expr ast.Expr, inFlags ...int) {
 		compileExpr(ctx, lhs, v.X, inFlags...)
 	case *ast.ErrWrapExpr:
 		compileErrWrapExpr(ctx, lhs, v, 0)
+	case *ast.GoExpr:
+		compileGoExpr(ctx, lhs, v)
 	case *ast.FuncType:
 		ctx.cb.Typ(toFuncType(ctx, v, nil, nil), v)
 	case *ast.EnvExpr:
 		compileEnvExpr(ctx, lhs, v)
-	/* case *ast.MatrixLit:
-	compileMatrixLit(ctx, v) */
+	case *ast.MatrixLit:
+		compileMatrixLit(ctx, v, nil)
 	case *ast.DomainTextLit:
 		compileDomainTextLit(ctx, v)
 	case *ast.AnySelectorExpr:
@@ -434,9 +570,26 @@ func compileUnaryExpr(ctx *blockCtx, lhs int, v *ast.UnaryExpr) {
 }
 
 func compileBinaryExpr(ctx *blockCtx, v *ast.BinaryExpr) {
-	compileExpr(ctx, 1, v.X)
-	compileExpr(ctx, 1, v.Y)
-	ctx.cb.BinaryOp(gotoken.Token(v.Op), v)
+	cb := ctx.cb
+	if v.Op == token.COALESCE { // x ?? y
+		compileOptChain(ctx, 1, v.X, v.Y, v)
+		return
+	}
+	if y, ok := v.Y.(*ast.NumberUnitLit); ok { // eg. 1km + 300m, d + 300m
+		if x, ok := v.X.(*ast.NumberUnitLit); ok {
+			compileNumberUnitLitEx(ctx, x, nil, y.Unit)
+		} else {
+			compileExpr(ctx, 1, v.X)
+		}
+		compileNumberUnitLit(ctx, y, cb.Get(-1).Type)
+	} else {
+		compileExpr(ctx, 1, v.X)
+		compileExpr(ctx, 1, v.Y)
+	}
+	if compileUnitBinaryOp(ctx, v) {
+		return
+	}
+	cb.BinaryOp(gotoken.Token(v.Op), v)
 }
 
 func compileIndexExprLHS(ctx *blockCtx, v *ast.IndexExpr) {
@@ -458,7 +611,7 @@ func compileStarExpr(ctx *blockCtx, v *ast.StarExpr) { // ... = *x
 func compileTypeAssertExpr(ctx *blockCtx, lhs int, v *ast.TypeAssertExpr) {
 	compileExpr(ctx, 1, v.X)
 	if v.Type == nil {
-		panic("TODO: x.(type) is only used in type switch")
+		panic(ctx.newCodeError(v.Pos(), v.End(), "use of .(type) outside type switch"))
 	}
 	typ := toType(ctx, v.Type)
 	ctx.cb.TypeAssert(typ, lhs, v)
@@ -902,6 +1055,17 @@ func compileCallExpr(ctx *blockCtx, lhs int, v *ast.CallExpr, inFlags int) {
 		ne.Args, ne.Kwargs = args, nil
 		v = &ne
 	}
+	if len(v.Args) == 0 {
+		// T() of an empty struct type T (eg. a variant of a sum type without
+		// fields) is a composite literal T{}
+		if tt, ok := fnt.(*gogen.TypeType); ok {
+			if t, ok := tt.Type().Underlying().(*types.Struct); ok && t.NumFields() == 0 {
+				stk.Pop()
+				ctx.cb.StructLit(tt.Type(), 0, false, v)
+				return
+			}
+		}
+	}
 	for fn != nil {
 		if err = compileCallArgs(ctx, lhs, pfn, fn, v, ellipsis, flags); err == nil {
 			if rec := ctx.recorder(); rec != nil {
@@ -1144,6 +1308,8 @@ func compileCallArgs(ctx *blockCtx, lhs int, pfn *gogen.Element, fn *fnType, v *
 			}
 		case *ast.NumberUnitLit:
 			compileNumberUnitLit(ctx, expr, t)
+		case *ast.MatrixLit:
+			compileMatrixLit(ctx, expr, t)
 		default:
 			compileExpr(ctx, 1, arg)
 			if sigParamLen(t) == 0 {
@@ -1342,12 +1508,6 @@ func compileFuncLit(ctx *blockCtx, v *ast.FuncLit) {
 	}
 }
 
-func compileNumberUnitLit(ctx *blockCtx, v *ast.NumberUnitLit, expected types.Type) {
-	ctx.cb.ValWithUnit(
-		&goast.BasicLit{ValuePos: v.ValuePos, Kind: gotoken.Token(v.Kind), Value: v.Value},
-		expected, v.Unit)
-}
-
 func compileBasicLit(ctx *blockCtx, v *ast.BasicLit) {
 	cb := ctx.cb
 	switch kind := v.Kind; kind {
@@ -1356,9 +1516,24 @@ func compileBasicLit(ctx *blockCtx, v *ast.BasicLit) {
 		bi, _ := new(big.Int).SetString(val[:len(val)-1], 10) // remove r suffix
 		cb.UntypedBigInt(bi, v)
 	case token.CSTRING, token.PYSTRING:
-		s, err := strconv.Unquote(v.Value)
+		if v.Extra != nil && hasStringLitExpr(v.Extra) {
+			// c"...${expr}..." => c.AllocaCStr(stringutil.Concat(...))
+			// py"...${expr}..." => py.FromGoString(stringutil.Concat(...))
+			var conv gogen.Ref
+			switch kind {
+			case token.CSTRING:
+				conv = ctx.pkg.Import(pathLibc).Ref("AllocaCStr")
+			default:
+				conv = ctx.pkg.Import(pathLibpy).Ref("FromGoString")
+			}
+			cb.Val(conv)
+			compileStringLitEx(ctx, cb, v)
+			cb.CallWith(1, 0, 0, v)
+			return
+		}
+		s, err := strconv.Unquote(stringLitText(v))
 		if err != nil {
-			log.Panicln("compileBasicLit:", err)
+			panic(ctx.newCodeErrorf(v.Pos(), v.End(), "invalid string literal %s: %v", v.Value, err))
 		}
 		var xstr gogen.Ref
 		switch kind {
@@ -1367,7 +1542,7 @@ func compileBasicLit(ctx *blockCtx, v *ast.BasicLit) {
 		default:
 			xstr = ctx.pystr()
 		}
-		cb.Val(xstr).Val(s).Call(1)
+		cb.Val(xstr).Val(s).CallWith(1, 0, 0, v)
 	default:
 		if v.Extra == nil {
 			basicLit(cb, v)
@@ -1377,6 +1552,35 @@ func compileBasicLit(ctx *blockCtx, v *ast.BasicLit) {
 	}
 }
 
+func hasStringLitExpr(lit *ast.StringLitEx) bool {
+	for _, part := range lit.Parts {
+		if _, ok := part.(ast.Expr); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// stringLitText returns the quoted text of a string literal without ${expr},
+// with all "$$" replaced by "$".
+func stringLitText(lit *ast.BasicLit) string {
+	if lit.Extra == nil {
+		return lit.Value
+	}
+	var b strings.Builder
+	quote := lit.Value[:1]
+	b.WriteString(quote)
+	for _, part := range lit.Extra.Parts {
+		v := part.(string)
+		if strings.HasSuffix(v, "$$") {
+			v = v[:len(v)-1]
+		}
+		b.WriteString(v)
+	}
+	b.WriteString(quote)
+	return b.String()
+}
+
 func invalidVal(cb *gogen.CodeBuilder) {
 	cb.Val(&gogen.Element{Type: types.Typ[types.Invalid]})
 }
@@ -1390,7 +1594,7 @@ const (
 )
 
 func compileStringLitEx(ctx *blockCtx, cb *gogen.CodeBuilder, lit *ast.BasicLit) {
-	pos := lit.ValuePos + 1
+	pos := lit.End() - token.Pos(len(lit.Value)) + 1 // skip c or py prefix and quote
 	quote := lit.Value[:1]
 	parts := lit.Extra.Parts
 	n := len(parts)
@@ -1425,7 +1629,7 @@ func compileStringLitEx(ctx *blockCtx, cb *gogen.CodeBuilder, lit *ast.BasicLit)
 			}
 			pos = v.End()
 		default:
-			panic("compileStringLitEx TODO: unexpected part")
+			panic(ctx.newCodeErrorf(lit.Pos(), lit.End(), "unexpected part %T in string literal", v))
 		}
 	}
 	if n != 1 {
@@ -1845,7 +2049,7 @@ func comprehensionKind(v *ast.ComprehensionExpr) int {
 		}
 		return comprehensionSelect
 	}
-	panic("TODO: invalid comprehensionExpr")
+	return comprehensionInvalid
 }
 
 // [expr for k, v in container, cond]
@@ -1858,6 +2062,9 @@ func compileComprehensionExpr(ctx *blockCtx, lhs int, v *ast.ComprehensionExpr)
 		nameRet = "_xgo_ret"
 	)
 	kind := comprehensionKind(v)
+	if kind == comprehensionInvalid {
+		panic(ctx.newCodeError(v.Pos(), v.End(), "invalid comprehension expression"))
+	}
 	pkg, cb := ctx.pkg, ctx.cb
 	var results *types.Tuple
 	var ret *gogen.Param
@@ -1877,37 +2084,7 @@ func compileComprehensionExpr(ctx *blockCtx, lhs int, v *ast.ComprehensionExpr)
 	if kind == comprehensionMap {
 		cb.VarRef(ret).ZeroLit(ret.Type()).Assign(1)
 	}
-	end := 0
-	for i := len(v.Fors) - 1; i >= 0; i-- {
-		names := make([]string, 0, 2)
-		defineNames := make([]*ast.Ident, 0, 2)
-		forStmt := v.Fors[i]
-		if forStmt.Key != nil {
-			names = append(names, forStmt.Key.Name)
-			defineNames = append(defineNames, forStmt.Key)
-		} else {
-			names = append(names, "_")
-		}
-		names = append(names, forStmt.Value.Name)
-		defineNames = append(defineNames, forStmt.Value)
-		cb.ForRange(names...)
-		compileExpr(ctx, 1, forStmt.X)
-		cb.RangeAssignThen(forStmt.TokPos)
-		defNames(ctx, defineNames, cb.Scope())
-		if rec := ctx.recorder(); rec != nil {
-			rec.Scope(forStmt, cb.Scope())
-		}
-		if forStmt.Cond != nil {
-			cb.If()
-			if forStmt.Init != nil {
-				compileStmt(ctx, forStmt.Init)
-			}
-			compileExpr(ctx, 1, forStmt.Cond)
-			cb.Then()
-			end++
-		}
-		end++
-	}
+	end := compileForPhrases(ctx, v.Fors)
 	switch kind {
 	case comprehensionList:
 		// _xgo_ret = append(_xgo_ret, elt)
@@ -1946,6 +2123,50 @@ func compileComprehensionExpr(ctx *blockCtx, lhs int, v *ast.ComprehensionExpr)
 	cb.Return(0).End().Call(0)
 }
 
+// compileForPhrases starts the loops of for phrases of a comprehension. It
+// returns the number of blocks to end.
+func compileForPhrases(ctx *blockCtx, fors []*ast.ForPhrase) (end int) {
+	cb := ctx.cb
+	for i := len(fors) - 1; i >= 0; i-- {
+		names := make([]string, 0, 2)
+		defineNames := make([]*ast.Ident, 0, 2)
+		forStmt := fors[i]
+		if forStmt.Key != nil {
+			names = append(names, forStmt.Key.Name)
+			defineNames = append(defineNames, forStmt.Key)
+		} else {
+			names = append(names, "_")
+		}
+		if forStmt.Tuple != nil {
+			names = append(names, nameForValue)
+		} else {
+			names = append(names, forStmt.Value.Name)
+			defineNames = append(defineNames, forStmt.Value)
+		}
+		cb.ForRange(names...)
+		compileExpr(ctx, 1, forStmt.X)
+		cb.RangeAssignThen(forStmt.TokPos)
+		defNames(ctx, defineNames, cb.Scope())
+		if forStmt.Tuple != nil {
+			destructForPhrase(ctx, forStmt.Tuple)
+		}
+		if rec := ctx.recorder(); rec != nil {
+			rec.Scope(forStmt, cb.Scope())
+		}
+		if forStmt.Cond != nil {
+			cb.If()
+			if forStmt.Init != nil {
+				compileStmt(ctx, forStmt.Init)
+			}
+			compileExpr(ctx, 1, forStmt.Cond)
+			cb.Then()
+			end++
+		}
+		end++
+	}
+	return
+}
+
 const (
 	errorPkgPath = "github.com/qiniu/x/errors"
 )
@@ -1960,10 +2181,10 @@ func compileErrWrapExpr(ctx *blockCtx, lhs int, v *ast.ErrWrapExpr, inFlags int)
 		nameRet = "_xgo_ret"
 	)
 	pkg, cb := ctx.pkg, ctx.cb
-	useClosure := v.Tok == token.NOT || v.Default != nil
-	if !useClosure && (cb.Scope().Parent() == types.Universe) {
-		panic("TODO: can't use expr? in global")
-	}
+	// There is no function to return the error to in a package-level var
+	// initializer, so expr? panics like expr! there.
+	global := ctx.inInit && cb.Func() == ctx.initFn
+	useClosure := v.Tok == token.NOT || v.Default != nil || global
 	if lhs != 0 {
 		// lhs == 0 means the result is discarded
 		// +1 accounts for the error value that will be stripped from the result tuple
@@ -2010,10 +2231,12 @@ func compileErrWrapExpr(ctx *blockCtx, lhs int, v *ast.ErrWrapExpr, inFlags int)
 	cb.If().Val(err).CompareNil(gotoken.NEQ).Then()
 	if v.Default == nil {
 		pos := pkg.Fset.Position(v.Pos())
-		curFn := cb.Func().Ancestor()
-		curFnName := curFn.Name()
-		if curFnName == "" {
-			curFnName = "main"
+		curFnName := "init"
+		if !global {
+			curFnName = cb.Func().Ancestor().Name()
+			if curFnName == "" {
+				curFnName = "main"
+			}
 		}
 
 		cb.VarRef(err).
@@ -2022,12 +2245,12 @@ func compileErrWrapExpr(ctx *blockCtx, lhs int, v *ast.ErrWrapExpr, inFlags int)
 			Val(sprintAst(pkg.Fset, v.X)).
 			Val(relFile(ctx.relBaseDir, pos.Filename)).
 			Val(pos.Line).
-			Val(curFn.Pkg().Name() + "." + curFnName).
+			Val(pkg.Types.Name() + "." + curFnName).
 			Call(5).
 			Assign(1)
 	}
 
-	if v.Tok == token.NOT { // expr!
+	if v.Tok == token.NOT || (global && v.Default == nil) { // expr!
 		cb.Val(pkg.Builtin().Ref("panic")).Val(err).Call(1).EndStmt()
 	} else if v.Default == nil { // expr?
 		cb.Val(err).ReturnErr(true)
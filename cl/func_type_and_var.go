@@ -40,7 +40,8 @@ func toRecv(ctx *blockCtx, recv *ast.FieldList) *types.Var {
 	typ, star, _ := getRecvType(v.Type)
 	id, ok := typ.(*ast.Ident)
 	if !ok {
-		panic("TODO: getRecvType")
+		src := ctx.LoadExpr(typ)
+		panic(ctx.newCodeErrorf(typ.Pos(), typ.End(), "invalid receiver type %v (%v is not a defined type)", src, src))
 	}
 	t := toIdentType(ctx, id)
 	if star {
@@ -343,13 +344,17 @@ func (p *checkRedecl) chkRedecl(ctx *blockCtx, name string, pos, end token.Pos,
 // Named fields in the tuple are compile-time aliases converted to ordinal fields.
 func toTupleType(ctx *blockCtx, v *ast.TupleType) types.Type {
 	fieldList := v.Fields.List
-	switch len(fieldList) {
-	case 0:
-		return types.NewStruct(nil, nil)
-	case 1:
-		// single-field tuple is equivalent to the field type itself
-		if len(fieldList[0].Names) <= 1 {
-			return toType(ctx, fieldList[0].Type)
+	// variants of a sum type are always tuples, even if they have less than
+	// two fields
+	if _, ok := ctx.variants[v]; !ok {
+		switch len(fieldList) {
+		case 0:
+			return types.NewStruct(nil, nil)
+		case 1:
+			// single-field tuple is equivalent to the field type itself
+			if len(fieldList[0].Names) <= 1 {
+				return toType(ctx, fieldList[0].Type)
+			}
 		}
 	}
 
@@ -404,7 +409,7 @@ func toStructType(ctx *blockCtx, v *ast.StructType) *types.Struct {
 				emptyStruct := types.NewStruct(nil, nil)
 				fld := types.NewField(ident.NamePos, pkg, "_", emptyStruct, false)
 				fields = append(fields, fld)
-				tags = append(tags, toFieldTag(field.Tag))
+				tags = append(tags, toFieldTag(ctx, field.Tag))
 				if rec != nil {
 					rec.Def(ident, fld)
 				}
@@ -414,17 +419,21 @@ func toStructType(ctx *blockCtx, v *ast.StructType) *types.Struct {
 
 		typ := toType(ctx, field.Type)
 		if len(field.Names) == 0 { // embedded
-			name := getTypeName(typ)
+			ident := parseTypeEmbedName(field.Type)
+			name, ok := getTypeName(typ)
+			if ident == nil || !ok {
+				ctx.handleErrorf(field.Type.Pos(), field.Type.End(), "invalid embedded field type %s", ctx.LoadExpr(field.Type))
+				continue
+			}
 			if chk.chkRedecl(ctx, name, field.Type.Pos(), field.Type.End(), fieldKindUser) {
 				continue
 			}
 			if t, ok := typ.(*types.Named); ok { // #1196: embedded type should ensure loaded
 				ctx.loadNamed(ctx.pkg, t)
 			}
-			ident := parseTypeEmbedName(field.Type)
 			fld := types.NewField(ident.NamePos, pkg, name, typ, true)
 			fields = append(fields, fld)
-			tags = append(tags, toFieldTag(field.Tag))
+			tags = append(tags, toFieldTag(ctx, field.Tag))
 			if rec != nil {
 				rec.Def(ident, fld)
 			}
@@ -436,7 +445,7 @@ func toStructType(ctx *blockCtx, v *ast.StructType) *types.Struct {
 			}
 			fld := types.NewField(name.NamePos, pkg, name.Name, typ, false)
 			fields = append(fields, fld)
-			tags = append(tags, toFieldTag(field.Tag))
+			tags = append(tags, toFieldTag(ctx, field.Tag))
 			if rec != nil {
 				rec.Def(name, fld)
 			}
@@ -445,7 +454,7 @@ func toStructType(ctx *blockCtx, v *ast.StructType) *types.Struct {
 	return types.NewStruct(fields, tags)
 }
 
-func toFieldTag(v *ast.BasicLit) string {
+func toFieldTag(ctx *blockCtx, v *ast.BasicLit) string {
 	if v != nil {
 		data := v.Value
 		if len(data) > 0 && data[0] == '"' && noTagKey(data) {
@@ -453,7 +462,7 @@ func toFieldTag(v *ast.BasicLit) string {
 		}
 		tag, err := strconv.Unquote(data)
 		if err != nil {
-			log.Panicln("TODO: toFieldTag -", err)
+			ctx.handleErrorf(v.Pos(), v.End(), "invalid struct tag %s: %v", data, err)
 		}
 		return tag
 	}
@@ -468,18 +477,19 @@ func noTagKey(data string) bool {
 	return strings.IndexByte(data[:pos], ' ') >= 0
 }
 
-func getTypeName(typ types.Type) string {
+func getTypeName(typ types.Type) (string, bool) {
 	if t, ok := typ.(*types.Pointer); ok {
 		typ = t.Elem()
 	}
 	switch t := typ.(type) {
 	case *types.Named:
-		return t.Obj().Name()
+		return t.Obj().Name(), true
+	case *types.Alias:
+		return t.Obj().Name(), true
 	case *types.Basic:
-		return t.Name()
-	default:
-		panic("TODO: getTypeName")
+		return t.Name(), true
 	}
+	return "", false
 }
 
 func toMapType(ctx *blockCtx, v *ast.MapType) *types.Map {
@@ -574,14 +584,14 @@ func toIndexListType(ctx *blockCtx, v *ast.IndexListExpr) types.Type {
 
 // -----------------------------------------------------------------------------
 
-func toString(l *ast.BasicLit) string {
+func toString(ctx *blockCtx, l *ast.BasicLit) string {
 	if l.Kind == token.STRING {
 		s, err := strconv.Unquote(l.Value)
 		if err == nil {
 			return s
 		}
 	}
-	panic("TODO: toString - convert ast.BasicLit to string failed")
+	panic(ctx.newCodeErrorf(l.Pos(), l.End(), "invalid string %s", l.Value))
 }
 
 // -----------------------------------------------------------------------------
@@ -0,0 +1,181 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cl
+
+import (
+	"go/types"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/token"
+)
+
+const (
+	parallelPkgPath = "github.com/goplus/xgo/parallel"
+)
+
+// results of tasks of a go expression
+const (
+	goTaskDo   = iota // func()
+	goTaskMap         // func() T
+	goTaskWait        // func() error
+	goTaskRun         // func() (T, error)
+)
+
+var goTaskFuncs = [...]string{
+	goTaskDo:   "Do",
+	goTaskMap:  "Map",
+	goTaskWait: "Wait",
+	goTaskRun:  "Run",
+}
+
+// compileGoExpr compiles a go expression into a call of parallel.Run, Wait, Map
+// or Do, depending on the results of its calls:
+//
+//	go [call for v in container if cond]
+//
+// =>
+//
+//	parallel.Run(func(ctx context.Context) (_xgo_ret []func() (T, error)) {
+//		for _, v := range container {
+//			if cond {
+//				_xgo_ret = append(_xgo_ret, func() (T, error) {
+//					return call
+//				})
+//			}
+//		}
+//		return
+//	})
+func compileGoExpr(ctx *blockCtx, lhs int, v *ast.GoExpr) {
+	const (
+		nameRet = "_xgo_ret"
+	)
+	var elts []ast.Expr
+	var fors []*ast.ForPhrase
+	switch x := v.X.(type) {
+	case *ast.SliceLit:
+		elts = x.Elts
+	case *ast.ComprehensionExpr:
+		if comprehensionKind(x) == comprehensionList {
+			elts, fors = []ast.Expr{x.Elt}, x.Fors
+		}
+	}
+	if len(elts) == 0 {
+		panic(ctx.newCodeError(v.X.Pos(), v.X.End(), "go expression requires a list of calls"))
+	}
+	pkg, cb := ctx.pkg, ctx.cb
+	tyContext := pkg.Import("context").Ref("Context").Type()
+	ctxParam := pkg.NewParam(token.NoPos, checkGoExpr(ctx, elts), tyContext)
+	ret := pkg.NewAutoParam(nameRet)
+	cb.NewClosure(types.NewTuple(ctxParam), types.NewTuple(ret), false).BodyStart(pkg)
+	end := compileForPhrases(ctx, fors)
+
+	// _xgo_ret = append(_xgo_ret, task1, task2, ...)
+	cb.VarRef(ret)
+	cb.Val(pkg.Builtin().Ref("append"))
+	cb.Val(ret)
+	kind := goTaskDo
+	for i, elt := range elts {
+		if lambda, ok := elt.(*ast.LambdaExpr); ok {
+			if rec := ctx.recorder(); rec != nil {
+				rec.Def(lambda.Lhs[0], ctxParam)
+			}
+			elt = lambda.Rhs[0]
+		}
+		if k := compileGoTask(ctx, elt); i == 0 {
+			kind = k
+		}
+	}
+	cb.CallWith(1+len(elts), 0, 0, v.X).Assign(1)
+	for i := 0; i < end; i++ {
+		cb.End()
+	}
+	cb.Return(0).End()
+
+	stk := cb.InternalStack()
+	tasks := stk.Pop()
+	cb.Val(pkg.Import(parallelPkgPath).Ref(goTaskFuncs[kind]))
+	stk.Push(tasks)
+	cb.CallWith(1, lhs, 0, v)
+}
+
+// checkGoExpr checks tasks of a go expression and returns the name of their
+// context parameter, that is the parameter name of its lambda expressions.
+func checkGoExpr(ctx *blockCtx, elts []ast.Expr) string {
+	name := "_"
+	for _, elt := range elts {
+		switch e := elt.(type) {
+		case *ast.LambdaExpr:
+			if len(e.Lhs) != 1 || len(e.Rhs) != 1 {
+				panic(ctx.newCodeError(e.Pos(), e.End(), "lambda in go expression must be of the form ctx => call"))
+			}
+			if lname := e.Lhs[0].Name; name == "_" {
+				name = lname
+			} else if lname != name && lname != "_" {
+				panic(ctx.newCodeErrorf(e.Pos(), e.End(), "mismatched lambda parameters %s and %s in go expression", name, lname))
+			}
+			elt = e.Rhs[0]
+		case *ast.LambdaExpr2:
+			panic(ctx.newCodeError(e.Pos(), e.End(), "lambda in go expression must be of the form ctx => call"))
+		}
+		if e, ok := elt.(*ast.ErrWrapExpr); ok && e.Tok == token.QUESTION && e.Default == nil {
+			panic(ctx.newCodeError(e.Pos(), e.End(), "can't use expr? in go expression"))
+		}
+	}
+	return name
+}
+
+// compileGoTask compiles a task of a go expression into a closure that calls
+// call, and returns the kind of results of the closure.
+func compileGoTask(ctx *blockCtx, call ast.Expr) int {
+	pkg, cb := ctx.pkg, ctx.cb
+	compileExpr(ctx, 1, call)
+	stk := cb.InternalStack()
+	x := stk.Pop()
+	kind := goTaskMap
+	var results []*types.Var
+	switch t := x.Type.(type) {
+	case *types.Tuple:
+		switch n := t.Len(); {
+		case n == 0:
+			kind = goTaskDo
+		case n == 2 && types.Identical(t.At(1).Type(), tyError):
+			kind = goTaskRun
+			results = []*types.Var{
+				pkg.NewParam(token.NoPos, "", t.At(0).Type()),
+				pkg.NewParam(token.NoPos, "", tyError),
+			}
+		default:
+			panic(ctx.newCodeErrorf(call.Pos(), call.End(),
+				"%s (value of type %v) can't be used as a task of go expression", ctx.LoadExpr(call), t))
+		}
+	default:
+		if types.Identical(t, tyError) {
+			kind = goTaskWait
+		}
+		results = []*types.Var{pkg.NewParam(token.NoPos, "", types.Default(t))}
+	}
+	sig := types.NewSignatureType(nil, nil, nil, nil, types.NewTuple(results...), false)
+	cb.NewClosureWith(sig).BodyStart(pkg)
+	stk.Push(x)
+	if kind == goTaskDo {
+		cb.EndStmt()
+	} else {
+		cb.Return(1)
+	}
+	cb.End()
+	return kind
+}
@@ -4,6 +4,26 @@ package unit
 
 type Distance int
 
-const XGou_Distance = "mm=1,cm=10,dm=100,m=1000"
+const XGou_Distance = "mm=1,cm=10,dm=100,m=1000,km=1000000"
+
+// -----------------------------------------------------------------------------
+
+type Time int
+
+const XGou_Time = "ms=1,s=1000,min=60000,h=3600000"
+
+type Speed float64
+
+const (
+	XGou_Speed = "mps=1,kmps=1000"
+	XGod_Speed = "Distance/Time"
+)
+
+type Area int
+
+const (
+	XGou_Area = "mm2=1,cm2=100,m2=1000000"
+	XGod_Area = "Distance*Distance"
+)
 
 // -----------------------------------------------------------------------------
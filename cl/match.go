@@ -0,0 +1,560 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cl
+
+import (
+	"go/constant"
+	gotoken "go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goplus/gogen"
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/token"
+)
+
+// -----------------------------------------------------------------------------
+
+// matchVal represents a value being matched or destructured, that is
+// `root.path[0].path[1]...`.
+type matchVal struct {
+	root *types.Var
+	path []string
+	typ  types.Type
+}
+
+func (p *matchVal) push(cb *gogen.CodeBuilder) {
+	cb.Val(p.root)
+	for _, fld := range p.path {
+		cb.MemberVal(fld, 0)
+	}
+}
+
+func (p *matchVal) field(fld *types.Var) *matchVal {
+	path := make([]string, len(p.path), len(p.path)+1)
+	copy(path, p.path)
+	return &matchVal{root: p.root, path: append(path, fld.Name()), typ: fld.Type()}
+}
+
+type matchBind struct {
+	name *ast.Ident
+	val  *matchVal
+}
+
+// matchPattern holds the result of compiling a pattern: the number of tests
+// pushed onto the stack (already combined by &&) and the bound variables.
+type matchPattern struct {
+	ntest int
+	binds []matchBind
+	cval  constant.Value // constant value of a pattern that is a single comparison
+}
+
+func isConstIdent(ctx *blockCtx, ident *ast.Ident) bool {
+	_, o := ctx.cb.Scope().LookupParent(ident.Name, token.NoPos)
+	if o == nil && ctx.loadSymbol(ident.Name) {
+		o = ctx.pkg.Types.Scope().Lookup(ident.Name)
+	}
+	switch o.(type) {
+	case *types.Const, *types.Nil:
+		return true
+	}
+	return false
+}
+
+func compilePattern(ctx *blockCtx, m *matchPattern, pat ast.Expr, val *matchVal) {
+	cb := ctx.cb
+	switch v := pat.(type) {
+	case *ast.Ident:
+		if v.Name == "_" {
+			return
+		}
+		if !isConstIdent(ctx, v) {
+			for _, b := range m.binds {
+				if b.name.Name == v.Name {
+					panic(ctx.newCodeErrorf(v.Pos(), v.End(), "%s repeated in pattern", v.Name))
+				}
+			}
+			m.binds = append(m.binds, matchBind{v, val})
+			return
+		}
+	case *ast.ParenExpr:
+		compilePattern(ctx, m, v.X, val)
+		return
+	case *ast.TupleLit:
+		t, ok := val.typ.Underlying().(*types.Struct)
+		if !ok || !cb.IsTupleType(val.typ) {
+			panic(ctx.newCodeErrorf(v.Pos(), v.End(), "cannot match %v with tuple pattern %s", val.typ, ctx.LoadExpr(v)))
+		}
+		if n := t.NumFields(); n != len(v.Elts) {
+			panic(ctx.newCodeErrorf(v.Pos(), v.End(), "cannot match %v with tuple pattern %s: expected %d elements, got %d",
+				val.typ, ctx.LoadExpr(v), n, len(v.Elts)))
+		}
+		for i, elt := range v.Elts {
+			compilePattern(ctx, m, elt, val.field(t.Field(i)))
+		}
+		m.cval = nil
+		return
+	case *ast.CompositeLit:
+		compileStructPattern(ctx, m, v, val)
+		m.cval = nil
+		return
+	}
+	val.push(cb)
+	compileExpr(ctx, 1, pat)
+	m.cval = cb.Get(-1).CVal
+	cb.BinaryOp(gotoken.EQL)
+	if m.ntest > 0 {
+		cb.BinaryOp(gotoken.LAND)
+		m.cval = nil
+	}
+	m.ntest++
+}
+
+func compileStructPattern(ctx *blockCtx, m *matchPattern, v *ast.CompositeLit, val *matchVal) {
+	typ := val.typ
+	if v.Type != nil {
+		if t := toType(ctx, v.Type); !types.Identical(t, typ) {
+			panic(ctx.newCodeErrorf(v.Pos(), v.End(), "cannot match %v with pattern of type %v", typ, t))
+		}
+	}
+	if t, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = t.Elem()
+	}
+	t, ok := typ.Underlying().(*types.Struct)
+	if !ok {
+		panic(ctx.newCodeErrorf(v.Pos(), v.End(), "cannot match %v with struct pattern %s", val.typ, ctx.LoadExpr(v)))
+	}
+	for i, elt := range v.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				panic(ctx.newCodeErrorf(kv.Key.Pos(), kv.Key.End(), "invalid field name %s in struct pattern", ctx.LoadExpr(kv.Key)))
+			}
+			idx := ctx.cb.LookupField(t, key.Name)
+			if idx < 0 {
+				panic(ctx.newCodeErrorf(key.Pos(), key.End(), "unknown field %s in struct pattern of type %v", key.Name, typ))
+			}
+			compilePattern(ctx, m, kv.Value, val.field(t.Field(idx)))
+			continue
+		}
+		if i >= t.NumFields() {
+			panic(ctx.newCodeErrorf(elt.Pos(), elt.End(), "too many values in struct pattern of type %v", typ))
+		}
+		compilePattern(ctx, m, elt, val.field(t.Field(i)))
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// match x {
+// case pat1, pat2 if guard:
+//
+//	...
+//
+// default:
+//
+//	...
+//
+// }
+//
+// is compiled into:
+//
+// switch _xgo_m := x; {
+// case (cond1 || cond2) && guard:
+//
+//	bind1 := _xgo_m.field1
+//	...
+//
+// default:
+//
+//	...
+//
+// }
+func compileMatchStmt(ctx *blockCtx, v *ast.MatchStmt) {
+	const nameMatch = "_xgo_m"
+	cb := ctx.cb
+	defer cb.End(v)
+	defer func() {
+		r := recover()
+		if r != nil {
+			ctx.handleRecover(r, v)
+			cb.ResetStmt()
+		}
+	}()
+	comments, once := cb.BackupComments()
+	cb.Switch(v)
+	var subject *types.Var
+	if matchUsesSubject(ctx, v) {
+		cb.DefineVarStart(v.X.Pos(), nameMatch)
+		compileExpr(ctx, 1, v.X)
+		cb.EndInit(1)
+		subject = cb.Scope().Lookup(nameMatch).(*types.Var)
+	} else { // switch _ = x; {...}
+		cb.VarRef(nil)
+		compileExpr(ctx, 1, v.X)
+		subject = types.NewVar(v.X.Pos(), ctx.pkg.Types, "_", types.Default(cb.Get(-1).Type))
+		cb.Assign(1)
+	}
+	cb.None()
+	if rec := ctx.recorder(); rec != nil {
+		rec.Scope(v, cb.Scope())
+	}
+	cb.Then(v.Body)
+	val := &matchVal{root: subject, typ: subject.Type()}
+	seen := make(map[string]bool)
+	var firstDefault ast.Stmt
+	var exhaustive bool
+	for _, stmt := range v.Body.List {
+		c, ok := stmt.(*ast.MatchClause)
+		if !ok {
+			ctx.handleErrorf(stmt.Pos(), stmt.End(), "expected case clause")
+			continue
+		}
+		cb.Case(c)
+		var binds []matchBind
+		if c.List == nil {
+			if firstDefault != nil {
+				ctx.handleErrorf(c.Pos(), c.End(), "multiple defaults in match (first at %v)", ctx.Position(firstDefault.Pos()))
+			} else {
+				firstDefault = c
+			}
+			exhaustive = true
+		} else {
+			binds = compileMatchCond(ctx, c, val, seen, &exhaustive)
+		}
+		cb.Then()
+		defineMatchBinds(ctx, binds, c.Body)
+		compileStmts(ctx, c.Body)
+		commentStmt(ctx, stmt)
+		if rec := ctx.recorder(); rec != nil {
+			rec.Scope(c, cb.Scope())
+		}
+		cb.End(c)
+	}
+	if !exhaustive && ctx.warn != nil {
+		typ := val.typ
+		ctx.inits = append(ctx.inits, func() { // check after all constants are loaded
+			if missing := MissingCases(typ, seen); len(missing) > 0 {
+				ctx.warnf(v.Pos(), v.Body.Lbrace, "match is not exhaustive: missing case %s", strings.Join(missing, ", "))
+			}
+		})
+	}
+	cb.SetComments(comments, once)
+}
+
+func compileMatchCond(ctx *blockCtx, c *ast.MatchClause, val *matchVal, seen map[string]bool, exhaustive *bool) []matchBind {
+	cb := ctx.cb
+	var binds []matchBind
+	var irrefutable bool
+	for i, pat := range c.List {
+		m := new(matchPattern)
+		compilePattern(ctx, m, pat, val)
+		if len(m.binds) > 0 {
+			if len(c.List) > 1 {
+				panic(ctx.newCodeErrorf(pat.Pos(), pat.End(), "cannot bind variables in a case with multiple patterns"))
+			}
+			binds = m.binds
+		}
+		if m.ntest == 0 {
+			irrefutable = true
+			cb.Val(true)
+		} else if m.cval != nil && c.Guard == nil {
+			seen[m.cval.ExactString()] = true
+		}
+		if i > 0 {
+			cb.BinaryOp(gotoken.LOR)
+		}
+	}
+	if c.Guard != nil {
+		old := ctx.guardBinds
+		ctx.guardBinds = make(map[string]*matchVal, len(binds))
+		for _, b := range binds {
+			ctx.guardBinds[b.name.Name] = b.val
+		}
+		compileExpr(ctx, 1, c.Guard)
+		ctx.guardBinds = old
+		if irrefutable && len(c.List) == 1 { // true && guard => guard
+			stk := cb.InternalStack()
+			stk.Ret(2, stk.Get(-1))
+		} else {
+			cb.BinaryOp(gotoken.LAND)
+		}
+	} else if irrefutable {
+		*exhaustive = true
+	}
+	return binds
+}
+
+// defineMatchBinds defines variables bound by a pattern. Only variables used
+// in body are defined to avoid `declared and not used` errors.
+func defineMatchBinds(ctx *blockCtx, binds []matchBind, body []ast.Stmt) {
+	if len(binds) == 0 {
+		return
+	}
+	used := make(map[string]nameUse)
+	for _, stmt := range body {
+		usedNames(used, stmt)
+	}
+	for _, b := range binds {
+		switch used[b.name.Name] {
+		case nameUsed:
+			defineBind(ctx, b)
+		case nameMaybeUsed: // _ = name, in case it isn't used
+			defineBind(ctx, b)
+			cb := ctx.cb
+			cb.VarRef(nil).VarVal(b.name.Name).Assign(1)
+		}
+	}
+}
+
+// nameUse tells how a name is used, see usedNames.
+type nameUse int
+
+const (
+	nameMaybeUsed nameUse = iota + 1 // a key of a composite literal: a variable or a field name
+	nameUsed                         // may refer to a variable
+)
+
+// usedNames collects names of identifiers that may refer to variables in node.
+// Selectors of selector expressions, keyword argument names and labels aren't
+// collected.
+func usedNames(used map[string]nameUse, node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch v := n.(type) {
+		case *ast.SelectorExpr:
+			usedNames(used, v.X)
+			return false
+		case *ast.CompositeLit:
+			if v.Type != nil {
+				usedNames(used, v.Type)
+			}
+			for _, elt := range v.Elts {
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if key, ok := kv.Key.(*ast.Ident); ok {
+						if used[key.Name] == 0 {
+							used[key.Name] = nameMaybeUsed
+						}
+						usedNames(used, kv.Value)
+						continue
+					}
+				}
+				usedNames(used, elt)
+			}
+			return false
+		case *ast.KwargExpr:
+			usedNames(used, v.Value)
+			return false
+		case *ast.LabeledStmt:
+			usedNames(used, v.Stmt)
+			return false
+		case *ast.BranchStmt:
+			return false
+		case *ast.Ident:
+			used[v.Name] = nameUsed
+		}
+		return true
+	})
+}
+
+// matchUsesSubject reports whether the subject of a match statement is
+// referenced by any of its case clauses.
+func matchUsesSubject(ctx *blockCtx, v *ast.MatchStmt) bool {
+	for _, stmt := range v.Body.List {
+		c, ok := stmt.(*ast.MatchClause)
+		if !ok {
+			continue
+		}
+		var used map[string]nameUse
+		isUsed := func(name string) bool {
+			if used == nil {
+				used = make(map[string]nameUse)
+				if c.Guard != nil {
+					usedNames(used, c.Guard)
+				}
+				for _, stmt := range c.Body {
+					usedNames(used, stmt)
+				}
+			}
+			return used[name] != 0
+		}
+		for _, pat := range c.List {
+			if patternUsesValue(ctx, pat, isUsed) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func patternUsesValue(ctx *blockCtx, pat ast.Expr, isUsed func(name string) bool) bool {
+	switch v := pat.(type) {
+	case *ast.Ident:
+		if v.Name == "_" {
+			return false
+		}
+		return isConstIdent(ctx, v) || isUsed(v.Name)
+	case *ast.ParenExpr:
+		return patternUsesValue(ctx, v.X, isUsed)
+	case *ast.TupleLit:
+		for _, elt := range v.Elts {
+			if patternUsesValue(ctx, elt, isUsed) {
+				return true
+			}
+		}
+		return false
+	case *ast.CompositeLit:
+		for _, elt := range v.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				elt = kv.Value
+			}
+			if patternUsesValue(ctx, elt, isUsed) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func defineBind(ctx *blockCtx, b matchBind) {
+	cb := ctx.cb
+	cb.DefineVarStart(b.name.Pos(), b.name.Name)
+	b.val.push(cb)
+	cb.EndInit(1)
+	defNames(ctx, []*ast.Ident{b.name}, cb.Scope())
+}
+
+// MissingCases returns cases that a match statement or a type switch on
+// values of type typ doesn't cover. It only checks types with a finite set of
+// values: bool and named types with constants declared in the same package
+// (seen holds exact values of the constants, see constant.Value.ExactString),
+// and sum types declared by enum (seen holds names of the variants).
+func MissingCases(typ types.Type, seen map[string]bool) (missing []string) {
+	if t, ok := typ.(*types.Basic); ok {
+		if t.Kind() == types.Bool {
+			for _, v := range []string{"true", "false"} {
+				if !seen[v] {
+					missing = append(missing, v)
+				}
+			}
+		}
+		return
+	}
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil {
+		return
+	}
+	sum := sumType(named)
+	scope := obj.Pkg().Scope()
+	names := scope.Names()
+	sort.Strings(names)
+	for _, name := range names {
+		switch o := scope.Lookup(name).(type) {
+		case *types.Const:
+			if sum == nil && types.Identical(o.Type(), typ) && !seen[o.Val().ExactString()] {
+				missing = append(missing, name)
+			}
+		case *types.TypeName:
+			if sum != nil && !types.IsInterface(o.Type()) && types.Implements(o.Type(), sum) && !seen[name] {
+				missing = append(missing, name)
+			}
+		}
+	}
+	return
+}
+
+// sumType returns the underlying interface of a sum type declared by enum,
+// which has only the sealed method that its variants implement.
+func sumType(named *types.Named) *types.Interface {
+	if t, ok := named.Underlying().(*types.Interface); ok && t.NumMethods() == 1 {
+		if t.Method(0).Name() == enumSealedMethod(named.Obj().Name()) {
+			return t
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// compileTupleDefine compiles `(a, (b, c)) := x`.
+func compileTupleDefine(ctx *blockCtx, expr *ast.AssignStmt, tuple *ast.TupleLit) {
+	const nameTuple = "_xgo_t"
+	cb := ctx.cb
+	name := nameTuple
+	for i := 1; cb.Scope().Lookup(name) != nil; i++ {
+		name = nameTuple + strconv.Itoa(i)
+	}
+	tmp := defineTemp(ctx, expr.Pos(), name, expr.Rhs[0])
+	destructTuple(ctx, tuple, &matchVal{root: tmp, typ: tmp.Type()})
+}
+
+func defineTemp(ctx *blockCtx, pos token.Pos, name string, x ast.Expr) *types.Var {
+	cb := ctx.cb
+	cb.DefineVarStart(pos, name)
+	if enableRecover {
+		defer func() {
+			if e := recover(); e != nil {
+				cb.ResetInit()
+				panic(e)
+			}
+		}()
+	}
+	compileExpr(ctx, 1, x)
+	cb.EndInit(1)
+	return cb.Scope().Lookup(name).(*types.Var)
+}
+
+// destructTuple defines variables of a tuple pattern from val.
+func destructTuple(ctx *blockCtx, tuple *ast.TupleLit, val *matchVal) {
+	t, ok := val.typ.Underlying().(*types.Struct)
+	if !ok || !ctx.cb.IsTupleType(val.typ) {
+		panic(ctx.newCodeErrorf(tuple.Pos(), tuple.End(), "cannot destructure %v with tuple pattern %s", val.typ, ctx.LoadExpr(tuple)))
+	}
+	if n := t.NumFields(); n != len(tuple.Elts) {
+		panic(ctx.newCodeErrorf(tuple.Pos(), tuple.End(), "cannot destructure %v with tuple pattern %s: expected %d elements, got %d",
+			val.typ, ctx.LoadExpr(tuple), n, len(tuple.Elts)))
+	}
+	for i, elt := range tuple.Elts {
+		fv := val.field(t.Field(i))
+		switch e := elt.(type) {
+		case *ast.TupleLit:
+			destructTuple(ctx, e, fv)
+		case *ast.Ident:
+			if e.Name != "_" {
+				defineBind(ctx, matchBind{e, fv})
+			}
+		}
+	}
+}
+
+// nameForValue is the name of the value variable of a for phrase with a
+// tuple pattern, eg. `for k, (a, b) in container`.
+const nameForValue = "_xgo_v"
+
+// destructForPhrase defines variables of the tuple pattern of a for phrase.
+func destructForPhrase(ctx *blockCtx, tuple *ast.TupleLit) {
+	v := ctx.cb.Scope().Lookup(nameForValue).(*types.Var)
+	destructTuple(ctx, tuple, &matchVal{root: v, typ: v.Type()})
+}
+
+// -----------------------------------------------------------------------------
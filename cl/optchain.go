@@ -0,0 +1,417 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cl
+
+import (
+	gotoken "go/token"
+	"go/types"
+	"strconv"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/token"
+)
+
+// An optional chain is a chain of selectors, index expressions, calls, slice
+// expressions and type assertions that contains an optional selector x?.sel.
+// Its links are the operands x of its optional selectors and its index
+// expressions. A nil operand, a missing map entry or an out of range index
+// short-circuits the chain.
+
+// optChainVal is the value of a link of an optional chain being compiled.
+type optChainVal struct {
+	x     *types.Var
+	index *types.Var // x[index] if not nil
+}
+
+// compileOptChainExpr compiles expr if it is an optional chain or a link of
+// the optional chain being compiled, and reports whether it did.
+func compileOptChainExpr(ctx *blockCtx, lhs int, expr ast.Expr) bool {
+	switch v := expr.(type) {
+	case *ast.OptChainExpr, *ast.IndexExpr, *ast.TypeAssertExpr:
+		if val, ok := ctx.optVals[expr]; ok {
+			cb := ctx.cb
+			cb.Val(val.x, expr)
+			if val.index != nil {
+				cb.Val(val.index).Index(1, 0, expr)
+			}
+			return true
+		}
+		if v, ok := v.(*ast.OptChainExpr); ok {
+			if isErrWrapOpt(ctx, v) { // f()?.sel means (f()?).sel
+				compileErrWrapExpr(ctx, lhs, &ast.ErrWrapExpr{X: v.X, Tok: token.QUESTION, TokPos: v.Quest}, 0)
+				return true
+			}
+			panic(ctx.newCodeErrorf(v.Pos(), v.End(), "cannot use optional chaining %s?. here", ctx.LoadExpr(v.X)))
+		}
+	case *ast.SelectorExpr, *ast.CallExpr, *ast.SliceExpr:
+	default:
+		return false
+	}
+	if !isOptChain(ctx, expr) {
+		return false
+	}
+	compileOptChain(ctx, lhs, expr, nil, expr)
+	return true
+}
+
+// isOptChain reports whether x is an optional chain which isn't being
+// compiled. The chain is walked once from its root: if x isn't an optional
+// chain, the operands walked are marked so that compiling them doesn't walk
+// the rest of the chain again.
+func isOptChain(ctx *blockCtx, x ast.Expr) bool {
+	if _, ok := ctx.optChecked[x]; ok {
+		delete(ctx.optChecked, x)
+		return false
+	}
+	var operands []ast.Expr
+	for e := x; ; {
+		if _, ok := ctx.optVals[e]; ok { // a link of the chain being compiled
+			break
+		}
+		if v, ok := e.(*ast.OptChainExpr); ok {
+			if !isErrWrapOpt(ctx, v) {
+				return true
+			}
+			break
+		}
+		if e = chainOperand(e); e == nil {
+			break
+		}
+		operands = append(operands, e)
+	}
+	if len(operands) > 0 {
+		if ctx.optChecked == nil {
+			ctx.optChecked = make(map[ast.Expr]none)
+		}
+		for _, e := range operands {
+			ctx.optChecked[e] = none{}
+		}
+	}
+	return false
+}
+
+// chainOperand returns the operand of x if x is a selector, index
+// expression, call, slice expression or type assertion, or nil otherwise.
+func chainOperand(x ast.Expr) ast.Expr {
+	switch v := x.(type) {
+	case *ast.SelectorExpr:
+		return v.X
+	case *ast.IndexExpr:
+		return v.X
+	case *ast.CallExpr:
+		return v.Fun
+	case *ast.SliceExpr:
+		return v.X
+	case *ast.TypeAssertExpr:
+		return v.X
+	}
+	return nil
+}
+
+// endOptChain forgets links of the optional chain x and the marks of its
+// operands, see isOptChain.
+func endOptChain(ctx *blockCtx, x ast.Expr, links []ast.Expr) {
+	for _, link := range links {
+		delete(ctx.optVals, link)
+	}
+	for x != nil {
+		delete(ctx.optChecked, x)
+		if v, ok := x.(*ast.OptChainExpr); ok {
+			x = v.X
+		} else {
+			x = chainOperand(x)
+		}
+	}
+}
+
+// isErrWrapOpt reports whether x?.sel is an error wrapping expression x?
+// followed by a selector rather than an optional selector, that is, x is a
+// call of a function returning multiple values, the last of which is an
+// error.
+func isErrWrapOpt(ctx *blockCtx, v *ast.OptChainExpr) bool {
+	call, ok := v.X.(*ast.CallExpr)
+	if !ok || !isQualifiedName(call.Fun) {
+		return false
+	}
+	if ret, ok := ctx.errWrapOpts[v]; ok {
+		return ret
+	}
+	// call.Fun is a (qualified) name, so compiling it has no side effects
+	compileExpr(ctx, 1, call.Fun, clInCallExpr)
+	fn := ctx.cb.InternalStack().Pop()
+	ret := false
+	if sig, ok := fn.Type.(*types.Signature); ok {
+		results := sig.Results()
+		n := results.Len()
+		ret = n > 1 && types.Identical(results.At(n-1).Type(), tyError)
+	}
+	if ctx.errWrapOpts == nil {
+		ctx.errWrapOpts = make(map[*ast.OptChainExpr]bool)
+	}
+	ctx.errWrapOpts[v] = ret
+	return ret
+}
+
+func isQualifiedName(x ast.Expr) bool {
+	for {
+		switch v := x.(type) {
+		case *ast.Ident:
+			return true
+		case *ast.SelectorExpr:
+			x = v.X
+		default:
+			return false
+		}
+	}
+}
+
+// optChainLinks returns links of the chain x, from the innermost one.
+func optChainLinks(x ast.Expr) (links []ast.Expr) {
+	for {
+		switch v := x.(type) {
+		case *ast.SelectorExpr:
+			x = v.X
+		case *ast.IndexExpr:
+			links = append(links, v)
+			x = v.X
+		case *ast.CallExpr:
+			x = v.Fun
+		case *ast.SliceExpr:
+			x = v.X
+		case *ast.TypeAssertExpr:
+			if _, ok := v.X.(*ast.OptChainExpr); ok { // x?.(T)
+				links = append(links, v)
+			}
+			x = v.X
+		case *ast.OptChainExpr:
+			links = append(links, v)
+			x = v.X
+		default:
+			for i, j := 0, len(links)-1; i < j; i, j = i+1, j-1 {
+				links[i], links[j] = links[j], links[i]
+			}
+			return
+		}
+	}
+}
+
+// compileOptChain compiles an optional chain x, or x ?? fallback if fallback
+// isn't nil:
+//
+//	a?.b.c?.d ?? fallback
+//
+// =>
+//
+//	func() (_xgo_ret T) {
+//		if _xgo_1 := a; _xgo_1 != nil {
+//			if _xgo_2 := _xgo_1.b.c; _xgo_2 != nil {
+//				_xgo_ret = _xgo_2.d
+//				if _xgo_ret != nil {
+//					return
+//				}
+//			}
+//		}
+//		return fallback
+//	}()
+func compileOptChain(ctx *blockCtx, lhs int, x, fallback ast.Expr, src ast.Expr) {
+	const (
+		nameRet = "_xgo_ret"
+	)
+	pkg, cb := ctx.pkg, ctx.cb
+	ret := pkg.NewAutoParam(nameRet)
+	cb.NewClosure(nil, types.NewTuple(ret), false).BodyStart(pkg)
+	body, bound, closed := cb.Scope(), false, false
+	defer func() {
+		if closed {
+			return
+		}
+		if e := recover(); e != nil {
+			// complete the closure to report the compile error only
+			for cb.ResetStmt(); cb.Scope() != body; cb.ResetStmt() {
+				cb.End()
+			}
+			if !bound {
+				cb.VarRef(ret).Val(0).Assign(1)
+			}
+			cb.Return(0).End()
+			panic(e)
+		}
+	}()
+	links := optChainLinks(x)
+	end := compileOptChainLinks(ctx, links)
+	cb.VarRef(ret)
+	compileExpr(ctx, 1, x)
+	typ := cb.Get(-1).Type
+	cb.Assign(1)
+	bound = true
+	if fallback != nil {
+		if isNilable(typ) {
+			cb.If().Val(ret).CompareNil(gotoken.NEQ).Then().Return(0).End()
+		} else if end > 0 {
+			cb.Return(0)
+		} else {
+			panic(ctx.newCodeErrorf(x.Pos(), x.End(), "invalid operation: operator ?? not defined on %s (type %v)", ctx.LoadExpr(x), typ))
+		}
+	}
+	for i := 0; i < end; i++ {
+		cb.End()
+	}
+	if fallback != nil {
+		compileExpr(ctx, 1, fallback)
+		cb.Return(1)
+	} else {
+		cb.Return(0)
+	}
+	closed = true
+	cb.End().CallWith(0, lhs, 0, src)
+	endOptChain(ctx, x, links)
+}
+
+// compileOptChainStmt compiles an optional chain x used as a statement:
+//
+//	a?.b.c?.d()
+//
+// =>
+//
+//	if _xgo_1 := a; _xgo_1 != nil {
+//		if _xgo_2 := _xgo_1.b.c; _xgo_2 != nil {
+//			_xgo_2.d()
+//		}
+//	}
+func compileOptChainStmt(ctx *blockCtx, x ast.Expr) {
+	cb := ctx.cb
+	links := optChainLinks(x)
+	end := compileOptChainLinks(ctx, links)
+	compileExpr(ctx, 0, x)
+	cb.EndStmt()
+	for i := 0; i < end; i++ {
+		cb.End()
+	}
+	endOptChain(ctx, x, links)
+}
+
+// compileOptChainLinks compiles links of an optional chain into nested if
+// statements that check them, and returns the number of if statements.
+func compileOptChainLinks(ctx *blockCtx, links []ast.Expr) (end int) {
+	pkg, cb := ctx.pkg, ctx.cb
+	stk := cb.InternalStack()
+	if ctx.optVals == nil {
+		ctx.optVals = make(map[ast.Expr]optChainVal)
+	}
+	n := 0
+	newName := func() string {
+		n++
+		return "_xgo_" + strconv.Itoa(n)
+	}
+	for _, link := range links {
+		switch v := link.(type) {
+		case *ast.OptChainExpr:
+			compileExpr(ctx, 1, v.X)
+			x := stk.Pop()
+			if !isNilable(x.Type) {
+				panic(ctx.newCodeErrorf(v.Pos(), v.End(), "invalid operation: operator ?. not defined on %s (type %v)", ctx.LoadExpr(v.X), x.Type))
+			}
+			// if _xgo_N := x; _xgo_N != nil {
+			name := newName()
+			cb.If().DefineVarStart(0, name)
+			stk.Push(x)
+			cb.EndInit(1)
+			varX := cb.Scope().Lookup(name).(*types.Var)
+			cb.Val(varX).CompareNil(gotoken.NEQ).Then()
+			ctx.optVals[v] = optChainVal{x: varX}
+			end++
+		case *ast.TypeAssertExpr:
+			// if _xgo_N, _xgo_ok := x.(T); _xgo_ok && _xgo_N != nil {
+			compileExpr(ctx, 1, v.X)
+			if v.Type == nil {
+				panic(ctx.newCodeError(v.Pos(), v.End(), "use of .(type) outside type switch"))
+			}
+			typ := toType(ctx, v.Type)
+			cb.TypeAssert(typ, 2, v)
+			val := stk.Pop()
+			name := newName()
+			cb.If().DefineVarStart(0, name, "_xgo_ok")
+			stk.Push(val)
+			cb.EndInit(1)
+			varX := cb.Scope().Lookup(name).(*types.Var)
+			cb.VarVal("_xgo_ok")
+			if isNilable(typ) { // a typed nil in the interface is missing too
+				cb.Val(varX).CompareNil(gotoken.NEQ).BinaryOp(gotoken.LAND)
+			}
+			cb.Then()
+			ctx.optVals[v] = optChainVal{x: varX}
+			end++
+		case *ast.IndexExpr:
+			compileExpr(ctx, 1, v.X)
+			x := stk.Pop()
+			switch t := x.Type.Underlying().(type) {
+			case *types.Map:
+				// if _xgo_N, _xgo_ok := x[index]; _xgo_ok {
+				stk.Push(x)
+				compileExpr(ctx, 1, v.Index)
+				cb.Index(1, 2, v)
+				val := stk.Pop()
+				name := newName()
+				cb.If().DefineVarStart(0, name, "_xgo_ok")
+				stk.Push(val)
+				cb.EndInit(1)
+				varX := cb.Scope().Lookup(name).(*types.Var)
+				cb.VarVal("_xgo_ok").Then()
+				ctx.optVals[v] = optChainVal{x: varX}
+				end++
+				continue
+			case *types.Pointer:
+				if _, ok := t.Elem().Underlying().(*types.Array); ok {
+					break
+				}
+				continue
+			case *types.Slice, *types.Array:
+			case *types.Basic:
+				if t.Info()&types.IsString == 0 {
+					continue
+				}
+			default: // not an index operation, eg. generic instantiation
+				continue
+			}
+			// if _xgo_N, _xgo_M := x, index; _xgo_M >= 0 && _xgo_M < len(_xgo_N) {
+			compileExpr(ctx, 1, v.Index)
+			index := stk.Pop()
+			nameX, nameIdx := newName(), newName()
+			cb.If().DefineVarStart(0, nameX, nameIdx)
+			stk.Push(x)
+			stk.Push(index)
+			cb.EndInit(2)
+			scope := cb.Scope()
+			varX := scope.Lookup(nameX).(*types.Var)
+			varIdx := scope.Lookup(nameIdx).(*types.Var)
+			cb.Val(varIdx).Val(0).BinaryOp(gotoken.GEQ).
+				Val(varIdx).Val(pkg.Builtin().Ref("len")).Val(varX).CallWith(1, 1, 0).BinaryOp(gotoken.LSS).
+				BinaryOp(gotoken.LAND).Then()
+			ctx.optVals[v] = optChainVal{x: varX, index: varIdx}
+			end++
+		}
+	}
+	return
+}
+
+func isNilable(typ types.Type) bool {
+	switch typ.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Map, *types.Slice, *types.Signature, *types.Chan:
+		return true
+	}
+	return false
+}
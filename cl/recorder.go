@@ -274,6 +274,8 @@ func (rec *goxRecorder) recordExpr(ctx *blockCtx, expr ast.Expr, _ bool) {
 	case *ast.FuncLit:
 	case *ast.CompositeLit:
 	case *ast.SliceLit:
+	case *ast.MatrixLit:
+		rec.recordTypeValue(ctx, v, typesutil.Value)
 	case *ast.RangeExpr:
 	case *ast.IndexExpr:
 		rec.indexExpr(ctx, v)
@@ -298,6 +300,8 @@ func (rec *goxRecorder) recordExpr(ctx *blockCtx, expr ast.Expr, _ bool) {
 	case *ast.ParenExpr:
 		rec.recordTypeValue(ctx, v, typesutil.Value)
 	case *ast.ErrWrapExpr:
+	case *ast.OptChainExpr:
+		rec.recordTypeValue(ctx, v, typesutil.Variable)
 	case *ast.FuncType:
 		rec.recordTypeValue(ctx, v, typesutil.TypExpr)
 	case *ast.Ellipsis:
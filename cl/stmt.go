@@ -21,6 +21,7 @@ import (
 	"go/constant"
 	"log"
 	"path/filepath"
+	"strings"
 
 	goast "go/ast"
 	gotoken "go/token"
@@ -132,6 +133,10 @@ func compileStmt(ctx *blockCtx, stmt ast.Stmt) {
 	switch v := stmt.(type) {
 	case *ast.ExprStmt:
 		x := v.X
+		if isOptChain(ctx, x) { // a?.b.c()
+			compileOptChainStmt(ctx, x)
+			break
+		}
 		inFlags := checkCommandWithoutArgs(x)
 		compileExpr(ctx, 0, x, inFlags)
 	case *ast.AssignStmt:
@@ -142,6 +147,8 @@ func compileStmt(ctx *blockCtx, stmt ast.Stmt) {
 		compileIfStmt(ctx, v)
 	case *ast.SwitchStmt:
 		compileSwitchStmt(ctx, v)
+	case *ast.MatchStmt:
+		compileMatchStmt(ctx, v)
 	case *ast.RangeStmt:
 		compileRangeStmt(ctx, v)
 	case *ast.ForStmt:
@@ -235,6 +242,9 @@ func compileReturnStmt(ctx *blockCtx, expr *ast.ReturnStmt) {
 			case *ast.SliceLit:
 				rtyp := ctx.cb.Func().Type().(*types.Signature).Results().At(i).Type()
 				compileSliceLit(ctx, v, rtyp)
+			case *ast.MatrixLit:
+				rtyp := ctx.cb.Func().Type().(*types.Signature).Results().At(i).Type()
+				compileMatrixLit(ctx, v, rtyp)
 			default:
 				compileExpr(ctx, lhs, ret)
 			}
@@ -304,6 +314,12 @@ func compileAssignStmt(ctx *blockCtx, expr *ast.AssignStmt) {
 		lhs = len(expr.Lhs)
 	}
 	if tok == token.DEFINE {
+		if len(expr.Lhs) == 1 && len(expr.Rhs) == 1 {
+			if tuple, ok := expr.Lhs[0].(*ast.TupleLit); ok { // (a, b) := x
+				compileTupleDefine(ctx, expr, tuple)
+				return
+			}
+		}
 		stk := ctx.cb.InternalStack()
 		base := stk.Len()
 		names := make([]string, len(expr.Lhs))
@@ -312,7 +328,7 @@ func compileAssignStmt(ctx *blockCtx, expr *ast.AssignStmt) {
 				names[i] = v.Name
 			} else {
 				compileExprLHS(ctx, lhs) // only for typesutil.Check
-				log.Panicln("TODO: non-name $v on left side of :=")
+				panic(ctx.newCodeErrorf(lhs.Pos(), lhs.End(), "non-name %s on left side of :=", ctx.LoadExpr(lhs)))
 			}
 		}
 		if rec := ctx.recorder(); rec != nil {
@@ -341,6 +357,10 @@ func compileAssignStmt(ctx *blockCtx, expr *ast.AssignStmt) {
 		ctx.cb.EndInit(stk.Len() - base)
 		return
 	}
+	if tok != token.ASSIGN && (len(expr.Lhs) != 1 || len(expr.Rhs) != 1) {
+		ctx.handleErrorf(expr.Pos(), expr.End(), "assignment operation %v requires single-valued expressions", tok)
+		return
+	}
 	for _, lhs := range expr.Lhs {
 		compileExprLHS(ctx, lhs)
 	}
@@ -369,6 +389,12 @@ func compileAssignStmt(ctx *blockCtx, expr *ast.AssignStmt) {
 				typ, _ = gogen.DerefType(ctx.cb.Get(-1 - i).Type)
 			}
 			compileCompositeLit(ctx, e, typ, false)
+		case *ast.MatrixLit:
+			var typ types.Type
+			if len(expr.Lhs) == len(expr.Rhs) {
+				typ, _ = gogen.DerefType(ctx.cb.Get(-1 - i).Type)
+			}
+			compileMatrixLit(ctx, e, typ)
 		default:
 			compileExpr(ctx, lhs, rhs)
 		}
@@ -377,9 +403,6 @@ func compileAssignStmt(ctx *blockCtx, expr *ast.AssignStmt) {
 		ctx.cb.AssignWith(len(expr.Lhs), len(expr.Rhs), expr)
 		return
 	}
-	if len(expr.Lhs) != 1 || len(expr.Rhs) != 1 {
-		panic("TODO: invalid syntax of assign by operator")
-	}
 	ctx.cb.AssignOp(gotoken.Token(tok), expr)
 }
 
@@ -469,6 +492,10 @@ func compileRangeStmt(ctx *blockCtx, v *ast.RangeStmt) {
 
 func compileForPhraseStmt(ctx *blockCtx, v *ast.ForPhraseStmt) {
 	if re, ok := v.X.(*ast.RangeExpr); ok {
+		if v.Tuple != nil {
+			ctx.handleErrorf(v.Tuple.Pos(), v.Tuple.End(), "cannot destructure range value with tuple pattern %s", ctx.LoadExpr(v.Tuple))
+			return
+		}
 		compileForStmt(ctx, toForStmt(v.For, v.Value, v.Body, re, token.DEFINE, v.ForPhrase))
 		return
 	}
@@ -493,6 +520,8 @@ func compileForPhraseStmt(ctx *blockCtx, v *ast.ForPhraseStmt) {
 	if v.Value != nil {
 		names = append(names, v.Value.Name)
 		defineNames = append(defineNames, v.Value)
+	} else if v.Tuple != nil {
+		names = append(names, nameForValue)
 	}
 	cb.ForRange(names...)
 	compileExpr(ctx, 1, v.X)
@@ -500,6 +529,9 @@ func compileForPhraseStmt(ctx *blockCtx, v *ast.ForPhraseStmt) {
 	if len(defineNames) > 0 {
 		defNames(ctx, defineNames, cb.Scope())
 	}
+	if v.Tuple != nil {
+		destructForPhrase(ctx, v.Tuple)
+	}
 	if rec := ctx.recorder(); rec != nil {
 		rec.Scope(v, cb.Scope())
 	}
@@ -700,6 +732,36 @@ func compileIfStmt(ctx *blockCtx, v *ast.IfStmt) {
 	}
 }
 
+// typeSwitchGuard checks the guard `x := y.(type)` or `y.(type)` of a type
+// switch statement.
+func typeSwitchGuard(ctx *blockCtx, v *ast.TypeSwitchStmt) (name string, ta *ast.TypeAssertExpr, ok bool) {
+	var x ast.Expr
+	switch stmt := v.Assign.(type) {
+	case *ast.AssignStmt:
+		if stmt.Tok != token.DEFINE || len(stmt.Lhs) != 1 || len(stmt.Rhs) != 1 {
+			ctx.handleErrorf(stmt.Pos(), stmt.End(), "invalid type switch guard: %s", ctx.LoadExpr(stmt))
+			return
+		}
+		id, isIdent := stmt.Lhs[0].(*ast.Ident)
+		if !isIdent {
+			lhs := stmt.Lhs[0]
+			ctx.handleErrorf(lhs.Pos(), lhs.End(), "non-name %s on left side of :=", ctx.LoadExpr(lhs))
+			return
+		}
+		name, x = id.Name, stmt.Rhs[0]
+	case *ast.ExprStmt:
+		x = stmt.X
+	default:
+		ctx.handleErrorf(v.Pos(), v.Body.Pos(), "invalid type switch guard")
+		return
+	}
+	if ta, ok = x.(*ast.TypeAssertExpr); !ok || ta.Type != nil {
+		ctx.handleErrorf(x.Pos(), x.End(), "%s is not a type switch guard, please use x.(type)", ctx.LoadExpr(x))
+		return "", nil, false
+	}
+	return
+}
+
 // typeSwitch(name) init; expr typeAssertThen()
 // type1 type2 ... typeN typeCase(N)
 //
@@ -713,6 +775,17 @@ func compileIfStmt(ctx *blockCtx, v *ast.IfStmt) {
 //
 // end
 func compileTypeSwitchStmt(ctx *blockCtx, v *ast.TypeSwitchStmt) {
+	name, ta, ok := typeSwitchGuard(ctx, v)
+	if !ok { // still compile the body to report errors in it
+		for _, stmt := range v.Body.List {
+			if c, ok := stmt.(*ast.CaseClause); ok {
+				ctx.cb.Block()
+				compileStmts(ctx, c.Body)
+				ctx.cb.End()
+			}
+		}
+		return
+	}
 	var cb = ctx.cb
 	defer cb.End(v)
 	defer func() {
@@ -723,21 +796,6 @@ func compileTypeSwitchStmt(ctx *blockCtx, v *ast.TypeSwitchStmt) {
 		}
 	}()
 	comments, once := cb.BackupComments()
-	var name string
-	var ta *ast.TypeAssertExpr
-	switch stmt := v.Assign.(type) {
-	case *ast.AssignStmt:
-		if stmt.Tok != token.DEFINE || len(stmt.Lhs) != 1 || len(stmt.Rhs) != 1 {
-			panic("TODO: type switch syntax error")
-		}
-		name = stmt.Lhs[0].(*ast.Ident).Name
-		ta = stmt.Rhs[0].(*ast.TypeAssertExpr)
-	case *ast.ExprStmt:
-		ta = stmt.X.(*ast.TypeAssertExpr)
-	}
-	if ta.Type != nil {
-		panic("TODO: type switch syntax error, please use x.(type)")
-	}
 	cb.TypeSwitch(name, v)
 	if rec := ctx.recorder(); rec != nil {
 		rec.Scope(v, cb.Scope())
@@ -746,13 +804,15 @@ func compileTypeSwitchStmt(ctx *blockCtx, v *ast.TypeSwitchStmt) {
 		compileStmt(ctx, v.Init)
 	}
 	compileExpr(ctx, 1, ta.X)
+	typ := cb.Get(-1).Type
 	cb.TypeAssertThen()
 	seen := make(map[types.Type]ast.Expr)
 	var firstDefault ast.Stmt
 	for _, stmt := range v.Body.List {
 		c, ok := stmt.(*ast.CaseClause)
 		if !ok {
-			log.Panicln("TODO: compile TypeSwitchStmt failed - case clause expected.")
+			ctx.handleErrorf(stmt.Pos(), stmt.End(), "expected case clause")
+			continue
 		}
 		cb.TypeCase(c)
 		for _, citem := range c.List {
@@ -798,6 +858,19 @@ func compileTypeSwitchStmt(ctx *blockCtx, v *ast.TypeSwitchStmt) {
 		}
 		cb.End(c)
 	}
+	if firstDefault == nil && ctx.warn != nil {
+		ctx.inits = append(ctx.inits, func() { // check after all variants are loaded
+			variants := make(map[string]bool, len(seen))
+			for t := range seen {
+				if named, ok := t.(*types.Named); ok {
+					variants[named.Obj().Name()] = true
+				}
+			}
+			if missing := MissingCases(typ, variants); len(missing) > 0 {
+				ctx.warnf(v.Pos(), v.Body.Lbrace, "type switch is not exhaustive: missing case %s", strings.Join(missing, ", "))
+			}
+		})
+	}
 	cb.SetComments(comments, once)
 }
 
@@ -842,12 +915,16 @@ func compileSwitchStmt(ctx *blockCtx, v *ast.SwitchStmt) {
 	for _, stmt := range v.Body.List {
 		c, ok := stmt.(*ast.CaseClause)
 		if !ok {
-			log.Panicln("TODO: compile SwitchStmt failed - case clause expected.")
+			ctx.handleErrorf(stmt.Pos(), stmt.End(), "expected case clause")
+			continue
 		}
 		cb.Case(c)
 		for _, citem := range c.List {
 			compileExpr(ctx, 1, citem)
 			v := cb.Get(-1)
+			if _, ok := v.Type.(*gogen.TypeType); ok {
+				panic(ctx.newCodeErrorf(citem.Pos(), citem.End(), "%s (type) is not an expression", ctx.LoadExpr(citem)))
+			}
 			if val := goVal(v.CVal); val != nil {
 				// look for duplicate types for a given value
 				// (quadratic algorithm, but these lists tend to be very short)
@@ -935,7 +1012,8 @@ func compileSelectStmt(ctx *blockCtx, v *ast.SelectStmt) {
 	for _, stmt := range v.Body.List {
 		c, ok := stmt.(*ast.CommClause)
 		if !ok {
-			log.Panicln("TODO: compile SelectStmt failed - comm clause expected.")
+			ctx.handleErrorf(stmt.Pos(), stmt.End(), "expected comm clause")
+			continue
 		}
 		cb.CommCase(c)
 		if c.Comm != nil {
@@ -973,7 +1051,7 @@ func compileBranchStmt(ctx *blockCtx, v *ast.BranchStmt) {
 	case token.FALLTHROUGH:
 		ctx.handleErrorf(v.Pos(), v.End(), "fallthrough statement out of place")
 	default:
-		panic("unknown branch statement")
+		ctx.handleErrorf(v.Pos(), v.End(), "unknown branch statement %v", v.Tok)
 	}
 }
 
@@ -1020,7 +1098,7 @@ func compileDeclStmt(ctx *blockCtx, expr *ast.DeclStmt) {
 				loadVars(ctx, v, d.Doc, false)
 			}
 		default:
-			log.Panicln("TODO: compileDeclStmt - unknown")
+			ctx.handleErrorf(d.Pos(), d.End(), "unexpected %v declaration in function body", d.Tok)
 		}
 	}
 }
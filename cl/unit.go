@@ -0,0 +1,414 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cl
+
+import (
+	goast "go/ast"
+	"go/constant"
+	gotoken "go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goplus/gogen"
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/cl/internal/typesutil"
+	"github.com/goplus/xgo/token"
+)
+
+// -----------------------------------------------------------------------------
+
+// A package declares a unit system by declaring types with units:
+//
+//	type Distance int
+//	type Time int
+//	type Speed float64
+//
+//	const (
+//		XGou_Distance = "mm=1,cm=10,m=1000,km=1000000"
+//		XGou_Time     = "ms=1,s=1000,min=60000"
+//		XGou_Speed    = "mps=1,kmps=1000"
+//	)
+//
+// The value of a unit is its conversion factor to the base unit of the type.
+// A type without a dimension declaration is a base quantity. A derived
+// quantity declares its dimension in terms of other types with units:
+//
+//	const XGod_Speed = "Distance/Time"
+//
+// The base unit of a derived quantity is derived from the base units of its
+// dimension, eg. the base unit of Speed above is mm/ms (that is m/s).
+const (
+	unitsPrefix = "XGou_"
+	dimPrefix   = "XGod_"
+
+	durationUnits = "ns=1,us=1000,µs=1000,ms=1000000,s=1000000000,m=60000000000,h=3600000000000,d=86400000000000"
+)
+
+// dimension represents the dimension of a quantity type by exponents of base
+// quantity types.
+type dimension map[*types.TypeName]int
+
+func (a dimension) equal(b dimension) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// combine returns the dimension of a*b (sign = 1) or a/b (sign = -1).
+func (a dimension) combine(b dimension, sign int) dimension {
+	ret := make(dimension, len(a)+len(b))
+	for k, v := range a {
+		ret[k] = v
+	}
+	for k, v := range b {
+		if n := ret[k] + sign*v; n != 0 {
+			ret[k] = n
+		} else {
+			delete(ret, k)
+		}
+	}
+	return ret
+}
+
+// unitType represents a type with units.
+type unitType struct {
+	obj   *types.TypeName
+	units map[string]constant.Value // unit => conversion factor to the base unit
+	dim   dimension
+}
+
+func (p *blockCtx) unitTypeOf(t types.Type) *unitType {
+	if named, ok := t.(*types.Named); ok {
+		return p.unitTypeOfObj(named.Obj())
+	}
+	return nil
+}
+
+func (p *blockCtx) unitTypeOfObj(obj *types.TypeName) *unitType {
+	if ut, ok := p.units[obj]; ok {
+		return ut
+	}
+	if p.units == nil {
+		p.units = make(map[*types.TypeName]*unitType)
+	}
+	p.units[obj] = nil // avoid infinite recursion of cyclic dimensions
+	pkg := obj.Pkg()
+	if pkg == nil {
+		return nil
+	}
+	units, ok := p.unitConst(pkg, unitsPrefix+obj.Name())
+	if !ok {
+		if pkg.Path() != "time" || obj.Name() != "Duration" {
+			return nil
+		}
+		units = durationUnits
+	}
+	ut := &unitType{obj: obj, units: parseUnits(units)}
+	if dim, ok := p.unitConst(pkg, dimPrefix+obj.Name()); ok {
+		ut.dim = p.parseDim(pkg, dim)
+	}
+	if ut.dim == nil {
+		ut.dim = dimension{obj: 1}
+	}
+	p.units[obj] = ut
+	return ut
+}
+
+func (p *blockCtx) unitConst(pkg *types.Package, name string) (string, bool) {
+	if pkg == p.pkg.Types {
+		p.loadSymbol(name)
+	}
+	if c, ok := pkg.Scope().Lookup(name).(*types.Const); ok {
+		if v := c.Val(); v.Kind() == constant.String {
+			return constant.StringVal(v), true
+		}
+	}
+	return "", false
+}
+
+// unitTypeString returns the string form of t, qualified by package names.
+func (p *blockCtx) unitTypeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg == p.pkg.Types {
+			return ""
+		}
+		return pkg.Name()
+	})
+}
+
+// parseUnits parses units like "mm=1,cm=10,m=1000".
+func parseUnits(v string) map[string]constant.Value {
+	units := strings.Split(v, ",")
+	ret := make(map[string]constant.Value, len(units))
+	for _, unit := range units {
+		if pos := strings.Index(unit, "="); pos > 0 {
+			name, factor := strings.TrimSpace(unit[:pos]), strings.TrimSpace(unit[pos+1:])
+			val := constant.MakeFromLiteral(factor, gotoken.INT, 0)
+			if val.Kind() == constant.Unknown {
+				val = constant.MakeFromLiteral(factor, gotoken.FLOAT, 0)
+			}
+			if val.Kind() != constant.Unknown {
+				ret[name] = val
+			}
+		}
+	}
+	return ret
+}
+
+// parseDim parses a dimension like "Distance/Time". It returns nil if the
+// dimension is invalid.
+func (p *blockCtx) parseDim(pkg *types.Package, v string) dimension {
+	dim := make(dimension)
+	sign := 1
+	for {
+		name, next := v, ""
+		if pos := strings.IndexAny(v, "*/"); pos >= 0 {
+			name, next = v[:pos], v[pos:]
+		}
+		name = strings.TrimSpace(name)
+		if pkg == p.pkg.Types {
+			p.loadSymbol(name)
+		}
+		obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			return nil
+		}
+		ut := p.unitTypeOfObj(obj)
+		if ut == nil {
+			return nil
+		}
+		dim = dim.combine(ut.dim, sign)
+		if next == "" {
+			return dim
+		}
+		if next[0] == '*' {
+			sign = 1
+		} else {
+			sign = -1
+		}
+		v = next[1:]
+	}
+}
+
+// unitTypes returns all types with units declared in this package or imported
+// packages.
+func (p *blockCtx) unitTypes() (ret []*unitType) {
+	seen := make(map[*types.TypeName]bool)
+	add := func(pkg *types.Package, name string) {
+		if obj, ok := pkg.Scope().Lookup(name).(*types.TypeName); ok && !seen[obj] {
+			seen[obj] = true
+			if ut := p.unitTypeOfObj(obj); ut != nil {
+				ret = append(ret, ut)
+			}
+		}
+	}
+	addPkg := func(pkg *types.Package, names []string) {
+		if pkg.Path() == "time" {
+			add(pkg, "Duration")
+		}
+		for _, name := range names {
+			if strings.HasPrefix(name, unitsPrefix) {
+				if pkg == p.pkg.Types {
+					p.loadSymbol(name)
+				}
+				add(pkg, name[len(unitsPrefix):])
+			}
+		}
+	}
+	this := p.pkg.Types
+	names := this.Scope().Names()
+	for name := range p.syms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	addPkg(this, names)
+	pkgs := make([]*types.Package, 0, len(p.imports))
+	for _, imps := range []map[string]pkgImp{p.imports, p.autoimps} {
+		for _, pi := range imps {
+			if pi.Types != nil {
+				pkgs = append(pkgs, pi.Types)
+			}
+		}
+	}
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].Path() < pkgs[j].Path()
+	})
+	for _, pkg := range pkgs {
+		addPkg(pkg, pkg.Scope().Names())
+	}
+	return
+}
+
+// lookupUnitType returns the type that has the unit of v. If there are more
+// than one such types, the type that also has unit `also` is preferred.
+func lookupUnitType(ctx *blockCtx, v *ast.NumberUnitLit, also string) *unitType {
+	var found []*unitType
+	for _, ut := range ctx.unitTypes() {
+		if _, ok := ut.units[v.Unit]; ok {
+			found = append(found, ut)
+		}
+	}
+	if len(found) > 1 && also != "" {
+		var both []*unitType
+		for _, ut := range found {
+			if _, ok := ut.units[also]; ok {
+				both = append(both, ut)
+			}
+		}
+		if len(both) > 0 {
+			found = both
+		}
+	}
+	switch len(found) {
+	case 0:
+		panic(ctx.newCodeErrorf(v.Pos(), v.End(), "undefined unit %s", v.Unit))
+	case 1:
+		return found[0]
+	}
+	typs := make([]string, len(found))
+	for i, ut := range found {
+		typs[i] = ctx.unitTypeString(ut.obj.Type())
+	}
+	panic(ctx.newCodeErrorf(v.Pos(), v.End(), "ambiguous unit %s: %s", v.Unit, strings.Join(typs, " or ")))
+}
+
+func lookupUnitTypeByDim(ctx *blockCtx, dim dimension) *unitType {
+	for _, ut := range ctx.unitTypes() {
+		if ut.dim.equal(dim) {
+			return ut
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+
+// compileNumberUnitLit compiles a number with unit, eg. `1.5km`. If the unit
+// isn't a unit of the expected type, the type is looked up by the unit.
+func compileNumberUnitLit(ctx *blockCtx, v *ast.NumberUnitLit, expected types.Type) {
+	compileNumberUnitLitEx(ctx, v, expected, "")
+}
+
+func compileNumberUnitLitEx(ctx *blockCtx, v *ast.NumberUnitLit, expected types.Type, also string) {
+	ut := ctx.unitTypeOf(expected)
+	typed := ut != nil && ut.units[v.Unit] != nil
+	if !typed {
+		ut = lookupUnitType(ctx, v, also)
+	}
+	val := constant.MakeFromLiteral(v.Value, gotoken.Token(v.Kind), 0)
+	val = constant.BinaryOp(val, gotoken.MUL, ut.units[v.Unit])
+	pushUnitVal(ctx, ut.obj.Type(), val, v, typed)
+	if rec := ctx.recorder(); rec != nil {
+		rec.recordTypeValue(ctx, v, typesutil.Value)
+	}
+}
+
+// pushUnitVal pushes a constant val of type typ. If typed is false, the
+// constant is converted to typ explicitly, eg. `time.Duration(1000)`.
+func pushUnitVal(ctx *blockCtx, typ types.Type, val constant.Value, src ast.Expr, typed bool) {
+	lit := &goast.BasicLit{ValuePos: src.Pos(), Kind: gotoken.INT}
+	if v := constant.ToInt(val); v.Kind() == constant.Int {
+		val, lit.Value = v, v.ExactString()
+	} else if t, ok := typ.Underlying().(*types.Basic); ok && t.Info()&types.IsInteger != 0 {
+		panic(ctx.newCodeErrorf(src.Pos(), src.End(), "cannot use %s as %s value (truncated)", ctx.LoadExpr(src), ctx.unitTypeString(typ)))
+	} else {
+		f, _ := constant.Float64Val(val)
+		lit.Kind, lit.Value = gotoken.FLOAT, strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	cb := ctx.cb
+	if typed {
+		cb.Val(&gogen.Element{Val: lit, Type: typ, CVal: val}, src)
+		return
+	}
+	cb.Typ(typ).Val(lit).CallWith(1, 0, 0, src)
+}
+
+// compileUnitBinaryOp compiles a binary operation of two quantities. It
+// returns false if the operands aren't quantities or the operation is an
+// ordinary Go operation (eg. time.Duration(n) * time.Second).
+//
+// Quantities of different types can't be added, subtracted or compared. The
+// result of multiplying or dividing quantities of different types is of the
+// type whose dimension matches, eg. Distance / Time => Speed.
+func compileUnitBinaryOp(ctx *blockCtx, v *ast.BinaryExpr) bool {
+	cb := ctx.cb
+	stk := cb.InternalStack()
+	x, y := stk.Get(-2), stk.Get(-1)
+	ux, uy := ctx.unitTypeOf(x.Type), ctx.unitTypeOf(y.Type)
+	if ux == nil || uy == nil {
+		return false
+	}
+	sign := 1
+	switch v.Op {
+	case token.MUL:
+	case token.QUO:
+		sign = -1
+	case token.ADD, token.SUB, token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		if ux != uy {
+			panic(ctx.newCodeErrorf(v.Pos(), v.End(), "invalid operation: %s (mismatched units %s and %s)",
+				ctx.LoadExpr(v), ctx.unitTypeString(x.Type), ctx.unitTypeString(y.Type)))
+		}
+		return false
+	default:
+		return false
+	}
+	dim := ux.dim.combine(uy.dim, sign)
+	ret := lookupUnitTypeByDim(ctx, dim)
+	if ret == nil {
+		if ux == uy {
+			return false
+		}
+		if len(dim) != 0 {
+			panic(ctx.newCodeErrorf(v.Pos(), v.End(), "invalid operation: %s (no unit type for %s %v %s)",
+				ctx.LoadExpr(v), ctx.unitTypeString(x.Type), v.Op, ctx.unitTypeString(y.Type)))
+		}
+	}
+	op := gotoken.Token(v.Op)
+	stk.PopN(2)
+	if x.CVal != nil && y.CVal != nil {
+		if op == gotoken.QUO && constant.Sign(y.CVal) == 0 {
+			panic(ctx.newCodeError(v.Y.Pos(), v.Y.End(), "invalid operation: division by zero"))
+		}
+		val := constant.BinaryOp(constant.ToFloat(x.CVal), op, constant.ToFloat(y.CVal))
+		if ret == nil { // dimensionless
+			f, _ := constant.Float64Val(val)
+			cb.Val(f, v)
+		} else {
+			pushUnitVal(ctx, ret.obj.Type(), val, v, false)
+		}
+		return true
+	}
+	tyFloat64 := types.Typ[types.Float64]
+	if ret != nil {
+		cb.Typ(ret.obj.Type())
+	}
+	cb.Typ(tyFloat64).Val(x).Call(1).Typ(tyFloat64).Val(y).Call(1).BinaryOp(op, v)
+	if ret != nil {
+		cb.CallWith(1, 0, 0, v)
+	}
+	return true
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,46 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package base
+
+import (
+	"os"
+	"os/exec"
+
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgo/tool"
+	"github.com/qiniu/x/errors"
+)
+
+// PrintDiagnostics prints err to stdout as JSON diagnostics (see -json flag).
+// Errors of running the go command are skipped because the go command reports
+// them by itself.
+func PrintDiagnostics(fset *token.FileSet, err error) {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return
+	}
+	tool.WriteDiagnostics(os.Stdout, fset, err)
+}
+
+// WarningPrinter returns a function to print compiler warnings to stdout as
+// JSON diagnostics with severity "warning" (see -json flag and
+// tool.Config.Warning).
+func WarningPrinter(fset *token.FileSet) func(err error) {
+	return func(err error) {
+		tool.WriteWarnings(os.Stdout, fset, err)
+	}
+}
@@ -3,6 +3,8 @@ package base
 import (
 	"flag"
 	"fmt"
+	"runtime"
+	"strconv"
 	"strings"
 )
 
@@ -23,6 +25,7 @@ func (p *stringValue) Set(v string) error {
 type boolValue struct {
 	p    *PassArgs
 	name string
+	val  bool
 }
 
 func (p *boolValue) String() string {
@@ -30,6 +33,11 @@ func (p *boolValue) String() string {
 }
 
 func (p *boolValue) Set(v string) error {
+	val, err := strconv.ParseBool(v)
+	if err != nil {
+		return err
+	}
+	p.val = val
 	p.p.Args = append(p.p.Args, fmt.Sprintf("-%v=%v", p.name, v))
 	return nil
 }
@@ -52,6 +60,30 @@ func (p *PassArgs) Tags() string {
 	return ""
 }
 
+// JSON reports whether the -json flag is set. The flag must be registered by
+// p.Bool("json").
+func (p *PassArgs) JSON() bool {
+	if f := p.Flag.Lookup("json"); f != nil {
+		if v, ok := f.Value.(*boolValue); ok {
+			return v.val
+		}
+	}
+	return false
+}
+
+// Parallel returns the value of the -p flag, or runtime.GOMAXPROCS(0) if it
+// isn't specified.
+func (p *PassArgs) Parallel() int {
+	for _, v := range p.Args {
+		if strings.HasPrefix(v, "-p=") {
+			if n, err := strconv.Atoi(v[3:]); err == nil {
+				return n
+			}
+		}
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
 func (p *PassArgs) Var(names ...string) {
 	for _, name := range names {
 		p.Flag.Var(&stringValue{p: p, name: name}, name, "")
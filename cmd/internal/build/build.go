@@ -34,7 +34,7 @@ import (
 
 // gop build
 var Cmd = &base.Command{
-	UsageLine: "gop build [-debug -o output] [packages]",
+	UsageLine: "gop build [-debug -json -o output -p n] [packages]",
 	Short:     "Build XGo files",
 }
 
@@ -50,6 +50,7 @@ func init() {
 
 func runCmd(cmd *base.Command, args []string) {
 	pass := base.PassBuildFlags(cmd)
+	pass.Bool("json")
 	err := flag.Parse(args)
 	if err != nil {
 		log.Panicln("parse input arguments failed:", err)
@@ -79,6 +80,7 @@ func runCmd(cmd *base.Command, args []string) {
 		log.Panicln("tool.NewDefaultConf:", err)
 	}
 	defer conf.UpdateCache()
+	conf.Parallel = pass.Parallel()
 
 	confCmd := conf.NewGoCmdConf()
 	if *flagOutput != "" {
@@ -89,11 +91,15 @@ func runCmd(cmd *base.Command, args []string) {
 		confCmd.Flags = []string{"-o", output}
 	}
 	confCmd.Flags = append(confCmd.Flags, pass.Args...)
-	build(proj, conf, confCmd)
+	build(proj, conf, confCmd, pass.JSON())
 }
 
-func build(proj xgoprojs.Proj, conf *tool.Config, build *gocmd.BuildConfig) {
-	const flags = tool.GenFlagPrompt
+func build(proj xgoprojs.Proj, conf *tool.Config, build *gocmd.BuildConfig, json bool) {
+	var flags = tool.GenFlagPrompt
+	if json {
+		flags = 0
+		conf.Warning = base.WarningPrinter(conf.Fset)
+	}
 	var obj string
 	var err error
 	switch v := proj.(type) {
@@ -110,6 +116,8 @@ func build(proj xgoprojs.Proj, conf *tool.Config, build *gocmd.BuildConfig) {
 	}
 	if tool.NotFound(err) {
 		fmt.Fprintf(os.Stderr, "gop build %v: not found\n", obj)
+	} else if err != nil && json {
+		base.PrintDiagnostics(conf.Fset, err)
 	} else if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 	} else {
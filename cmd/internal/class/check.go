@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package class
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/goplus/xgo/cmd/internal/base"
+	"github.com/goplus/xgo/tool"
+)
+
+// xgo class check
+var CmdCheck = &base.Command{
+	UsageLine: "xgo class check [exts]",
+	Short:     "Check if class frameworks follow the classfile conventions",
+}
+
+var (
+	flag = &CmdCheck.Flag
+)
+
+func init() {
+	CmdCheck.Run = runCheck
+}
+
+func runCheck(cmd *base.Command, args []string) {
+	err := flag.Parse(args)
+	if err != nil {
+		log.Fatalln("parse input arguments failed:", err)
+	}
+
+	conf, err := tool.NewDefaultConf(".", tool.ConfFlagNoTestFiles)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer conf.UpdateCache()
+
+	projs, err := tool.CheckClasses(flag.Args(), conf)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	nerr := 0
+	for _, proj := range projs {
+		nerr += proj.Errors()
+	}
+	for _, proj := range projs {
+		printClass(proj)
+	}
+	if nerr > 0 {
+		conf.UpdateCache()
+		os.Exit(1)
+	}
+}
+
+func printClass(proj *tool.ClassFramework) {
+	fmt.Printf("project %s %s (%s)\n", proj.Ext, proj.Class, strings.Join(proj.PkgPaths, ", "))
+	if proj.Main != "" {
+		fmt.Printf("\tentry: %s\n", proj.Main)
+	}
+	for _, w := range proj.Works {
+		var attrs []string
+		if w.Proto != "" {
+			attrs = append(attrs, "proto "+w.Proto)
+		}
+		if w.Prefix != "" {
+			attrs = append(attrs, "prefix "+w.Prefix)
+		}
+		if w.Embedded {
+			attrs = append(attrs, "embedded")
+		}
+		attrs = append(attrs, w.Features...)
+		if len(attrs) > 0 {
+			fmt.Printf("\tclass %s %s (%s)\n", w.Ext, w.Class.Class, strings.Join(attrs, ", "))
+		} else {
+			fmt.Printf("\tclass %s %s\n", w.Ext, w.Class.Class)
+		}
+	}
+	for _, diag := range proj.Diags {
+		fmt.Fprintln(os.Stderr, diag)
+	}
+	if n := proj.Errors(); n > 0 {
+		fmt.Printf("FAIL\t%s: %d error(s)\n", proj.Ext, n)
+	} else {
+		fmt.Printf("ok\t%s\n", proj.Ext)
+	}
+}
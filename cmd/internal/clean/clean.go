@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/goplus/xgo/cmd/internal/base"
+	"github.com/goplus/xgo/tool"
 )
 
 const (
@@ -109,8 +110,9 @@ var Cmd = &base.Command{
 var (
 	flag = &Cmd.Flag
 
-	_        = flag.Bool("v", false, "print verbose information.")
-	testMode = flag.Bool("t", false, "test mode: display files to clean but don't clean them.")
+	_         = flag.Bool("v", false, "print verbose information.")
+	testMode  = flag.Bool("t", false, "test mode: display files to clean but don't clean them.")
+	cacheMode = flag.Bool("cache", false, "remove the entire code generation cache.")
 )
 
 func init() {
@@ -129,6 +131,14 @@ func runCmd(_ *base.Command, args []string) {
 		dir = flag.Arg(0)
 	}
 	cleanAGFiles(dir, !*testMode)
+	if *cacheMode {
+		if c := tool.DefaultGenCache(); c != nil {
+			fmt.Printf("Cleaning %s ...\n", c.Dir())
+			if !*testMode {
+				c.Clean()
+			}
+		}
+	}
 }
 
 // -----------------------------------------------------------------------------
@@ -29,6 +29,7 @@ import (
 	"github.com/goplus/mod/modcache"
 	"github.com/goplus/xgo/cmd/internal/base"
 	"github.com/goplus/xgo/env"
+	"github.com/goplus/xgo/tool"
 	"github.com/goplus/xgo/x/gocmd"
 )
 
@@ -76,6 +77,10 @@ func runCmd(_ *base.Command, args []string) {
 	xgoEnv["GOMODCACHE"] = modcache.GOMODCACHE
 	xgoEnv["GOXMOD"], _ = mod.GOXMOD("")
 	xgoEnv["HOME"] = env.HOME()
+	xgoEnv["XGOCACHE"] = "off"
+	if c := tool.DefaultGenCache(); c != nil {
+		xgoEnv["XGOCACHE"] = c.Dir()
+	}
 
 	vars := flag.Args()
 
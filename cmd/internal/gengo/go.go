@@ -22,6 +22,7 @@ import (
 	"log"
 	"os"
 	"reflect"
+	"runtime"
 
 	"github.com/goplus/gogen"
 	"github.com/goplus/xgo/cl"
@@ -33,7 +34,7 @@ import (
 
 // gop go
 var Cmd = &base.Command{
-	UsageLine: "gop go [-v] [packages|files]",
+	UsageLine: "gop go [-v -json -p n] [packages|files|work]",
 	Short:     "Convert XGo code into Go code",
 }
 
@@ -44,7 +45,9 @@ var (
 	flagSingleMode       = flag.Bool("s", false, "run in single file mode for package")
 	flagIgnoreNotatedErr = flag.Bool(
 		"ignore-notated-error", false, "ignore notated errors, only available together with -t (check mode)")
-	flagTags = flag.String("tags", "", "a comma-separated list of additional build tags to consider satisfied")
+	flagJSON     = flag.Bool("json", false, "print diagnostics in JSON format")
+	flagTags     = flag.String("tags", "", "a comma-separated list of additional build tags to consider satisfied")
+	flagParallel = flag.Int("p", runtime.GOMAXPROCS(0), "the number of packages that can be converted in parallel")
 )
 
 func init() {
@@ -61,24 +64,50 @@ func runCmd(cmd *base.Command, args []string) {
 		pattern = []string{"."}
 	}
 
-	projs, err := xgoprojs.ParseAll(pattern...)
-	if err != nil {
-		log.Panicln("xgoprojs.ParseAll:", err)
-	}
-
 	if *flagVerbose {
 		gogen.SetDebug(gogen.DbgFlagAll &^ gogen.DbgFlagComments)
 		cl.SetDebug(cl.DbgFlagAll)
 		cl.SetDisableRecover(true)
 	}
 
-	conf, err := tool.NewDefaultConf(".", 0, *flagTags)
+	if len(pattern) == 1 && pattern[0] == "work" {
+		work, err := tool.LoadWork(".")
+		if err != nil {
+			log.Panicln("tool.LoadWork:", err)
+		}
+		if work == nil {
+			fmt.Fprintln(os.Stderr, "gop go: pattern work: not in a workspace (no go.work or xgo.work found)")
+			os.Exit(1)
+		}
+		for _, m := range work.Mods {
+			genGo(m.Dir, []xgoprojs.Proj{&xgoprojs.DirProj{Dir: m.Dir + "/..."}})
+		}
+		return
+	}
+
+	projs, err := xgoprojs.ParseAll(pattern...)
+	if err != nil {
+		log.Panicln("xgoprojs.ParseAll:", err)
+	}
+	genGo(".", projs)
+}
+
+func genGo(modDir string, projs []xgoprojs.Proj) {
+	conf, err := tool.NewDefaultConf(modDir, 0, *flagTags)
 	if err != nil {
 		log.Panicln("tool.NewDefaultConf:", err)
 	}
 	defer conf.UpdateCache()
+	conf.Parallel = *flagParallel
+	if *flagVerbose { // keep debug logs of packages from interleaving
+		conf.Parallel = 1
+	}
 
 	flags := tool.GenFlagPrintError | tool.GenFlagPrompt
+	if *flagJSON {
+		flags = 0
+		conf.Warning = base.WarningPrinter(conf.Fset)
+	}
 	if *flagCheckMode {
 		flags |= tool.GenFlagCheckOnly
 		if *flagIgnoreNotatedErr {
@@ -100,6 +129,10 @@ func runCmd(cmd *base.Command, args []string) {
 			log.Panicln("`gop go` doesn't support", reflect.TypeOf(v))
 		}
 		if err != nil {
+			if *flagJSON {
+				base.PrintDiagnostics(conf.Fset, err)
+				os.Exit(1)
+			}
 			fmt.Fprintf(os.Stderr, "GenGo failed: %d errors.\n", errorNum(err))
 			os.Exit(1)
 		}
@@ -55,35 +55,45 @@ func runCmd(cmd *base.Command, args []string) {
 		log.Fatalln("TODO: not impl")
 	}
 	for i := 0; i < narg; i++ {
-		get(flag.Arg(i))
+		check(Get(".", flag.Arg(i)))
 	}
 }
 
-func get(pkgPath string) {
+// Get adds pkgPath (in the form of path[@version]) as a dependency of the
+// module in dir, and downloads it. If dir isn't in a module, it only downloads
+// the package.
+func Get(dir, pkgPath string) (err error) {
 	modBase := ""
-	mod, err := modload.Load(".")
+	mod, err := modload.Load(dir)
 	noMod := tool.NotFound(err)
 	if !noMod {
-		check(err)
+		if err != nil {
+			return
+		}
 		modBase = mod.Path()
 	}
 
 	pkgModVer, _, err := modfetch.GetPkg(pkgPath, modBase)
-	check(err)
-	if noMod {
+	if err != nil || noMod {
 		return
 	}
 
 	pkgModRoot, err := modcache.Path(pkgModVer)
-	check(err)
+	if err != nil {
+		return
+	}
 
 	pkgMod, err := modload.Load(pkgModRoot)
-	check(err)
+	if err != nil {
+		return
+	}
 
-	check(mod.AddRequire(pkgModVer.Path, pkgModVer.Version, pkgMod.HasProject()))
+	if err = mod.AddRequire(pkgModVer.Path, pkgModVer.Version, pkgMod.HasProject()); err != nil {
+		return
+	}
 	fmt.Fprintf(os.Stderr, "gop get: added %s %s\n", pkgModVer.Path, pkgModVer.Version)
 
-	check(mod.Save())
+	return mod.Save()
 }
 
 func check(err error) {
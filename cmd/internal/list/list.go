@@ -14,28 +14,33 @@
  * limitations under the License.
  */
 
+// Package list implements the “gop list” command.
 package list
 
-/*
 import (
-	"fmt"
+	"bufio"
+	"encoding/json"
 	"log"
+	"os"
+	"text/template"
 
 	"github.com/goplus/xgo/cmd/internal/base"
-	"github.com/goplus/xgo/x/gopmod"
+	"github.com/goplus/xgo/tool"
 )
 
 // -----------------------------------------------------------------------------
 
 // gop list
 var Cmd = &base.Command{
-	UsageLine: "gop list [-json] [packages]",
-	Short:     "List packages or modules",
+	UsageLine: "gop list [-json] [-deps] [-f format] [packages]",
+	Short:     "List packages",
 }
 
 var (
-	flag = &Cmd.Flag
-	_    = flag.Bool("json", false, "printing in JSON format.")
+	flag       = &Cmd.Flag
+	flagJSON   = flag.Bool("json", false, "print package metadata in JSON format")
+	flagDeps   = flag.Bool("deps", false, "also list all dependencies of the named packages")
+	flagFormat = flag.String("f", "", "print package metadata using the given template")
 )
 
 func init() {
@@ -47,16 +52,41 @@ func runCmd(cmd *base.Command, args []string) {
 	if err != nil {
 		log.Fatalln("parse input arguments failed:", err)
 	}
+	if *flagJSON && *flagFormat != "" {
+		log.Fatalln("list: cannot use -f with -json")
+	}
 
 	pattern := flag.Args()
 	if len(pattern) == 0 {
 		pattern = []string{"."}
 	}
 
-	pkgPaths, err := gopmod.List(pattern...)
+	conf, err := tool.NewDefaultConf(".", 0)
 	check(err)
-	for _, pkgPath := range pkgPaths {
-		fmt.Println(pkgPath)
+	defer conf.UpdateCache()
+
+	pkgs, err := tool.ListPackages(pattern, conf, *flagDeps)
+	check(err)
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	switch {
+	case *flagJSON:
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "\t")
+		for _, pkg := range pkgs {
+			check(enc.Encode(pkg))
+		}
+	default:
+		format := *flagFormat
+		if format == "" {
+			format = "{{.ImportPath}}"
+		}
+		tmpl, err := template.New("main").Parse(format + "\n")
+		check(err)
+		for _, pkg := range pkgs {
+			check(tmpl.Execute(out, pkg))
+		}
 	}
 }
 
@@ -65,6 +95,5 @@ func check(err error) {
 		log.Fatalln(err)
 	}
 }
-*/
 
 // -----------------------------------------------------------------------------
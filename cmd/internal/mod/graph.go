@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mod
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/goplus/xgo/cmd/internal/base"
+	"github.com/goplus/xgo/tool"
+)
+
+// xgo mod graph
+var CmdGraph = &base.Command{
+	UsageLine: "xgo mod graph [-json]",
+	Short:     "print module requirement graph (including class frameworks)",
+}
+
+var (
+	flagGraph     = &CmdGraph.Flag
+	flagGraphJSON = flagGraph.Bool("json", false, "print the graph in JSON format")
+)
+
+func init() {
+	CmdGraph.Run = runGraph
+}
+
+func runGraph(cmd *base.Command, args []string) {
+	err := flagGraph.Parse(args)
+	if err != nil {
+		log.Fatalln("parse input arguments failed:", err)
+	}
+	if flagGraph.NArg() > 0 {
+		fatal("xgo mod graph: too many arguments")
+	}
+	edges, err := tool.ModGraph(".")
+	if err != nil {
+		if tool.NotFound(err) {
+			fatal("go.mod not found")
+		}
+		fatal(err)
+	}
+	if *flagGraphJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "\t")
+		check(enc.Encode(edges))
+		return
+	}
+	for _, e := range edges {
+		if len(e.Classes) > 0 {
+			fmt.Printf("%s %s //xgo:class %s\n", e.From, e.To, strings.Join(e.Classes, " "))
+		} else {
+			fmt.Println(e.From, e.To)
+		}
+	}
+}
@@ -62,19 +62,32 @@ Run 'gop help mod init' for more information.`)
 		fatal("gop mod init: too many arguments")
 	}
 
-	modPath := args[0]
-	mod, err := modload.Create(".", modPath, goMainVer(), env.MainVersion())
+	compiler := ""
+	if *flagLLGo {
+		compiler = "llgo"
+	} else if *flagTinyGo {
+		compiler = "tinygo"
+	}
+	_, err = Init(".", args[0], compiler)
 	check(err)
+}
 
-	if *flagLLGo {
+// Init initializes a new module with modPath in dir, as `xgo mod init` does.
+// compiler can be "llgo", "tinygo" or "" (the go compiler).
+func Init(dir, modPath, compiler string) (mod modload.Module, err error) {
+	mod, err = modload.Create(dir, modPath, goMainVer(), env.MainVersion())
+	if err != nil {
+		return
+	}
+	switch compiler {
+	case "llgo":
 		mod.AddCompiler("llgo", "1.0")
 		mod.AddRequire("github.com/goplus/lib", llgoLibVer(), false)
-	} else if *flagTinyGo {
+	case "tinygo":
 		mod.AddCompiler("tinygo", "0.32")
 	}
-
 	err = mod.Save()
-	check(err)
+	return
 }
 
 func goMainVer() string {
@@ -31,6 +31,9 @@ var Cmd = &base.Command{
 		CmdInit,
 		CmdDownload,
 		CmdTidy,
+		CmdGraph,
+		CmdWhy,
+		CmdVendor,
 	},
 }
 
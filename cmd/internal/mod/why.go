@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mod
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/goplus/xgo/cmd/internal/base"
+	"github.com/goplus/xgo/tool"
+)
+
+// xgo mod why
+var CmdWhy = &base.Command{
+	UsageLine: "xgo mod why [-m] packages",
+	Short:     "explain why packages or modules are needed",
+}
+
+var (
+	flagWhy       = &CmdWhy.Flag
+	flagWhyModule = flagWhy.Bool("m", false, "treat arguments as a list of modules")
+)
+
+func init() {
+	CmdWhy.Run = runWhy
+}
+
+func runWhy(cmd *base.Command, args []string) {
+	err := flagWhy.Parse(args)
+	if err != nil {
+		log.Fatalln("parse input arguments failed:", err)
+	}
+	targets := flagWhy.Args()
+	if len(targets) == 0 {
+		fatal("usage: " + cmd.UsageLine)
+	}
+	chains, err := tool.ModWhy(".", targets, *flagWhyModule)
+	if err != nil {
+		if tool.NotFound(err) {
+			fatal("go.mod not found")
+		}
+		fatal(err)
+	}
+	kind := "package"
+	if *flagWhyModule {
+		kind = "module"
+	}
+	for i, chain := range chains {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Println("#", targets[i])
+		if chain == nil {
+			fmt.Printf("(main module does not need %s %s)\n", kind, targets[i])
+			continue
+		}
+		for _, step := range chain {
+			if step.Reason != "" {
+				fmt.Printf("%s (%s)\n", step.Pkg, step.Reason)
+			} else {
+				fmt.Println(step.Pkg)
+			}
+		}
+	}
+}
@@ -0,0 +1,333 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package newproj implements the “xgo new” command.
+package newproj
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/goplus/mod/modload"
+	"github.com/goplus/mod/xgomod"
+	"github.com/goplus/xgo/cmd/internal/base"
+	"github.com/goplus/xgo/cmd/internal/gopget"
+	"github.com/goplus/xgo/cmd/internal/mod"
+	"github.com/goplus/xgo/env"
+)
+
+// xgo new
+var Cmd = &base.Command{
+	UsageLine: "xgo new [-l -module path -var key=value] template dir",
+	Short:     "Create a new project from a template",
+}
+
+var (
+	flag     = &Cmd.Flag
+	flagList = flag.Bool("l", false, "list built-in templates")
+	flagMod  = flag.String("module", "", "module path of the new project (default: base name of dir)")
+	flagVars = make(map[string]string)
+)
+
+func init() {
+	Cmd.Run = runCmd
+	flag.Func("var", "set template variable `key=value` (can be repeated)", func(s string) error {
+		k, v, ok := strings.Cut(s, "=")
+		if !ok || k == "" {
+			return fmt.Errorf("invalid variable %q: want key=value", s)
+		}
+		flagVars[k] = v
+		return nil
+	})
+}
+
+// -----------------------------------------------------------------------------
+
+//go:embed _templates
+var builtinFS embed.FS
+
+type projTemplate struct {
+	name     string
+	short    string
+	requires []string // modules required by the project, in the form of path[@version]
+	next     string   // command to try the new project
+	fsys     fs.FS
+	local    bool
+}
+
+var builtins = []*projTemplate{
+	{name: "app", short: "XGo application (main.xgo)"},
+	{name: "cmd", short: "command line application (cobra classfiles: *_app.gox, *_cmd.gox)", requires: []string{"github.com/goplus/cobra"}},
+	{name: "test", short: "package with unit test classfiles (*_test.gox)", next: "xgo test"},
+	{name: "gsh", short: "shell script (gsh classfile: *.gsh)"},
+	{name: "yap", short: "web application (yap classfiles: *.yap)", requires: []string{"github.com/goplus/yap"}},
+	{name: "spx", short: "2D game (spx classfiles: *.spx)", requires: []string{"github.com/goplus/spx"}},
+}
+
+func runCmd(cmd *base.Command, args []string) {
+	err := flag.Parse(args)
+	if err != nil {
+		log.Fatalln("parse input arguments failed:", err)
+	}
+	if *flagList {
+		for _, t := range builtins {
+			fmt.Printf("%-6s %s\n", t.name, t.short)
+		}
+		return
+	}
+	if flag.NArg() != 2 {
+		cmd.Usage(os.Stderr)
+		os.Exit(2)
+	}
+
+	tmpl, err := lookupTemplate(flag.Arg(0))
+	check(err)
+	dir := flag.Arg(1)
+	existed, err := checkEmptyDir(dir)
+	check(err)
+
+	absDir, err := filepath.Abs(dir)
+	check(err)
+	name := filepath.Base(absDir)
+	modPath := *flagMod
+	if modPath == "" {
+		modPath = name
+	}
+	vars := map[string]string{
+		"Name":    name,
+		"PkgName": pkgName(name),
+		"ModPath": modPath,
+	}
+	for k, v := range flagVars {
+		vars[k] = v
+	}
+
+	if err = tmpl.copyTo(dir, vars); err != nil {
+		if !existed {
+			os.RemoveAll(dir)
+		}
+		check(err)
+	}
+	if _, e := os.Lstat(filepath.Join(dir, "go.mod")); e != nil { // go.mod isn't provided by the template
+		check(initMod(dir, modPath))
+	}
+	for _, req := range tmpl.requires {
+		check(gopget.Get(dir, req))
+	}
+	next := tmpl.next
+	if next == "" {
+		next = "xgo run ."
+	}
+	fmt.Fprintf(os.Stderr, "xgo new: created %s from template %s\n\tcd %s && xgo mod tidy && %s\n", dir, tmpl.name, dir, next)
+}
+
+// lookupTemplate returns a built-in template by name, or a local template if
+// name is a directory.
+func lookupTemplate(name string) (*projTemplate, error) {
+	if fi, err := os.Stat(name); err == nil && fi.IsDir() {
+		return localTemplate(name)
+	}
+	for _, t := range builtins {
+		if t.name == name {
+			ret := *t
+			ret.fsys, _ = fs.Sub(builtinFS, "_templates/"+name)
+			ret.requires = make([]string, len(t.requires))
+			for i, req := range t.requires {
+				ret.requires[i] = toolchainVer(req)
+			}
+			return &ret, nil
+		}
+	}
+	return nil, fmt.Errorf("xgo new: unknown template %q (run 'xgo new -l' to list built-in templates)", name)
+}
+
+// localTemplate loads a template from dir. Modules required by go.mod of the
+// template are required by the new project too.
+func localTemplate(dir string) (*projTemplate, error) {
+	ret := &projTemplate{name: dir, fsys: os.DirFS(dir), local: true}
+	tmod, err := modload.Load(dir)
+	if err != nil {
+		if xgomod.IsNotFound(err) {
+			return ret, nil
+		}
+		return nil, err
+	}
+	if tmod.Root() != mustAbs(dir) { // go.mod doesn't belong to the template
+		return ret, nil
+	}
+	for _, r := range tmod.Require {
+		if !r.Indirect {
+			ret.requires = append(ret.requires, r.Mod.Path+"@"+r.Mod.Version)
+		}
+	}
+	return ret, nil
+}
+
+// initMod creates go.mod of the new project. gox.mod copied from the template
+// (which registers classfiles of the project) is kept.
+func initMod(dir, modPath string) (err error) {
+	goxMod := filepath.Join(dir, "gox.mod")
+	data, err := os.ReadFile(goxMod)
+	hasGoxMod := err == nil
+	if hasGoxMod {
+		if err = os.Remove(goxMod); err != nil { // modload.Create fails if gox.mod exists
+			return
+		}
+	} else if !os.IsNotExist(err) {
+		return
+	}
+	if _, err = mod.Init(dir, modPath, ""); err != nil {
+		return
+	}
+	if hasGoxMod {
+		err = os.WriteFile(goxMod, data, 0644)
+	}
+	return
+}
+
+// toolchainVer returns modPath@version if modPath is required by the XGo
+// toolchain (eg. class frameworks registered by XGo itself), otherwise it
+// returns modPath to require the latest version.
+func toolchainVer(modPath string) string {
+	if modXGo, e := xgomod.LoadFrom(filepath.Join(env.XGOROOT(), "go.mod"), ""); e == nil {
+		if ver, ok := modXGo.LookupDepMod(modPath); ok && ver.Version != "" {
+			return modPath + "@" + ver.Version
+		}
+	}
+	return modPath
+}
+
+// copyTo copies files of the template to dir. Names containing "{{" and files
+// with the .tmpl suffix (which is removed) are expanded as text/template with
+// vars.
+func (p *projTemplate) copyTo(dir string, vars map[string]string) error {
+	return fs.WalkDir(p.fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || name == "." {
+			return err
+		}
+		if p.local && skipLocal(name, d) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		target := name
+		if strings.Contains(target, "{{") {
+			if target, err = expand(name, target, vars); err != nil {
+				return err
+			}
+		}
+		target = filepath.Join(dir, filepath.FromSlash(target))
+		if d.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		data, err := fs.ReadFile(p.fsys, name)
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(target, ".tmpl") {
+			target = strings.TrimSuffix(target, ".tmpl")
+			text, err := expand(name, string(data), vars)
+			if err != nil {
+				return err
+			}
+			data = []byte(text)
+		}
+		if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0644)
+	})
+}
+
+func expand(name, text string, vars map[string]string) (string, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err = t.Execute(&b, vars); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// skipLocal reports whether name of a local template shouldn't be copied.
+func skipLocal(name string, d fs.DirEntry) bool {
+	base := path.Base(name)
+	if d.IsDir() {
+		return strings.HasPrefix(base, ".")
+	}
+	switch base {
+	case "go.mod", "go.sum":
+		return name == base // module files of the template itself
+	}
+	return strings.HasPrefix(base, "xgo_autogen") || strings.HasPrefix(base, "gop_autogen")
+}
+
+// checkEmptyDir checks if dir doesn't exist or is empty.
+func checkEmptyDir(dir string) (existed bool, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return
+	}
+	if len(entries) > 0 {
+		err = fmt.Errorf("xgo new: directory %s already exists and is not empty", dir)
+	}
+	return true, err
+}
+
+// pkgName returns a valid package name derived from name.
+func pkgName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "p" + name
+	}
+	return name
+}
+
+func mustAbs(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
+
+func check(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,201 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package newproj
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestPkgName(t *testing.T) {
+	cases := []struct {
+		name, pkg string
+	}{
+		{"hello", "hello"},
+		{"Hello-World", "helloworld"},
+		{"my_app.v2", "my_appv2"},
+		{"2048", "p2048"},
+		{"---", "p"},
+		{"你好", "你好"},
+	}
+	for _, c := range cases {
+		if ret := pkgName(c.name); ret != c.pkg {
+			t.Errorf("pkgName(%q) = %q, want %q", c.name, ret, c.pkg)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"Name": "foo", "PkgName": "foo"}
+	ret, err := expand("main.xgo.tmpl", "package {{.PkgName}} // {{.Name}}", vars)
+	if err != nil || ret != "package foo // foo" {
+		t.Fatal("expand:", ret, err)
+	}
+	if _, err = expand("bad", "{{.Unknown}}", vars); err == nil {
+		t.Fatal("expand: no error for missing key")
+	}
+	if _, err = expand("bad", "{{.Name", vars); err == nil {
+		t.Fatal("expand: no error for syntax error")
+	}
+}
+
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	ret := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		ret[filepath.ToSlash(rel)] = string(data)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+func checkTree(t *testing.T, dir string, want map[string]string) {
+	t.Helper()
+	got := readTree(t, dir)
+	for name, data := range want {
+		if got[name] != data {
+			t.Errorf("%s: got %q, want %q", name, got[name], data)
+		}
+	}
+	for name := range got {
+		if _, ok := want[name]; !ok {
+			t.Errorf("%s: unexpected file", name)
+		}
+	}
+}
+
+var testFS = fstest.MapFS{
+	"go.mod":                   {Data: []byte("module tmpl\n")},
+	"go.sum":                   {Data: []byte("sum\n")},
+	"gox.mod":                  {Data: []byte("xgo 1.5\n\nproject _mcp.gox Server github.com/goplus/xgo/mcp\n")},
+	"main_mcp.gox.tmpl":        {Data: []byte("// {{.Name}}\n")},
+	"{{.PkgName}}/doc.xgo":     {Data: []byte("package {{.PkgName}}\n")},
+	"sub/go.mod":               {Data: []byte("module sub\n")},
+	"xgo_autogen.go":           {Data: []byte("package main\n")},
+	".git/config":              {Data: []byte("[core]\n")},
+	"assets/.keep":             {Data: []byte("")},
+	"assets/gop_autogen_x.txt": {Data: []byte("x\n")},
+}
+
+func TestCopyTo(t *testing.T) {
+	vars := map[string]string{"Name": "foo-bar", "PkgName": "foobar"}
+	dir := t.TempDir()
+	tmpl := &projTemplate{name: "test", fsys: testFS}
+	if err := tmpl.copyTo(dir, vars); err != nil {
+		t.Fatal("copyTo:", err)
+	}
+	want := make(map[string]string)
+	for name, f := range testFS {
+		want[name] = string(f.Data)
+	}
+	delete(want, "main_mcp.gox.tmpl")
+	delete(want, "{{.PkgName}}/doc.xgo")
+	want["main_mcp.gox"] = "// foo-bar\n"
+	want["foobar/doc.xgo"] = "package {{.PkgName}}\n" // only names and .tmpl files are expanded
+	checkTree(t, dir, want)
+}
+
+func TestCopyToLocal(t *testing.T) {
+	vars := map[string]string{"Name": "foo", "PkgName": "foo"}
+	dir := t.TempDir()
+	tmpl := &projTemplate{name: "test", fsys: testFS, local: true}
+	if err := tmpl.copyTo(dir, vars); err != nil {
+		t.Fatal("copyTo:", err)
+	}
+	checkTree(t, dir, map[string]string{
+		"gox.mod":      string(testFS["gox.mod"].Data),
+		"main_mcp.gox": "// foo\n",
+		"foo/doc.xgo":  "package {{.PkgName}}\n",
+		"sub/go.mod":   "module sub\n",
+		"assets/.keep": "",
+	})
+}
+
+func TestCopyToError(t *testing.T) {
+	tmpl := &projTemplate{name: "test", fsys: fstest.MapFS{
+		"main.xgo.tmpl": {Data: []byte("{{.Unknown}}")},
+	}}
+	if err := tmpl.copyTo(t.TempDir(), map[string]string{}); err == nil {
+		t.Fatal("copyTo: no error")
+	}
+}
+
+func TestLocalTemplate(t *testing.T) {
+	src := t.TempDir()
+	files := map[string]string{
+		"go.mod":       "module tmpl\n\ngo 1.21\n\nrequire (\n\tgithub.com/goplus/xgo v1.5.0\n\tgithub.com/qiniu/x v1.15.0 // indirect\n)\n",
+		"gox.mod":      "xgo 1.5\n\nproject _mcp.gox Server github.com/goplus/xgo/mcp\n",
+		"main_mcp.gox": "tool \"hello\", => {\n}\n",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(src, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tmpl, err := lookupTemplate(src)
+	if err != nil {
+		t.Fatal("lookupTemplate:", err)
+	}
+	if !tmpl.local || len(tmpl.requires) != 1 || tmpl.requires[0] != "github.com/goplus/xgo@v1.5.0" {
+		t.Fatalf("lookupTemplate: %+v", tmpl)
+	}
+
+	dir := filepath.Join(t.TempDir(), "proj")
+	if err = tmpl.copyTo(dir, map[string]string{"Name": "proj"}); err != nil {
+		t.Fatal("copyTo:", err)
+	}
+	if err = initMod(dir, "example.com/proj"); err != nil {
+		t.Fatal("initMod:", err)
+	}
+	got := readTree(t, dir)
+	if got["gox.mod"] != files["gox.mod"] || got["main_mcp.gox"] != files["main_mcp.gox"] {
+		t.Fatalf("gox.mod or main_mcp.gox isn't copied: %q", got)
+	}
+	if gomod := got["go.mod"]; gomod == "" || gomod == files["go.mod"] {
+		t.Fatalf("go.mod: %q", gomod)
+	}
+	if len(got) != 3 {
+		t.Fatalf("unexpected files: %q", got)
+	}
+}
+
+func TestLookupTemplate(t *testing.T) {
+	tmpl, err := lookupTemplate("app")
+	if err != nil || tmpl.local || tmpl.name != "app" {
+		t.Fatal("lookupTemplate(app):", tmpl, err)
+	}
+	if _, err = lookupTemplate("unknown"); err == nil {
+		t.Fatal("lookupTemplate(unknown): no error")
+	}
+	dir := t.TempDir()
+	if tmpl, err = lookupTemplate(dir); err != nil || !tmpl.local || len(tmpl.requires) != 0 {
+		t.Fatal("lookupTemplate(dir):", tmpl, err)
+	}
+}
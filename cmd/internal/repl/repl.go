@@ -0,0 +1,196 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package repl implements the “gop repl” command.
+package repl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/goplus/xgo/cmd/internal/base"
+	"github.com/goplus/xgo/scanner"
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgo/tool"
+	"github.com/goplus/xgo/x/repl"
+	"github.com/qiniu/x/errors"
+)
+
+// gop repl
+var Cmd = &base.Command{
+	UsageLine: "gop repl [-q]",
+	Short:     "Start an interactive XGo session",
+}
+
+var (
+	flag      = &Cmd.Flag
+	flagQuiet = flag.Bool("q", false, "don't print the welcome message")
+)
+
+func init() {
+	Cmd.Run = runCmd
+}
+
+const help = `Enter XGo statements, expressions or declarations. Commands:
+  :type <expr>      print the type of an expression
+  :doc <name>       print documentation of name (or pkg.Name)
+  :load <file.xgo>  load an XGo file into the session
+  :source           print source code of the session
+  :help             print this help
+  :quit             exit the session
+`
+
+func runCmd(cmd *base.Command, args []string) {
+	pass := base.PassBuildFlags(cmd)
+	err := flag.Parse(args)
+	if err != nil {
+		log.Fatalln("parse input arguments failed:", err)
+	}
+
+	conf, err := tool.NewDefaultConf(".", tool.ConfFlagNoTestFiles, pass.Tags())
+	if err != nil {
+		log.Fatalln("tool.NewDefaultConf:", err)
+	}
+	defer conf.UpdateCache()
+
+	if !conf.Mod.HasModfile() { // if no go.mod, check XGoDeps
+		conf.XGoDeps = new(int)
+	}
+	confCmd := conf.NewGoCmdConf()
+	confCmd.Flags = pass.Args
+
+	sess, err := repl.New(conf, confCmd)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer sess.Close()
+
+	if !*flagQuiet {
+		fmt.Printf("XGo %s (type :help for help)\n", conf.XGo.Version)
+	}
+	in := bufio.NewReader(os.Stdin)
+	for {
+		src, err := readInput(in)
+		if err != nil {
+			if err != io.EOF {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			return
+		}
+		if quit := eval(sess, strings.TrimSpace(src)); quit {
+			return
+		}
+	}
+}
+
+func eval(sess *repl.REPL, src string) (quit bool) {
+	if src == "" {
+		return
+	}
+	if !strings.HasPrefix(src, ":") {
+		if err := sess.Eval(src); err != nil {
+			printErr(err)
+		}
+		return
+	}
+	cmd, arg, _ := strings.Cut(src[1:], " ")
+	arg = strings.TrimSpace(arg)
+	switch cmd {
+	case "type", "t":
+		typ, err := sess.TypeOf(arg)
+		if err != nil {
+			printErr(err)
+			return
+		}
+		fmt.Println(typ)
+	case "doc", "d":
+		doc, err := sess.Doc(arg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", arg, err)
+			return
+		}
+		fmt.Print(doc)
+		if !strings.HasSuffix(doc, "\n") {
+			fmt.Println()
+		}
+	case "load", "l":
+		if err := sess.Load(arg); err != nil {
+			printErr(err)
+		}
+	case "source":
+		fmt.Print(sess.Source())
+	case "help", "h", "?":
+		fmt.Print(help)
+	case "quit", "q", "exit":
+		return true
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command :%s (type :help for help)\n", cmd)
+	}
+	return
+}
+
+func printErr(err error) {
+	if _, ok := err.(interface{ ExitCode() int }); ok {
+		return // the go command has reported the error
+	}
+	fmt.Fprintln(os.Stderr, errors.Summary(err))
+}
+
+// readInput reads an input from in. It continues reading lines while there
+// are unclosed brackets.
+func readInput(in *bufio.Reader) (string, error) {
+	var b strings.Builder
+	prompt := "> "
+	for {
+		fmt.Print(prompt)
+		line, err := in.ReadString('\n')
+		b.WriteString(line)
+		if err != nil {
+			if err == io.EOF && b.Len() > 0 {
+				fmt.Println()
+				return b.String(), nil
+			}
+			return "", err
+		}
+		if depth(b.String()) <= 0 {
+			return b.String(), nil
+		}
+		prompt = "... "
+	}
+}
+
+// depth returns the number of unclosed brackets in src.
+func depth(src string) (n int) {
+	var s scanner.Scanner
+	fset := token.NewFileSet()
+	file := fset.AddFile("", -1, len(src))
+	s.Init(file, []byte(src), nil, 0)
+	for {
+		_, tok, _ := s.Scan()
+		switch tok {
+		case token.EOF:
+			return
+		case token.LBRACE, token.LPAREN, token.LBRACK:
+			n++
+		case token.RBRACE, token.RPAREN, token.RBRACK:
+			n--
+		}
+	}
+}
@@ -19,6 +19,7 @@ package serve
 
 import (
 	"context"
+	"time"
 
 	"github.com/goplus/xgo/cmd/internal/base"
 	"github.com/goplus/xgo/x/jsonrpc2"
@@ -36,6 +37,9 @@ var Cmd = &base.Command{
 var (
 	flag        = &Cmd.Flag
 	flagVerbose = flag.Bool("v", false, "print verbose information")
+	flagDaemon  = flag.Bool("daemon", false, "serve as a daemon shared by clients on a Unix socket under ~/.xgo/")
+	flagIdle    = flag.Duration("idle", 10*time.Minute, "shut the daemon down after being idle for this duration")
+	flagCache   = flag.Bool("cache", false, "use the importer cache file and the code generation cache")
 )
 
 func init() {
@@ -52,10 +56,18 @@ func runCmd(cmd *base.Command, args []string) {
 		jsonrpc2.SetDebug(jsonrpc2.DbgFlagCall)
 	}
 
+	if *flagDaemon {
+		conf := &langserver.DaemonConfig{IdleTimeout: *flagIdle, UseCache: *flagCache}
+		if err = langserver.ServeDaemon(context.Background(), conf); err != nil {
+			log.Fatalln("serve daemon failed:", err)
+		}
+		return
+	}
+
 	listener := stdio.Listener(false)
 	defer listener.Close()
 
-	server := langserver.NewServer(context.Background(), listener, nil)
+	server := langserver.NewServer(context.Background(), listener, &langserver.Config{UseCache: *flagCache})
 	server.Wait()
 }
 
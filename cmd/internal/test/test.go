@@ -33,7 +33,7 @@ import (
 
 // gop test
 var Cmd = &base.Command{
-	UsageLine: "gop test [-debug] [packages]",
+	UsageLine: "gop test [-debug -json] [packages]",
 	Short:     "Test XGo packages",
 }
 
@@ -78,12 +78,16 @@ func runCmd(cmd *base.Command, args []string) {
 	confCmd := conf.NewGoCmdConf()
 	confCmd.Flags = pass.Args
 	for _, proj := range projs {
-		test(proj, conf, confCmd)
+		test(proj, conf, confCmd, pass.JSON())
 	}
 }
 
-func test(proj xgoprojs.Proj, conf *tool.Config, test *gocmd.TestConfig) {
-	const flags = tool.GenFlagPrompt
+func test(proj xgoprojs.Proj, conf *tool.Config, test *gocmd.TestConfig, json bool) {
+	var flags = tool.GenFlagPrompt
+	if json {
+		flags = 0
+		conf.Warning = base.WarningPrinter(conf.Fset)
+	}
 	var obj string
 	var err error
 	switch v := proj.(type) {
@@ -100,6 +104,8 @@ func test(proj xgoprojs.Proj, conf *tool.Config, test *gocmd.TestConfig) {
 	}
 	if tool.NotFound(err) {
 		fmt.Fprintf(os.Stderr, "gop test %v: not found\n", obj)
+	} else if err != nil && json {
+		base.PrintDiagnostics(conf.Fset, err)
 	} else if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 	} else {
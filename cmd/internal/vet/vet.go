@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package vet implements the “xgo vet” command.
+package vet
+
+import (
+	"errors"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/goplus/xgo/cmd/internal/base"
+	"github.com/goplus/xgo/x/analysis/checker"
+	"github.com/goplus/xgo/x/analysis/passes"
+)
+
+// xgo vet
+var Cmd = &base.Command{
+	UsageLine: "xgo vet [-json -tags tags -vettool prog] [-analyzer flags] [packages]",
+	Short:     "Report likely mistakes in XGo packages",
+}
+
+var (
+	flag        = &Cmd.Flag
+	flagJSON    = flag.Bool("json", false, "emit diagnostics in JSON format")
+	flagTags    = flag.String("tags", "", "a comma-separated list of build tags")
+	_           = flag.String("vettool", "", "run `prog` (see checker.Main) instead of the built-in analyzers")
+	flagEnabled = checker.RegisterFlags(flag, passes.All)
+)
+
+func init() {
+	Cmd.Run = runCmd
+}
+
+func runCmd(cmd *base.Command, args []string) {
+	if prog, rest, ok := cutVetTool(args); ok {
+		runVetTool(prog, rest)
+		return
+	}
+	err := flag.Parse(args)
+	if err != nil {
+		log.Fatalln("parse input arguments failed:", err)
+	}
+	os.Exit(checker.Vet(flag.Args(), *flagTags, *flagJSON, flagEnabled()))
+}
+
+// cutVetTool finds the -vettool flag in args, and returns its value and
+// the other arguments.
+func cutVetTool(args []string) (prog string, rest []string, ok bool) {
+	for i, arg := range args {
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			break
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if v, found := strings.CutPrefix(name, "vettool="); found {
+			rest = append(append(rest, args[:i]...), args[i+1:]...)
+			return v, rest, true
+		}
+		if name == "vettool" && i+1 < len(args) {
+			rest = append(append(rest, args[:i]...), args[i+2:]...)
+			return args[i+1], rest, true
+		}
+	}
+	return "", args, false
+}
+
+func runVetTool(prog string, args []string) {
+	cmd := exec.Command(prog, args...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		log.Fatalln("vet:", err)
+	}
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package work
+
+import (
+	"errors"
+	"log"
+	"runtime"
+	"strings"
+
+	"github.com/goplus/xgo/cmd/internal/base"
+	"github.com/goplus/xgo/tool"
+)
+
+// xgo work init
+var CmdInit = &base.Command{
+	UsageLine: "xgo work init [moddirs]",
+	Short:     "initialize workspace file (go.work) in current directory",
+}
+
+func init() {
+	CmdInit.Run = runInit
+}
+
+func runInit(cmd *base.Command, args []string) {
+	err := cmd.Flag.Parse(args)
+	if err != nil {
+		log.Fatalln("parse input arguments failed:", err)
+	}
+	_, err = tool.InitWork(".", goWorkVer(), cmd.Flag.Args())
+	if errors.Is(err, tool.ErrWorkExists) {
+		fatal("xgo work init: go.work already exists")
+	}
+	check(err)
+}
+
+func goWorkVer() string {
+	ver := strings.TrimPrefix(runtime.Version(), "go")
+	if ver == "" || ver[0] < '0' || ver[0] > '9' { // devel version
+		return "1.18"
+	}
+	if pos := strings.IndexAny(ver, " -+"); pos > 0 {
+		ver = ver[:pos]
+	}
+	return ver
+}
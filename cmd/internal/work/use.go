@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package work
+
+import (
+	"log"
+
+	"github.com/goplus/xgo/cmd/internal/base"
+	"github.com/goplus/xgo/tool"
+)
+
+// xgo work use
+var CmdUse = &base.Command{
+	UsageLine: "xgo work use [-r] [moddirs]",
+	Short:     "add modules to workspace file",
+}
+
+var (
+	flagUse       = &CmdUse.Flag
+	flagRecursive = flagUse.Bool("r", false, "add modules in the directory trees of moddirs")
+)
+
+func init() {
+	CmdUse.Run = runUse
+}
+
+func runUse(cmd *base.Command, args []string) {
+	err := flagUse.Parse(args)
+	if err != nil {
+		log.Fatalln("parse input arguments failed:", err)
+	}
+	work, err := tool.LoadWork(".")
+	check(err)
+	if work == nil {
+		fatal("xgo work use: no go.work file found\n\t(run 'xgo work init' first or specify path using GOWORK environment variable)")
+	}
+	dirs := flagUse.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"."}
+	}
+	err = work.Use(dirs, *flagRecursive)
+	check(err)
+	check(work.Save())
+}
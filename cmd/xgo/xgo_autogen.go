@@ -8,6 +8,7 @@ import (
 	"github.com/goplus/gogen"
 	"github.com/goplus/xgo/cmd/internal/bug"
 	"github.com/goplus/xgo/cmd/internal/build"
+	"github.com/goplus/xgo/cmd/internal/class"
 	"github.com/goplus/xgo/cmd/internal/clean"
 	"github.com/goplus/xgo/cmd/internal/doc"
 	"github.com/goplus/xgo/cmd/internal/env"
@@ -15,11 +16,16 @@ import (
 	"github.com/goplus/xgo/cmd/internal/gopfmt"
 	"github.com/goplus/xgo/cmd/internal/gopget"
 	"github.com/goplus/xgo/cmd/internal/install"
+	"github.com/goplus/xgo/cmd/internal/list"
 	"github.com/goplus/xgo/cmd/internal/mod"
+	"github.com/goplus/xgo/cmd/internal/newproj"
+	"github.com/goplus/xgo/cmd/internal/repl"
 	"github.com/goplus/xgo/cmd/internal/run"
 	"github.com/goplus/xgo/cmd/internal/serve"
 	"github.com/goplus/xgo/cmd/internal/test"
+	"github.com/goplus/xgo/cmd/internal/vet"
 	"github.com/goplus/xgo/cmd/internal/watch"
+	"github.com/goplus/xgo/cmd/internal/work"
 	env1 "github.com/goplus/xgo/env"
 	"github.com/qiniu/x/log"
 	"github.com/qiniu/x/stringutil"
@@ -36,6 +42,14 @@ type Cmd_build struct {
 	xcmd.Command
 	*App
 }
+type Cmd_class_check struct {
+	xcmd.Command
+	*App
+}
+type Cmd_class struct {
+	xcmd.Command
+	*App
+}
 type Cmd_clean struct {
 	xcmd.Command
 	*App
@@ -64,6 +78,10 @@ type Cmd_install struct {
 	xcmd.Command
 	*App
 }
+type Cmd_list struct {
+	xcmd.Command
+	*App
+}
 type App struct {
 	xcmd.App
 }
@@ -75,6 +93,10 @@ type Cmd_mod_download struct {
 	xcmd.Command
 	*App
 }
+type Cmd_mod_graph struct {
+	xcmd.Command
+	*App
+}
 type Cmd_mod_init struct {
 	xcmd.Command
 	*App
@@ -83,6 +105,22 @@ type Cmd_mod_tidy struct {
 	xcmd.Command
 	*App
 }
+type Cmd_mod_vendor struct {
+	xcmd.Command
+	*App
+}
+type Cmd_mod_why struct {
+	xcmd.Command
+	*App
+}
+type Cmd_new struct {
+	xcmd.Command
+	*App
+}
+type Cmd_repl struct {
+	xcmd.Command
+	*App
+}
 type Cmd_run struct {
 	xcmd.Command
 	*App
@@ -99,10 +137,26 @@ type Cmd_version struct {
 	xcmd.Command
 	*App
 }
+type Cmd_vet struct {
+	xcmd.Command
+	*App
+}
 type Cmd_watch struct {
 	xcmd.Command
 	*App
 }
+type Cmd_work struct {
+	xcmd.Command
+	*App
+}
+type Cmd_work_init struct {
+	xcmd.Command
+	*App
+}
+type Cmd_work_use struct {
+	xcmd.Command
+	*App
+}
 //line cmd/xgo/main_app.gox:6
 func (this *App) MainEntry() {
 //line cmd/xgo/main_app.gox:6:1
@@ -115,23 +169,35 @@ func (this *App) MainEntry() {
 func (this *App) Main() {
 	_xgo_obj0 := &Cmd_bug{App: this}
 	_xgo_obj1 := &Cmd_build{App: this}
-	_xgo_obj2 := &Cmd_clean{App: this}
-	_xgo_obj3 := &Cmd_doc{App: this}
-	_xgo_obj4 := &Cmd_env{App: this}
-	_xgo_obj5 := &Cmd_fmt{App: this}
-	_xgo_obj6 := &Cmd_get{App: this}
-	_xgo_obj7 := &Cmd_go{App: this}
-	_xgo_obj8 := &Cmd_install{App: this}
-	_xgo_obj9 := &Cmd_mod{App: this}
-	_xgo_obj10 := &Cmd_mod_download{App: this}
-	_xgo_obj11 := &Cmd_mod_init{App: this}
-	_xgo_obj12 := &Cmd_mod_tidy{App: this}
-	_xgo_obj13 := &Cmd_run{App: this}
-	_xgo_obj14 := &Cmd_serve{App: this}
-	_xgo_obj15 := &Cmd_test{App: this}
-	_xgo_obj16 := &Cmd_version{App: this}
-	_xgo_obj17 := &Cmd_watch{App: this}
-	xcmd.Gopt_App_Main(this, _xgo_obj0, _xgo_obj1, _xgo_obj2, _xgo_obj3, _xgo_obj4, _xgo_obj5, _xgo_obj6, _xgo_obj7, _xgo_obj8, _xgo_obj9, _xgo_obj10, _xgo_obj11, _xgo_obj12, _xgo_obj13, _xgo_obj14, _xgo_obj15, _xgo_obj16, _xgo_obj17)
+	_xgo_obj2 := &Cmd_class_check{App: this}
+	_xgo_obj3 := &Cmd_class{App: this}
+	_xgo_obj4 := &Cmd_clean{App: this}
+	_xgo_obj5 := &Cmd_doc{App: this}
+	_xgo_obj6 := &Cmd_env{App: this}
+	_xgo_obj7 := &Cmd_fmt{App: this}
+	_xgo_obj8 := &Cmd_get{App: this}
+	_xgo_obj9 := &Cmd_go{App: this}
+	_xgo_obj10 := &Cmd_install{App: this}
+	_xgo_obj11 := &Cmd_list{App: this}
+	_xgo_obj12 := &Cmd_mod{App: this}
+	_xgo_obj13 := &Cmd_mod_download{App: this}
+	_xgo_obj14 := &Cmd_mod_graph{App: this}
+	_xgo_obj15 := &Cmd_mod_init{App: this}
+	_xgo_obj16 := &Cmd_mod_tidy{App: this}
+	_xgo_obj17 := &Cmd_mod_vendor{App: this}
+	_xgo_obj18 := &Cmd_mod_why{App: this}
+	_xgo_obj19 := &Cmd_new{App: this}
+	_xgo_obj20 := &Cmd_repl{App: this}
+	_xgo_obj21 := &Cmd_run{App: this}
+	_xgo_obj22 := &Cmd_serve{App: this}
+	_xgo_obj23 := &Cmd_test{App: this}
+	_xgo_obj24 := &Cmd_version{App: this}
+	_xgo_obj25 := &Cmd_vet{App: this}
+	_xgo_obj26 := &Cmd_watch{App: this}
+	_xgo_obj27 := &Cmd_work{App: this}
+	_xgo_obj28 := &Cmd_work_init{App: this}
+	_xgo_obj29 := &Cmd_work_use{App: this}
+	xcmd.Gopt_App_Main(this, _xgo_obj0, _xgo_obj1, _xgo_obj2, _xgo_obj3, _xgo_obj4, _xgo_obj5, _xgo_obj6, _xgo_obj7, _xgo_obj8, _xgo_obj9, _xgo_obj10, _xgo_obj11, _xgo_obj12, _xgo_obj13, _xgo_obj14, _xgo_obj15, _xgo_obj16, _xgo_obj17, _xgo_obj18, _xgo_obj19, _xgo_obj20, _xgo_obj21, _xgo_obj22, _xgo_obj23, _xgo_obj24, _xgo_obj25, _xgo_obj26, _xgo_obj27, _xgo_obj28, _xgo_obj29)
 }
 //line cmd/xgo/bug_cmd.gox:20
 func (this *Cmd_bug) Main(_xgo_arg0 string) {
@@ -169,6 +235,40 @@ func (this *Cmd_build) Main(_xgo_arg0 string) {
 func (this *Cmd_build) Classfname() string {
 	return "build"
 }
+//line cmd/xgo/class_check_cmd.gox:20
+func (this *Cmd_class_check) Main(_xgo_arg0 string) {
+	this.Command.Main(_xgo_arg0)
+//line cmd/xgo/class_check_cmd.gox:20:1
+	this.Use("check [exts]")
+//line cmd/xgo/class_check_cmd.gox:22:1
+	this.Short("Check if class frameworks follow the classfile conventions")
+//line cmd/xgo/class_check_cmd.gox:24:1
+	this.FlagOff()
+//line cmd/xgo/class_check_cmd.gox:26:1
+	this.Run__1(func(args []string) {
+//line cmd/xgo/class_check_cmd.gox:27:1
+		class.CmdCheck.Run(class.CmdCheck, args)
+	})
+}
+func (this *Cmd_class_check) Classfname() string {
+	return "class_check"
+}
+//line cmd/xgo/class_cmd.gox:20
+func (this *Cmd_class) Main(_xgo_arg0 string) {
+	this.Command.Main(_xgo_arg0)
+//line cmd/xgo/class_cmd.gox:20:1
+	this.Use("class")
+//line cmd/xgo/class_cmd.gox:22:1
+	this.Short("Class framework maintenance")
+//line cmd/xgo/class_cmd.gox:24:1
+	this.Run__0(func() {
+//line cmd/xgo/class_cmd.gox:25:1
+		this.Help()
+	})
+}
+func (this *Cmd_class) Classfname() string {
+	return "class"
+}
 //line cmd/xgo/clean_cmd.gox:20
 func (this *Cmd_clean) Main(_xgo_arg0 string) {
 	this.Command.Main(_xgo_arg0)
@@ -295,6 +395,24 @@ func (this *Cmd_install) Main(_xgo_arg0 string) {
 func (this *Cmd_install) Classfname() string {
 	return "install"
 }
+//line cmd/xgo/list_cmd.gox:20
+func (this *Cmd_list) Main(_xgo_arg0 string) {
+	this.Command.Main(_xgo_arg0)
+//line cmd/xgo/list_cmd.gox:20:1
+	this.Use("list [flags] [packages]")
+//line cmd/xgo/list_cmd.gox:22:1
+	this.Short("List packages")
+//line cmd/xgo/list_cmd.gox:24:1
+	this.FlagOff()
+//line cmd/xgo/list_cmd.gox:26:1
+	this.Run__1(func(args []string) {
+//line cmd/xgo/list_cmd.gox:27:1
+		list.Cmd.Run(list.Cmd, args)
+	})
+}
+func (this *Cmd_list) Classfname() string {
+	return "list"
+}
 //line cmd/xgo/mod_cmd.gox:20
 func (this *Cmd_mod) Main(_xgo_arg0 string) {
 	this.Command.Main(_xgo_arg0)
@@ -336,6 +454,24 @@ func (this *Cmd_mod_download) Main(_xgo_arg0 string) {
 func (this *Cmd_mod_download) Classfname() string {
 	return "mod_download"
 }
+//line cmd/xgo/mod_graph_cmd.gox:20
+func (this *Cmd_mod_graph) Main(_xgo_arg0 string) {
+	this.Command.Main(_xgo_arg0)
+//line cmd/xgo/mod_graph_cmd.gox:20:1
+	this.Use("graph [-json]")
+//line cmd/xgo/mod_graph_cmd.gox:22:1
+	this.Short("print module requirement graph (including class frameworks)")
+//line cmd/xgo/mod_graph_cmd.gox:24:1
+	this.FlagOff()
+//line cmd/xgo/mod_graph_cmd.gox:26:1
+	this.Run__1(func(args []string) {
+//line cmd/xgo/mod_graph_cmd.gox:27:1
+		mod.CmdGraph.Run(mod.CmdGraph, args)
+	})
+}
+func (this *Cmd_mod_graph) Classfname() string {
+	return "mod_graph"
+}
 //line cmd/xgo/mod_init_cmd.gox:20
 func (this *Cmd_mod_init) Main(_xgo_arg0 string) {
 	this.Command.Main(_xgo_arg0)
@@ -379,6 +515,85 @@ func (this *Cmd_mod_tidy) Main(_xgo_arg0 string) {
 func (this *Cmd_mod_tidy) Classfname() string {
 	return "mod_tidy"
 }
+//line cmd/xgo/mod_vendor_cmd.gox:20
+func (this *Cmd_mod_vendor) Main(_xgo_arg0 string) {
+	this.Command.Main(_xgo_arg0)
+//line cmd/xgo/mod_vendor_cmd.gox:20:1
+	this.Use("vendor")
+//line cmd/xgo/mod_vendor_cmd.gox:22:1
+	this.Short("make vendored copy of dependencies (including generated Go code)")
+//line cmd/xgo/mod_vendor_cmd.gox:24:1
+	this.FlagOff()
+//line cmd/xgo/mod_vendor_cmd.gox:26:1
+	this.Run__1(func(args []string) {
+//line cmd/xgo/mod_vendor_cmd.gox:27:1
+		mod.CmdVendor.Run(mod.CmdVendor, args)
+	})
+}
+func (this *Cmd_mod_vendor) Classfname() string {
+	return "mod_vendor"
+}
+//line cmd/xgo/mod_why_cmd.gox:20
+func (this *Cmd_mod_why) Main(_xgo_arg0 string) {
+	this.Command.Main(_xgo_arg0)
+//line cmd/xgo/mod_why_cmd.gox:20:1
+	this.Use("why [-m] packages")
+//line cmd/xgo/mod_why_cmd.gox:22:1
+	this.Short("explain why packages or modules are needed")
+//line cmd/xgo/mod_why_cmd.gox:24:1
+	this.FlagOff()
+//line cmd/xgo/mod_why_cmd.gox:26:1
+	this.Run__1(func(args []string) {
+//line cmd/xgo/mod_why_cmd.gox:27:1
+		if len(args) < 1 {
+//line cmd/xgo/mod_why_cmd.gox:28:1
+			this.Help()
+//line cmd/xgo/mod_why_cmd.gox:29:1
+			return
+		}
+//line cmd/xgo/mod_why_cmd.gox:31:1
+		mod.CmdWhy.Run(mod.CmdWhy, args)
+	})
+}
+func (this *Cmd_mod_why) Classfname() string {
+	return "mod_why"
+}
+//line cmd/xgo/new_cmd.gox:20
+func (this *Cmd_new) Main(_xgo_arg0 string) {
+	this.Command.Main(_xgo_arg0)
+//line cmd/xgo/new_cmd.gox:20:1
+	this.Use("new [flags] template dir")
+//line cmd/xgo/new_cmd.gox:22:1
+	this.Short("Create a new project from a template")
+//line cmd/xgo/new_cmd.gox:24:1
+	this.FlagOff()
+//line cmd/xgo/new_cmd.gox:26:1
+	this.Run__1(func(args []string) {
+//line cmd/xgo/new_cmd.gox:27:1
+		newproj.Cmd.Run(newproj.Cmd, args)
+	})
+}
+func (this *Cmd_new) Classfname() string {
+	return "new"
+}
+//line cmd/xgo/repl_cmd.gox:20
+func (this *Cmd_repl) Main(_xgo_arg0 string) {
+	this.Command.Main(_xgo_arg0)
+//line cmd/xgo/repl_cmd.gox:20:1
+	this.Use("repl [flags]")
+//line cmd/xgo/repl_cmd.gox:22:1
+	this.Short("Start an interactive XGo session")
+//line cmd/xgo/repl_cmd.gox:24:1
+	this.FlagOff()
+//line cmd/xgo/repl_cmd.gox:26:1
+	this.Run__1(func(args []string) {
+//line cmd/xgo/repl_cmd.gox:27:1
+		repl.Cmd.Run(repl.Cmd, args)
+	})
+}
+func (this *Cmd_repl) Classfname() string {
+	return "repl"
+}
 //line cmd/xgo/run_cmd.gox:20
 func (this *Cmd_run) Main(_xgo_arg0 string) {
 	this.Command.Main(_xgo_arg0)
@@ -449,6 +664,24 @@ func (this *Cmd_version) Main(_xgo_arg0 string) {
 func (this *Cmd_version) Classfname() string {
 	return "version"
 }
+//line cmd/xgo/vet_cmd.gox:20
+func (this *Cmd_vet) Main(_xgo_arg0 string) {
+	this.Command.Main(_xgo_arg0)
+//line cmd/xgo/vet_cmd.gox:20:1
+	this.Use("vet [flags] [packages]")
+//line cmd/xgo/vet_cmd.gox:22:1
+	this.Short("Report likely mistakes in XGo packages")
+//line cmd/xgo/vet_cmd.gox:24:1
+	this.FlagOff()
+//line cmd/xgo/vet_cmd.gox:26:1
+	this.Run__1(func(args []string) {
+//line cmd/xgo/vet_cmd.gox:27:1
+		vet.Cmd.Run(vet.Cmd, args)
+	})
+}
+func (this *Cmd_vet) Classfname() string {
+	return "vet"
+}
 //line cmd/xgo/watch_cmd.gox:20
 func (this *Cmd_watch) Main(_xgo_arg0 string) {
 	this.Command.Main(_xgo_arg0)
@@ -467,6 +700,58 @@ func (this *Cmd_watch) Main(_xgo_arg0 string) {
 func (this *Cmd_watch) Classfname() string {
 	return "watch"
 }
+//line cmd/xgo/work_cmd.gox:20
+func (this *Cmd_work) Main(_xgo_arg0 string) {
+	this.Command.Main(_xgo_arg0)
+//line cmd/xgo/work_cmd.gox:20:1
+	this.Use("work")
+//line cmd/xgo/work_cmd.gox:22:1
+	this.Short("Workspace maintenance")
+//line cmd/xgo/work_cmd.gox:24:1
+	this.Run__0(func() {
+//line cmd/xgo/work_cmd.gox:25:1
+		this.Help()
+	})
+}
+func (this *Cmd_work) Classfname() string {
+	return "work"
+}
+//line cmd/xgo/work_init_cmd.gox:20
+func (this *Cmd_work_init) Main(_xgo_arg0 string) {
+	this.Command.Main(_xgo_arg0)
+//line cmd/xgo/work_init_cmd.gox:20:1
+	this.Use("init [moddirs]")
+//line cmd/xgo/work_init_cmd.gox:22:1
+	this.Short("initialize workspace file (go.work) in current directory")
+//line cmd/xgo/work_init_cmd.gox:24:1
+	this.FlagOff()
+//line cmd/xgo/work_init_cmd.gox:26:1
+	this.Run__1(func(args []string) {
+//line cmd/xgo/work_init_cmd.gox:27:1
+		work.CmdInit.Run(work.CmdInit, args)
+	})
+}
+func (this *Cmd_work_init) Classfname() string {
+	return "work_init"
+}
+//line cmd/xgo/work_use_cmd.gox:20
+func (this *Cmd_work_use) Main(_xgo_arg0 string) {
+	this.Command.Main(_xgo_arg0)
+//line cmd/xgo/work_use_cmd.gox:20:1
+	this.Use("use [-r] [moddirs]")
+//line cmd/xgo/work_use_cmd.gox:22:1
+	this.Short("add modules to workspace file")
+//line cmd/xgo/work_use_cmd.gox:24:1
+	this.FlagOff()
+//line cmd/xgo/work_use_cmd.gox:26:1
+	this.Run__1(func(args []string) {
+//line cmd/xgo/work_use_cmd.gox:27:1
+		work.CmdUse.Run(work.CmdUse, args)
+	})
+}
+func (this *Cmd_work_use) Classfname() string {
+	return "work_use"
+}
 func main() {
 	new(App).Main()
 }
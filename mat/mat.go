@@ -0,0 +1,269 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package mat provides a dense numeric matrix that XGo matrix literals can
+// target:
+//
+//	var m mat.Dense = [
+//		1, 2, 3
+//		4, 5, 6
+//	]
+//
+// Dense overloads + and - (element-wise), * (matrix product, or scaling by a
+// float64) and / (dividing by a float64).
+package mat
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	XGoPackage = true
+)
+
+// -----------------------------------------------------------------------------
+
+// Dense is a matrix of float64 values stored in row-major order. Dense values
+// share their storage when copied.
+type Dense struct {
+	rows, cols int
+	data       []float64
+}
+
+// Dense(rows) casts a Dense matrix from its rows. It panics if rows don't have
+// the same length.
+func Dense_Cast(rows [][]float64) Dense {
+	if len(rows) == 0 {
+		return Dense{}
+	}
+	cols := len(rows[0])
+	data := make([]float64, 0, len(rows)*cols)
+	for i, row := range rows {
+		if len(row) != cols {
+			panic(fmt.Sprintf("mat: inconsistent row length: row %d has %d elements, want %d", i, len(row), cols))
+		}
+		data = append(data, row...)
+	}
+	return Dense{rows: len(rows), cols: cols, data: data}
+}
+
+// New creates a rows x cols matrix backed by data in row-major order. If data
+// is nil, a zero matrix is allocated.
+func New(rows, cols int, data []float64) Dense {
+	if rows < 0 || cols < 0 {
+		panic("mat: negative dimension")
+	}
+	if data == nil {
+		data = make([]float64, rows*cols)
+	} else if len(data) != rows*cols {
+		panic(fmt.Sprintf("mat: data length %d doesn't match shape %dx%d", len(data), rows, cols))
+	}
+	return Dense{rows: rows, cols: cols, data: data}
+}
+
+// Zeros creates a rows x cols zero matrix.
+func Zeros(rows, cols int) Dense {
+	return New(rows, cols, nil)
+}
+
+// Identity creates an n x n identity matrix.
+func Identity(n int) Dense {
+	ret := Zeros(n, n)
+	for i := 0; i < n; i++ {
+		ret.data[i*n+i] = 1
+	}
+	return ret
+}
+
+// Dims returns the number of rows and columns of the matrix.
+func (a Dense) Dims() (rows, cols int) {
+	return a.rows, a.cols
+}
+
+// Data returns the underlying row-major storage of the matrix.
+func (a Dense) Data() []float64 {
+	return a.data
+}
+
+// At returns the element at row i and column j.
+func (a Dense) At(i, j int) float64 {
+	return a.data[a.index(i, j)]
+}
+
+// Set sets the element at row i and column j to v.
+func (a Dense) Set(i, j int, v float64) {
+	a.data[a.index(i, j)] = v
+}
+
+func (a Dense) index(i, j int) int {
+	if uint(i) >= uint(a.rows) || uint(j) >= uint(a.cols) {
+		panic(fmt.Sprintf("mat: index (%d, %d) out of range for %dx%d matrix", i, j, a.rows, a.cols))
+	}
+	return i*a.cols + j
+}
+
+// Row returns a copy of row i.
+func (a Dense) Row(i int) []float64 {
+	a.index(i, 0)
+	return append([]float64(nil), a.data[i*a.cols:(i+1)*a.cols]...)
+}
+
+// Rows returns a copy of the matrix as a slice of rows.
+func (a Dense) Rows() [][]float64 {
+	ret := make([][]float64, a.rows)
+	for i := range ret {
+		ret[i] = a.Row(i)
+	}
+	return ret
+}
+
+// T returns the transpose of the matrix.
+func (a Dense) T() Dense {
+	ret := Zeros(a.cols, a.rows)
+	for i := 0; i < a.rows; i++ {
+		for j := 0; j < a.cols; j++ {
+			ret.data[j*a.rows+i] = a.data[i*a.cols+j]
+		}
+	}
+	return ret
+}
+
+// Clone returns a copy of the matrix that doesn't share storage with it.
+func (a Dense) Clone() Dense {
+	return Dense{rows: a.rows, cols: a.cols, data: append([]float64(nil), a.data...)}
+}
+
+// Equal reports whether a and b have the same shape and elements.
+func (a Dense) Equal(b Dense) bool {
+	if a.rows != b.rows || a.cols != b.cols {
+		return false
+	}
+	for i, v := range a.data {
+		if v != b.data[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns a matrix whose elements are fn applied to the elements of a.
+func (a Dense) Apply(fn func(v float64) float64) Dense {
+	ret := Zeros(a.rows, a.cols)
+	for i, v := range a.data {
+		ret.data[i] = fn(v)
+	}
+	return ret
+}
+
+func (a Dense) elemwise(op string, b Dense, fn func(x, y float64) float64) Dense {
+	if a.rows != b.rows || a.cols != b.cols {
+		panic(fmt.Sprintf("mat: mismatched shapes %dx%d %s %dx%d", a.rows, a.cols, op, b.rows, b.cols))
+	}
+	ret := Zeros(a.rows, a.cols)
+	for i, v := range a.data {
+		ret.data[i] = fn(v, b.data[i])
+	}
+	return ret
+}
+
+// MulElem returns the element-wise product of a and b.
+func (a Dense) MulElem(b Dense) Dense {
+	return a.elemwise(".*", b, func(x, y float64) float64 { return x * y })
+}
+
+// DivElem returns the element-wise quotient of a and b.
+func (a Dense) DivElem(b Dense) Dense {
+	return a.elemwise("./", b, func(x, y float64) float64 { return x / y })
+}
+
+// a + b
+func (a Dense) XGo_Add(b Dense) Dense {
+	return a.elemwise("+", b, func(x, y float64) float64 { return x + y })
+}
+
+// a - b
+func (a Dense) XGo_Sub(b Dense) Dense {
+	return a.elemwise("-", b, func(x, y float64) float64 { return x - y })
+}
+
+// a * b (matrix product)
+func (a Dense) XGo_Mul__0(b Dense) Dense {
+	if a.cols != b.rows {
+		panic(fmt.Sprintf("mat: mismatched shapes %dx%d * %dx%d", a.rows, a.cols, b.rows, b.cols))
+	}
+	ret := Zeros(a.rows, b.cols)
+	for i := 0; i < a.rows; i++ {
+		row := ret.data[i*b.cols : (i+1)*b.cols]
+		for k := 0; k < a.cols; k++ {
+			x := a.data[i*a.cols+k]
+			if x == 0 {
+				continue
+			}
+			for j, y := range b.data[k*b.cols : (k+1)*b.cols] {
+				row[j] += x * y
+			}
+		}
+	}
+	return ret
+}
+
+// a * s
+func (a Dense) XGo_Mul__1(s float64) Dense {
+	return a.Apply(func(v float64) float64 { return v * s })
+}
+
+// a / s
+func (a Dense) XGo_Quo(s float64) Dense {
+	return a.Apply(func(v float64) float64 { return v / s })
+}
+
+// -a
+func (a Dense) XGo_Neg() Dense {
+	return a.Apply(func(v float64) float64 { return -v })
+}
+
+// a == b
+func (a Dense) XGo_EQ(b Dense) bool {
+	return a.Equal(b)
+}
+
+// a != b
+func (a Dense) XGo_NE(b Dense) bool {
+	return !a.Equal(b)
+}
+
+// String returns the matrix in XGo matrix literal syntax.
+func (a Dense) String() string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i := 0; i < a.rows; i++ {
+		if i > 0 {
+			b.WriteString("; ")
+		}
+		for j, v := range a.data[i*a.cols : (i+1)*a.cols] {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,71 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mat
+
+import "testing"
+
+func TestDense(t *testing.T) {
+	a := Dense_Cast([][]float64{{1, 2, 3}, {4, 5, 6}})
+	if r, c := a.Dims(); r != 2 || c != 3 {
+		t.Fatal("Dims:", r, c)
+	}
+	if v := a.At(1, 2); v != 6 {
+		t.Fatal("At:", v)
+	}
+	if s := a.String(); s != "[1, 2, 3; 4, 5, 6]" {
+		t.Fatal("String:", s)
+	}
+	if s := a.T().String(); s != "[1, 4; 2, 5; 3, 6]" {
+		t.Fatal("T:", s)
+	}
+	if s := a.XGo_Mul__0(a.T()).String(); s != "[14, 32; 32, 77]" {
+		t.Fatal("XGo_Mul__0:", s)
+	}
+	if s := a.XGo_Add(a).XGo_Sub(a.MulElem(a)).String(); s != "[1, 0, -3; -8, -15, -24]" {
+		t.Fatal("XGo_Add/XGo_Sub/MulElem:", s)
+	}
+	if s := a.XGo_Mul__1(2).XGo_Quo(4).XGo_Neg().String(); s != "[-0.5, -1, -1.5; -2, -2.5, -3]" {
+		t.Fatal("XGo_Mul__1/XGo_Quo/XGo_Neg:", s)
+	}
+	if !Identity(2).XGo_EQ(New(2, 2, []float64{1, 0, 0, 1})) || Identity(2).XGo_NE(Identity(2)) {
+		t.Fatal("XGo_EQ/XGo_NE")
+	}
+	b := a.Clone()
+	b.Set(0, 0, 7)
+	if a.At(0, 0) != 1 || b.Rows()[0][0] != 7 {
+		t.Fatal("Clone/Set")
+	}
+}
+
+func TestDensePanic(t *testing.T) {
+	for name, fn := range map[string]func(){
+		"ragged": func() { Dense_Cast([][]float64{{1, 2}, {3}}) },
+		"matmul": func() { Zeros(2, 3).XGo_Mul__0(Zeros(2, 3)) },
+		"add":    func() { Zeros(2, 3).XGo_Add(Zeros(3, 2)) },
+		"index":  func() { Zeros(2, 3).At(2, 0) },
+		"data":   func() { New(2, 2, []float64{1}) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatal(name, ": no panic")
+				}
+			}()
+			fn()
+		}()
+	}
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+// -----------------------------------------------------------------------------
+
+// argField is an argument of a tool or a prompt, which is a field of its
+// work class.
+//
+// The name of an argument is the name of its field, or the name specified by
+// its `json` tag. Its description is specified by the `_` tag, which is what
+// a string tag without keys means in XGo:
+//
+//	var (
+//		City string "name of the city"
+//		Days *int   "number of days to forecast"
+//	)
+//
+// An argument is required unless its field is a pointer or its `json` tag has
+// the omitempty option.
+type argField struct {
+	name     string
+	desc     string
+	required bool
+	index    int
+	typ      reflect.Type
+}
+
+// argFieldsOf returns arguments of a tool or a prompt, whose work class is t.
+// Embedded fields, eg. the base class and the project class, are skipped.
+func argFieldsOf(t reflect.Type) (args []*argField) {
+	for i, n := 0, t.NumField(); i < n; i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			continue
+		}
+		name, opts := f.Name, ""
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			var v string
+			v, opts, _ = strings.Cut(tag, ",")
+			if v == "-" {
+				continue
+			}
+			if v != "" {
+				name = v
+			}
+		}
+		args = append(args, &argField{
+			name:     name,
+			desc:     f.Tag.Get("_"),
+			required: f.Type.Kind() != reflect.Pointer && !strings.Contains(opts, "omitempty"),
+			index:    i,
+			typ:      f.Type,
+		})
+	}
+	return
+}
+
+// setArgs sets fields of the work object obj to the values of args. Fields of
+// missing optional arguments are reset to zero values.
+func setArgs(obj reflect.Value, fields []*argField, args map[string]json.RawMessage) error {
+	for _, f := range fields {
+		v := fieldOf(obj, f.index)
+		v.SetZero()
+		data, ok := args[f.name]
+		if !ok {
+			if f.required {
+				return fmt.Errorf("missing argument %q", f.name)
+			}
+			continue
+		}
+		if err := json.Unmarshal(data, v.Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid argument %q: %v", f.name, err)
+		}
+	}
+	return nil
+}
+
+// fieldOf returns the i-th field of obj, which is settable even if it is an
+// unexported field of a classfile.
+func fieldOf(obj reflect.Value, i int) reflect.Value {
+	v := obj.Field(i)
+	if !v.CanSet() {
+		v = reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+	}
+	return v
+}
+
+// -----------------------------------------------------------------------------
+
+// Schema is a JSON schema describing the input of a tool.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// inputSchemaOf returns the JSON schema of a tool input, whose arguments are
+// fields.
+func inputSchemaOf(fields []*argField) *Schema {
+	ret := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, f := range fields {
+		s := schemaOf(f.typ, nil)
+		s.Description = f.desc
+		ret.Properties[f.name] = s
+		if f.required {
+			ret.Required = append(ret.Required, f.name)
+		}
+	}
+	return ret
+}
+
+// schemaOf returns the JSON schema of values of type t. Recursive struct
+// types in visiting are described as any values.
+func schemaOf(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem(), visiting)
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 { // []byte is encoded as a base64 string
+			return &Schema{Type: "string"}
+		}
+		return &Schema{Type: "array", Items: schemaOf(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOf(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			break
+		}
+		if visiting == nil {
+			visiting = make(map[reflect.Type]bool)
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		ret := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		for i, n := 0, t.NumField(); i < n; i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name := f.Name
+			if tag, ok := f.Tag.Lookup("json"); ok {
+				if v, _, _ := strings.Cut(tag, ","); v == "-" {
+					continue
+				} else if v != "" {
+					name = v
+				}
+			}
+			s := schemaOf(f.Type, visiting)
+			s.Description = f.Tag.Get("_")
+			ret.Properties[name] = s
+		}
+		return ret
+	}
+	return &Schema{} // any value
+}
+
+// -----------------------------------------------------------------------------
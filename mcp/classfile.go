@@ -0,0 +1,167 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package mcp implements the classfile of MCP (Model Context Protocol) servers.
+//
+// To use it, declare the classfile in gox.mod of your module:
+//
+//	project _mcp.gox Game github.com/goplus/xgo/mcp
+//	class -prefix=Tool_ _tool.gox Tool ToolProto
+//	class -embed _prompt.gox Prompt PromptProto
+//	class _res.gox Resource ResourceProto
+//
+// Each xxx_tool.gox file is a tool named xxx. Its fields are the arguments of
+// the tool, from which the JSON schema of its input is derived, and its body
+// returns the result of calling it. Prompts and resources are similar. Then
+// `xgo run .` serves them as an MCP server over stdio.
+package mcp
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/goplus/xgo/x/fakenet"
+)
+
+const (
+	GopPackage = true
+)
+
+// -----------------------------------------------------------------------------
+
+// Game represents an MCP server, which is the project class of _mcp.gox files.
+type Game struct {
+	name         string
+	version      string
+	instructions string
+
+	tools     []*tool
+	prompts   []*prompt
+	resources []*resource
+}
+
+func (p *Game) initGame(resources []ResourceProto, tools []ToolProto, prompts []PromptProto) *Game {
+	if p.name == "" {
+		p.name = "xgo-mcp"
+	}
+	if p.version == "" {
+		p.version = "0.1.0"
+	}
+	for _, t := range tools {
+		p.tools = append(p.tools, newTool(t))
+	}
+	for _, t := range prompts {
+		p.prompts = append(p.prompts, newPrompt(t))
+	}
+	for _, t := range resources {
+		p.resources = append(p.resources, newResource(t))
+	}
+	return p
+}
+
+// Server sets name of the MCP server.
+func (p *Game) Server(name string) {
+	p.name = name
+}
+
+// Version sets version of the MCP server.
+func (p *Game) Version(version string) {
+	p.version = version
+}
+
+// Instructions sets instructions describing how to use the MCP server.
+func (p *Game) Instructions(instructions string) {
+	p.instructions = instructions
+}
+
+// Gopt_Game_Main is required by XGo compiler as the entry of an MCP server.
+func Gopt_Game_Main(game interface {
+	initGame(resources []ResourceProto, tools []ToolProto, prompts []PromptProto) *Game
+}, resources []ResourceProto, tools []ToolProto, prompts []PromptProto) {
+	p := game.initGame(resources, tools, prompts)
+	if me, ok := game.(interface{ MainEntry() }); ok {
+		me.MainEntry()
+	}
+	stdio := fakenet.NewConn("stdio", os.Stdin, os.Stdout)
+	if err := p.Serve(context.Background(), stdio); err != nil {
+		log.Fatalln("mcp:", err)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Tool represents an MCP tool, which is the work class of _tool.gox files.
+// The fields of a tool are its arguments.
+type Tool struct {
+}
+
+// Main is called before the body of a tool runs. It does nothing.
+func (p *Tool) Main(name string) any {
+	return nil
+}
+
+// ToolProto is the prototype of tools.
+//
+// The result of Main can be a string, a Content, a []Content, a *ToolResult
+// or an error. Other values are returned as JSON text.
+type ToolProto interface {
+	Main(name string) any
+}
+
+// Prompt represents an MCP prompt, which is the work class of _prompt.gox
+// files. The fields of a prompt are its arguments.
+type Prompt struct {
+}
+
+// Main is called before the body of a prompt runs. It does nothing.
+func (p *Prompt) Main(name string) any {
+	return nil
+}
+
+// PromptProto is the prototype of prompts.
+//
+// The result of Main can be a string, which is a message from the user, a
+// Message, a []Message or an error.
+type PromptProto interface {
+	Main(name string) any
+}
+
+// Resource represents an MCP resource, which is the work class of _res.gox
+// files.
+type Resource struct {
+}
+
+// Main is called before the body of a resource runs. It does nothing.
+func (p *Resource) Main(uri string) any {
+	return nil
+}
+
+// ResourceProto is the prototype of resources.
+//
+// The result of Main can be a string, which is the text of the resource, a
+// []byte, which is the binary data of the resource, or an error.
+type ResourceProto interface {
+	Main(uri string) any
+}
+
+// Describer can be implemented by tools, prompts and resources to describe
+// themselves, by defining a Description method in their classfiles.
+type Describer interface {
+	Description() string
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,183 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// the following types are what XGo generates for tools, prompts and
+// resources of an MCP server.
+
+type testGame struct {
+	Game
+}
+
+type Tool_add struct {
+	Tool
+	*testGame
+	X     int      `_:"the first number"`
+	Y     int      `_:"the second number"`
+	scale *float64 `_:"optional scale"`
+}
+
+func (this *Tool_add) Description() string {
+	return "Add two numbers"
+}
+
+func (this *Tool_add) Main(_xgo_arg0 string) any {
+	this.Tool.Main(_xgo_arg0)
+	if this.X < 0 {
+		return errors.New("negative X")
+	}
+	if this.Y < 0 {
+		panic("negative Y")
+	}
+	if this.scale != nil {
+		return float64(this.X+this.Y) * *this.scale
+	}
+	return this.X + this.Y
+}
+
+type greet struct {
+	Prompt
+	*testGame
+	name  string `_:"who to greet"`
+	times *int
+}
+
+func (this *greet) Main(_xgo_arg0 string) any {
+	this.Prompt.Main(_xgo_arg0)
+	n := 1
+	if this.times != nil {
+		n = *this.times
+	}
+	return strings.Repeat("Hello "+this.name+"! ", n)
+}
+
+type readme struct {
+	Resource
+	*testGame
+}
+
+func (this *readme) Main(_xgo_arg0 string) any {
+	this.Resource.Main(_xgo_arg0)
+	return "This is a demo."
+}
+
+func TestServe(t *testing.T) {
+	game := new(testGame)
+	game.Server("demo")
+	game.initGame([]ResourceProto{&readme{testGame: game}}, []ToolProto{&Tool_add{testGame: game}}, []PromptProto{&greet{testGame: game}})
+
+	server, client := net.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- game.Serve(context.Background(), server)
+	}()
+	r := bufio.NewReader(client)
+	call := func(req, expect string) {
+		t.Helper()
+		if _, err := client.Write([]byte(req + "\n")); err != nil {
+			t.Fatal("Write:", err)
+		}
+		if expect == "" { // notification
+			return
+		}
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal("ReadString:", err)
+		}
+		var ret struct {
+			Result json.RawMessage `json:"result"`
+			Error  json.RawMessage `json:"error"`
+		}
+		if err = json.Unmarshal([]byte(line), &ret); err != nil {
+			t.Fatal("Unmarshal:", err)
+		}
+		got := string(ret.Result)
+		if ret.Error != nil {
+			got = "error: " + string(ret.Error)
+		}
+		if got != expect {
+			t.Fatalf("%s:\ngot:    %s\nexpect: %s", req, got, expect)
+		}
+	}
+	call(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"protocolVersion":"2024-11-05","capabilities":{"prompts":{},"resources":{},"tools":{}},"serverInfo":{"name":"demo","version":"0.1.0"}}`)
+	call(`{"jsonrpc":"2.0","method":"notifications/initialized"}`, "")
+	call(`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"tools":[{"name":"add","description":"Add two numbers","inputSchema":{"type":"object","properties":{"X":{"type":"integer","description":"the first number"},"Y":{"type":"integer","description":"the second number"},"scale":{"type":"number","description":"optional scale"}},"required":["X","Y"]}}]}`)
+	call(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"add","arguments":{"X":1,"Y":2,"scale":1.5}}}`,
+		`{"content":[{"type":"text","text":"4.5"}]}`)
+	call(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"add","arguments":{"X":1,"Y":2}}}`,
+		`{"content":[{"type":"text","text":"3"}]}`)
+	call(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"add","arguments":{"X":-1,"Y":2}}}`,
+		`{"content":[{"type":"text","text":"negative X"}],"isError":true}`)
+	call(`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"add","arguments":{"X":1,"Y":-2}}}`,
+		`{"content":[{"type":"text","text":"panic: negative Y"}],"isError":true}`)
+	call(`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"add","arguments":{"X":1}}}`,
+		`error: {"code":-32602,"message":"JSON RPC invalid params: tool add: missing argument \"Y\""}`)
+	call(`{"jsonrpc":"2.0","id":8,"method":"tools/call","params":{"name":"sub"}}`,
+		`error: {"code":-32602,"message":"JSON RPC invalid params: unknown tool \"sub\""}`)
+	call(`{"jsonrpc":"2.0","id":9,"method":"prompts/list"}`,
+		`{"prompts":[{"name":"greet","arguments":[{"name":"name","description":"who to greet","required":true},{"name":"times"}]}]}`)
+	call(`{"jsonrpc":"2.0","id":10,"method":"prompts/get","params":{"name":"greet","arguments":{"name":"Bob","times":"2"}}}`,
+		`{"messages":[{"role":"user","content":{"type":"text","text":"Hello Bob! Hello Bob! "}}]}`)
+	call(`{"jsonrpc":"2.0","id":11,"method":"resources/list"}`,
+		`{"resources":[{"uri":"res:///readme","name":"readme","mimeType":"text/plain"}]}`)
+	call(`{"jsonrpc":"2.0","id":12,"method":"resources/read","params":{"uri":"res:///readme"}}`,
+		`{"contents":[{"uri":"res:///readme","mimeType":"text/plain","text":"This is a demo."}]}`)
+	call(`{"jsonrpc":"2.0","id":13,"method":"resources/read","params":{"uri":"res:///foo"}}`,
+		`error: {"code":-32002,"message":"Resource not found: res:///foo"}`)
+	call(`{"jsonrpc":"2.0","id":14,"method":"ping"}`, `{}`)
+
+	client.Close()
+	if err := <-done; err != nil {
+		t.Fatal("Serve:", err)
+	}
+}
+
+type node struct {
+	Name     string
+	Children []*node `json:"children"`
+	Skip     int     `json:"-"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	data, _ := json.Marshal(schemaOf(reflect.TypeFor[map[string][]node](), nil))
+	const expect = `{"type":"object","additionalProperties":{"type":"array","items":{"type":"object","properties":{"Name":{"type":"string"},"children":{"type":"array","items":{}}}}}}`
+	if string(data) != expect {
+		t.Fatal("schemaOf:", string(data))
+	}
+	// only []byte is encoded as a base64 string, [N]byte is an array of numbers
+	data, _ = json.Marshal(schemaOf(reflect.TypeFor[struct {
+		B []byte
+		A [4]byte
+	}](), nil))
+	const expectBytes = `{"type":"object","properties":{"A":{"type":"array","items":{"type":"integer"}},"B":{"type":"string"}}}`
+	if string(data) != expectBytes {
+		t.Fatal("schemaOf:", string(data))
+	}
+}
@@ -0,0 +1,431 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/goplus/xgo/x/jsonrpc2"
+)
+
+// ProtocolVersion is the latest version of MCP supported.
+const ProtocolVersion = "2025-06-18"
+
+var protocolVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// -----------------------------------------------------------------------------
+
+// Content is a content of a tool result or a prompt message.
+type Content struct {
+	Type     string `json:"type"` // text, image or audio
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"` // base64-encoded data of an image or an audio
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// TextContent returns a text content.
+func TextContent(text string) Content {
+	return Content{Type: "text", Text: text}
+}
+
+// ImageContent returns an image content.
+func ImageContent(data []byte, mimeType string) Content {
+	return Content{Type: "image", Data: base64.StdEncoding.EncodeToString(data), MimeType: mimeType}
+}
+
+// ToolResult is the result of calling a tool.
+type ToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// Message is a message of a prompt.
+type Message struct {
+	Role    string  `json:"role"` // user or assistant
+	Content Content `json:"content"`
+}
+
+// -----------------------------------------------------------------------------
+
+// work is a tool, a prompt or a resource.
+type work struct {
+	name string
+	desc string
+	obj  reflect.Value // the work object, which is a struct
+	args []*argField
+}
+
+func newWork(proto any, prefix string) work {
+	v := reflect.ValueOf(proto).Elem()
+	ret := work{
+		name: strings.TrimPrefix(v.Type().Name(), prefix),
+		obj:  v,
+		args: argFieldsOf(v.Type()),
+	}
+	if d, ok := proto.(Describer); ok {
+		ret.desc = d.Description()
+	}
+	return ret
+}
+
+type tool struct {
+	work
+	proto ToolProto
+}
+
+func newTool(proto ToolProto) *tool {
+	return &tool{newWork(proto, "Tool_"), proto}
+}
+
+type prompt struct {
+	work
+	proto PromptProto
+}
+
+func newPrompt(proto PromptProto) *prompt {
+	return &prompt{newWork(proto, "Prompt_"), proto}
+}
+
+type resource struct {
+	work
+	uri      string
+	mimeType string
+	proto    ResourceProto
+}
+
+func newResource(proto ResourceProto) *resource {
+	ret := &resource{work: newWork(proto, "Res_"), proto: proto}
+	ret.uri = "res:///" + ret.name
+	if v, ok := proto.(interface{ URI() string }); ok {
+		ret.uri = v.URI()
+	}
+	ret.mimeType = "text/plain"
+	if v, ok := proto.(interface{ MimeType() string }); ok {
+		ret.mimeType = v.MimeType()
+	}
+	return ret
+}
+
+// -----------------------------------------------------------------------------
+
+// Serve serves the MCP server on rwc with newline-delimited JSON messages,
+// until rwc is closed by the client.
+func (p *Game) Serve(ctx context.Context, rwc io.ReadWriteCloser) error {
+	mux := p.newMux()
+	conn, err := jsonrpc2.Dial(ctx, dialer{rwc}, jsonrpc2.BinderFunc(
+		func(ctx context.Context, c *jsonrpc2.Connection) jsonrpc2.ConnectionOptions {
+			return jsonrpc2.ConnectionOptions{
+				Framer:    jsonrpc2.NDJSONFramer(),
+				Preempter: cancelPreempter(c),
+				Handler:   mux,
+			}
+		}), nil)
+	if err != nil {
+		return err
+	}
+	return conn.Wait()
+}
+
+type dialer struct {
+	rwc io.ReadWriteCloser
+}
+
+func (p dialer) Dial(ctx context.Context) (io.ReadWriteCloser, error) {
+	return p.rwc, nil
+}
+
+// cancelPreempter handles notifications/cancelled by canceling the request.
+func cancelPreempter(c *jsonrpc2.Connection) jsonrpc2.Preempter {
+	return jsonrpc2.PreempterFunc(func(ctx context.Context, req *jsonrpc2.Request) (any, error) {
+		if req.Method != "notifications/cancelled" {
+			return nil, jsonrpc2.ErrNotHandled
+		}
+		var params struct {
+			RequestID any `json:"requestId"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, fmt.Errorf("%w: %v", jsonrpc2.ErrInvalidParams, err)
+		}
+		switch id := params.RequestID.(type) {
+		case float64:
+			c.Cancel(jsonrpc2.Int64ID(int64(id)))
+		case string:
+			c.Cancel(jsonrpc2.StringID(id))
+		}
+		return nil, nil
+	})
+}
+
+type (
+	implementation struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	initializeParams struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	initializeResult struct {
+		ProtocolVersion string         `json:"protocolVersion"`
+		Capabilities    map[string]any `json:"capabilities"`
+		ServerInfo      implementation `json:"serverInfo"`
+		Instructions    string         `json:"instructions,omitempty"`
+	}
+	toolInfo struct {
+		Name        string  `json:"name"`
+		Description string  `json:"description,omitempty"`
+		InputSchema *Schema `json:"inputSchema"`
+	}
+	callToolParams struct {
+		Name      string                     `json:"name"`
+		Arguments map[string]json.RawMessage `json:"arguments"`
+	}
+	promptArg struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		Required    bool   `json:"required,omitempty"`
+	}
+	promptInfo struct {
+		Name        string      `json:"name"`
+		Description string      `json:"description,omitempty"`
+		Arguments   []promptArg `json:"arguments,omitempty"`
+	}
+	getPromptParams struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	getPromptResult struct {
+		Description string    `json:"description,omitempty"`
+		Messages    []Message `json:"messages"`
+	}
+	resourceInfo struct {
+		URI         string `json:"uri"`
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+		MimeType    string `json:"mimeType,omitempty"`
+	}
+	readResourceParams struct {
+		URI string `json:"uri"`
+	}
+	resourceContents struct {
+		URI      string `json:"uri"`
+		MimeType string `json:"mimeType,omitempty"`
+		Text     string `json:"text,omitempty"`
+		Blob     string `json:"blob,omitempty"`
+	}
+	readResourceResult struct {
+		Contents []resourceContents `json:"contents"`
+	}
+	none = struct{}
+)
+
+func (p *Game) newMux() *jsonrpc2.Mux {
+	mux := jsonrpc2.NewMux()
+	mux.Use(jsonrpc2.Recover)
+	jsonrpc2.RegisterFunc(mux, "initialize", p.initialize)
+	jsonrpc2.RegisterNotify(mux, "notifications/initialized", func(ctx context.Context, _ none) error {
+		return nil
+	})
+	jsonrpc2.RegisterFunc(mux, "ping", func(ctx context.Context, _ none) (none, error) {
+		return none{}, nil
+	})
+	jsonrpc2.RegisterFunc(mux, "tools/list", p.listTools)
+	jsonrpc2.RegisterFunc(mux, "tools/call", p.callTool)
+	jsonrpc2.RegisterFunc(mux, "prompts/list", p.listPrompts)
+	jsonrpc2.RegisterFunc(mux, "prompts/get", p.getPrompt)
+	jsonrpc2.RegisterFunc(mux, "resources/list", p.listResources)
+	jsonrpc2.RegisterFunc(mux, "resources/read", p.readResource)
+	return mux
+}
+
+func (p *Game) initialize(ctx context.Context, params initializeParams) (*initializeResult, error) {
+	version := ProtocolVersion
+	for _, v := range protocolVersions {
+		if v == params.ProtocolVersion {
+			version = v
+			break
+		}
+	}
+	caps := make(map[string]any)
+	if len(p.tools) > 0 {
+		caps["tools"] = none{}
+	}
+	if len(p.prompts) > 0 {
+		caps["prompts"] = none{}
+	}
+	if len(p.resources) > 0 {
+		caps["resources"] = none{}
+	}
+	return &initializeResult{
+		ProtocolVersion: version,
+		Capabilities:    caps,
+		ServerInfo:      implementation{Name: p.name, Version: p.version},
+		Instructions:    p.instructions,
+	}, nil
+}
+
+func (p *Game) listTools(ctx context.Context, _ none) (ret struct {
+	Tools []toolInfo `json:"tools"`
+}, err error) {
+	ret.Tools = make([]toolInfo, len(p.tools))
+	for i, t := range p.tools {
+		ret.Tools[i] = toolInfo{Name: t.name, Description: t.desc, InputSchema: inputSchemaOf(t.args)}
+	}
+	return
+}
+
+func (p *Game) callTool(ctx context.Context, params callToolParams) (*ToolResult, error) {
+	for _, t := range p.tools {
+		if t.name == params.Name {
+			if err := setArgs(t.obj, t.args, params.Arguments); err != nil {
+				return nil, fmt.Errorf("%w: tool %s: %v", jsonrpc2.ErrInvalidParams, t.name, err)
+			}
+			return t.call(), nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown tool %q", jsonrpc2.ErrInvalidParams, params.Name)
+}
+
+func (p *tool) call() (ret *ToolResult) {
+	defer func() {
+		if e := recover(); e != nil {
+			ret = errorResult(fmt.Errorf("panic: %v", e))
+		}
+	}()
+	switch v := p.proto.Main(p.name).(type) {
+	case nil:
+		return &ToolResult{Content: []Content{}}
+	case *ToolResult:
+		return v
+	case string:
+		return &ToolResult{Content: []Content{TextContent(v)}}
+	case Content:
+		return &ToolResult{Content: []Content{v}}
+	case []Content:
+		return &ToolResult{Content: v}
+	case error:
+		return errorResult(v)
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return errorResult(err)
+		}
+		return &ToolResult{Content: []Content{TextContent(string(data))}}
+	}
+}
+
+func errorResult(err error) *ToolResult {
+	return &ToolResult{Content: []Content{TextContent(err.Error())}, IsError: true}
+}
+
+func (p *Game) listPrompts(ctx context.Context, _ none) (ret struct {
+	Prompts []promptInfo `json:"prompts"`
+}, err error) {
+	ret.Prompts = make([]promptInfo, len(p.prompts))
+	for i, t := range p.prompts {
+		info := promptInfo{Name: t.name, Description: t.desc}
+		for _, arg := range t.args {
+			info.Arguments = append(info.Arguments, promptArg{Name: arg.name, Description: arg.desc, Required: arg.required})
+		}
+		ret.Prompts[i] = info
+	}
+	return
+}
+
+func (p *Game) getPrompt(ctx context.Context, params getPromptParams) (*getPromptResult, error) {
+	for _, t := range p.prompts {
+		if t.name == params.Name {
+			// arguments of prompts are strings, so convert them to JSON values
+			args := make(map[string]json.RawMessage, len(params.Arguments))
+			for _, arg := range t.args {
+				v, ok := params.Arguments[arg.name]
+				if !ok {
+					continue
+				}
+				if arg.typ.Kind() == reflect.String {
+					args[arg.name], _ = json.Marshal(v)
+				} else {
+					args[arg.name] = json.RawMessage(v)
+				}
+			}
+			if err := setArgs(t.obj, t.args, args); err != nil {
+				return nil, fmt.Errorf("%w: prompt %s: %v", jsonrpc2.ErrInvalidParams, t.name, err)
+			}
+			msgs, err := t.get()
+			if err != nil {
+				return nil, err
+			}
+			return &getPromptResult{Description: t.desc, Messages: msgs}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown prompt %q", jsonrpc2.ErrInvalidParams, params.Name)
+}
+
+func (p *prompt) get() ([]Message, error) {
+	switch v := p.proto.Main(p.name).(type) {
+	case string:
+		return []Message{{Role: "user", Content: TextContent(v)}}, nil
+	case Message:
+		return []Message{v}, nil
+	case []Message:
+		return v, nil
+	case error:
+		return nil, v
+	default:
+		return nil, fmt.Errorf("prompt %s: unsupported result type %T", p.name, v)
+	}
+}
+
+func (p *Game) listResources(ctx context.Context, _ none) (ret struct {
+	Resources []resourceInfo `json:"resources"`
+}, err error) {
+	ret.Resources = make([]resourceInfo, len(p.resources))
+	for i, t := range p.resources {
+		ret.Resources[i] = resourceInfo{URI: t.uri, Name: t.name, Description: t.desc, MimeType: t.mimeType}
+	}
+	return
+}
+
+// ErrResourceNotFound is returned when reading a resource which doesn't exist.
+var ErrResourceNotFound = jsonrpc2.NewError(-32002, "Resource not found")
+
+func (p *Game) readResource(ctx context.Context, params readResourceParams) (*readResourceResult, error) {
+	for _, t := range p.resources {
+		if t.uri == params.URI {
+			ret := resourceContents{URI: t.uri, MimeType: t.mimeType}
+			switch v := t.proto.Main(t.uri).(type) {
+			case string:
+				ret.Text = v
+			case []byte:
+				ret.Blob = base64.StdEncoding.EncodeToString(v)
+			case error:
+				return nil, v
+			default:
+				return nil, fmt.Errorf("resource %s: unsupported result type %T", t.name, v)
+			}
+			return &readResourceResult{Contents: []resourceContents{ret}}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, params.URI)
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package parallel implements XGo go expressions, which run a list of calls
+// concurrently and wait for all of them:
+//
+//	results, err := go [fetch(u) for u in urls]
+//	err := go [ctx => save(ctx, doc) for doc in docs]
+//
+// A go expression is compiled into a call of Run, Wait, Map or Do, depending
+// on the results of its calls. The argument of the call creates the tasks,
+// given the context of the group of tasks. The context is canceled when a
+// task fails or panics.
+package parallel
+
+import (
+	"context"
+	"sync"
+)
+
+// -----------------------------------------------------------------------------
+
+type group struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	err    error
+	panic  any
+	failed bool
+}
+
+func newGroup() *group {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	return &group{ctx: ctx, cancel: cancel}
+}
+
+func (g *group) fail(err error, panicVal any) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.failed {
+		g.failed, g.err, g.panic = true, err, panicVal
+		if err == nil {
+			err = context.Canceled
+		}
+		g.cancel(err)
+	}
+}
+
+func (g *group) run(n int, task func(i int) error) error {
+	g.wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer g.wg.Done()
+			defer func() {
+				if e := recover(); e != nil {
+					g.fail(nil, e)
+				}
+			}()
+			if err := task(i); err != nil {
+				g.fail(err, nil)
+			}
+		}()
+	}
+	g.wg.Wait()
+	g.cancel(nil)
+	if g.panic != nil {
+		panic(g.panic)
+	}
+	return g.err
+}
+
+// Run runs tasks concurrently and waits for them. It returns the results of
+// the tasks in order, or the first error returned by a task.
+func Run[T any](tasks func(ctx context.Context) []func() (T, error)) ([]T, error) {
+	g := newGroup()
+	fns := tasks(g.ctx)
+	ret := make([]T, len(fns))
+	err := g.run(len(fns), func(i int) (err error) {
+		ret[i], err = fns[i]()
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Wait runs tasks concurrently and waits for them. It returns the first error
+// returned by a task.
+func Wait(tasks func(ctx context.Context) []func() error) error {
+	g := newGroup()
+	fns := tasks(g.ctx)
+	return g.run(len(fns), func(i int) error {
+		return fns[i]()
+	})
+}
+
+// Map runs tasks concurrently and waits for them. It returns the results of
+// the tasks in order.
+func Map[T any](tasks func(ctx context.Context) []func() T) []T {
+	g := newGroup()
+	fns := tasks(g.ctx)
+	ret := make([]T, len(fns))
+	g.run(len(fns), func(i int) error {
+		ret[i] = fns[i]()
+		return nil
+	})
+	return ret
+}
+
+// Do runs tasks concurrently and waits for them.
+func Do(tasks func(ctx context.Context) []func()) {
+	g := newGroup()
+	fns := tasks(g.ctx)
+	g.run(len(fns), func(i int) error {
+		fns[i]()
+		return nil
+	})
+}
+
+// -----------------------------------------------------------------------------
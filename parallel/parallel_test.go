@@ -0,0 +1,97 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parallel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	ret, err := Run(func(ctx context.Context) (tasks []func() (string, error)) {
+		for i := range 3 {
+			tasks = append(tasks, func() (string, error) { return fmt.Sprint(i), nil })
+		}
+		return
+	})
+	if err != nil || fmt.Sprint(ret) != "[0 1 2]" {
+		t.Fatal("Run:", ret, err)
+	}
+}
+
+func TestRunCancel(t *testing.T) {
+	errFail := errors.New("fail")
+	ret, err := Run(func(ctx context.Context) []func() (int, error) {
+		return []func() (int, error){
+			func() (int, error) { return 0, errFail },
+			func() (int, error) {
+				<-ctx.Done()
+				if context.Cause(ctx) != errFail {
+					t.Error("cause:", context.Cause(ctx))
+				}
+				return 0, ctx.Err()
+			},
+		}
+	})
+	if err != errFail || ret != nil {
+		t.Fatal("Run:", ret, err)
+	}
+}
+
+func TestWait(t *testing.T) {
+	var n atomic.Int32
+	err := Wait(func(ctx context.Context) (tasks []func() error) {
+		for range 5 {
+			tasks = append(tasks, func() error { n.Add(1); return nil })
+		}
+		return
+	})
+	if err != nil || n.Load() != 5 {
+		t.Fatal("Wait:", n.Load(), err)
+	}
+}
+
+func TestMapDo(t *testing.T) {
+	ret := Map(func(ctx context.Context) []func() int {
+		return []func() int{func() int { return 1 }, func() int { return 2 }}
+	})
+	if fmt.Sprint(ret) != "[1 2]" {
+		t.Fatal("Map:", ret)
+	}
+	var n atomic.Int32
+	Do(func(ctx context.Context) []func() {
+		return []func(){func() { n.Add(1) }, func() { n.Add(2) }}
+	})
+	if n.Load() != 3 {
+		t.Fatal("Do:", n.Load())
+	}
+	Do(func(ctx context.Context) []func() { return nil })
+}
+
+func TestPanic(t *testing.T) {
+	defer func() {
+		if e := recover(); e != "boom" {
+			t.Fatal("recover:", e)
+		}
+	}()
+	Do(func(ctx context.Context) []func() {
+		return []func(){func() { panic("boom") }, func() { <-ctx.Done() }}
+	})
+}
@@ -52,6 +52,10 @@ const (
 	ParseXGoClass Mode = 1 << 17
 	// SaveAbsFile - parse and save absolute path to pkg.Files
 	SaveAbsFile Mode = 1 << 18
+	// ErrorRecovery - never stop at syntax errors: report all of them and
+	// insert ast.Bad* nodes to always yield a complete AST (for tools like
+	// editors and linters)
+	ErrorRecovery Mode = 1 << 19
 
 	// Deprecated: use ParseGoAsXGo instead.
 	ParseGoAsGoPlus = ParseGoAsXGo
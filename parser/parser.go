@@ -174,7 +174,9 @@ func (p *parser) declare(decl, data any, scope *ast.Scope, kind ast.ObjKind, ide
 		obj.Decl = decl
 		obj.Data = data
 		ident.Obj = obj
-		if ident.Name != "_" {
+		// scope is nil for a label out of function bodies, eg. in a lambda of
+		// a tpl literal.
+		if ident.Name != "_" && scope != nil {
 			if alt := scope.Insert(obj); alt != nil && p.mode&DeclarationErrors != 0 {
 				prevDecl := ""
 				if pos := alt.Pos(); pos.IsValid() {
@@ -190,28 +192,45 @@ func (p *parser) shortVarDecl(decl *ast.AssignStmt, list []ast.Expr) {
 	// Go spec: A short variable declaration may redeclare variables
 	// provided they were originally declared in the same block with
 	// the same type, and at least one of the non-blank variables is new.
-	n := 0 // number of new variables
+	n := p.declareShortVars(decl, list, len(list) == 1)
+	if n == 0 && p.mode&DeclarationErrors != 0 {
+		p.error(list[0].Pos(), "no new variables on left side of :=")
+	}
+}
+
+// declareShortVars declares variables of a short variable declaration and
+// returns the number of new variables. XGo: if allowTuple is set, a tuple
+// pattern (eg. `(a, b) := pt`) is allowed to destructure a tuple.
+func (p *parser) declareShortVars(decl *ast.AssignStmt, list []ast.Expr, allowTuple bool) (n int) {
 	for _, x := range list {
-		if ident, isIdent := x.(*ast.Ident); isIdent {
-			assert(ident.Obj == nil, "identifier already declared or resolved")
-			obj := ast.NewObj(ast.Var, ident.Name)
+		switch v := x.(type) {
+		case *ast.Ident:
+			assert(v.Obj == nil, "identifier already declared or resolved")
+			obj := ast.NewObj(ast.Var, v.Name)
 			// remember corresponding assignment for other tools
 			obj.Decl = decl
-			ident.Obj = obj
-			if ident.Name != "_" {
+			v.Obj = obj
+			if v.Name != "_" {
 				if alt := p.topScope.Insert(obj); alt != nil {
-					ident.Obj = alt // redeclaration
+					v.Obj = alt // redeclaration
 				} else {
 					n++ // new declaration
 				}
 			}
-		} else {
+		default:
+			if t, ok := x.(*ast.TupleLit); ok && allowTuple {
+				for _, elt := range t.Elts {
+					if ident, ok := elt.(*ast.Ident); ok {
+						ident.Obj = nil // resolved as an operand of the tuple literal
+					}
+				}
+				n += p.declareShortVars(decl, t.Elts, true)
+				continue
+			}
 			p.errorExpected(x.Pos(), "identifier on left side of :=", 2)
 		}
 	}
-	if n == 0 && p.mode&DeclarationErrors != 0 {
-		p.error(list[0].Pos(), "no new variables on left side of :=")
-	}
+	return
 }
 
 // The unresolved object is a sentinel to mark identifiers that have been added
@@ -414,13 +433,13 @@ func (p *parser) error(pos token.Pos, msg string) {
 
 	// If AllErrors is not set, discard errors reported on the same line
 	// as the last recorded error and stop parsing if there are more than
-	// 10 errors.
+	// 10 errors (unless ErrorRecovery is set).
 	if p.mode&AllErrors == 0 {
 		n := len(p.errors)
 		if n > 0 && p.errors[n-1].Pos.Line == epos.Line {
 			return // discard - likely a spurious error
 		}
-		if n > 10 {
+		if n > 10 && p.mode&ErrorRecovery == 0 {
 			panic(bailout{})
 		}
 	}
@@ -508,7 +527,11 @@ func (p *parser) expectSemi() {
 			p.next()
 		default:
 			p.errorExpected(p.pos, "';'", 3)
-			p.advance(stmtStart)
+			if p.mode&ErrorRecovery != 0 {
+				p.advanceStmt()
+			} else {
+				p.advance(stmtStart)
+			}
 		}
 	}
 }
@@ -568,6 +591,23 @@ func (p *parser) advance(to map[token.Token]bool) {
 	}
 }
 
+// advanceStmt consumes tokens until the end of the current statement (usually
+// an automatically inserted semicolon at the end of line) or before a '}' or
+// a keyword that starts a statement. Unlike advance(stmtStart), it doesn't skip
+// the following statements starting with an identifier, such as command-style
+// calls.
+func (p *parser) advanceStmt() {
+	for ; p.tok != token.EOF; p.next() {
+		if p.tok == token.SEMICOLON {
+			p.next()
+			return
+		}
+		if p.tok == token.RBRACE || stmtStart[p.tok] {
+			return
+		}
+	}
+}
+
 var stmtStart = map[token.Token]bool{
 	token.BREAK:       true,
 	token.CONST:       true,
@@ -904,7 +944,8 @@ func (p *parser) parseArrayTypeOrSliceLit(state int, slice ast.Expr) (expr ast.E
 		elt = p.tryType()
 		if elt == nil {
 			if len == nil {
-				log.Panicln("TODO: expect slice index")
+				p.errorExpected(rbrack, "slice index", 2)
+				len = &ast.BadExpr{From: lbrack + 1, To: rbrack}
 			}
 			if debugParseOutput {
 				log.Printf("ast.IndexExpr{X: %v, Index: %v}\n", slice, len)
@@ -1940,6 +1981,9 @@ func (p *parser) domainTextLitEx(off, end token.Pos) *ast.DomainTextLitEx {
 		sp.next()
 	}
 	sp.expect(token.SEMICOLON)
+	if p.mode&ErrorRecovery != 0 {
+		p.errors = append(p.errors, sp.errors...)
+	}
 	return &ast.DomainTextLitEx{
 		Args:   args,
 		RawPos: sp.pos,
@@ -2006,8 +2050,11 @@ func (p *parser) parseOperand(flags int) (x ast.Expr, exprKind int) {
 
 	case token.STRING, token.CSTRING, token.PYSTRING, token.INT, token.FLOAT, token.IMAG, token.CHAR, token.RAT:
 		bl := &ast.BasicLit{ValuePos: p.pos, Kind: p.tok, Value: p.lit}
-		if p.tok == token.STRING && len(p.lit) > 1 {
-			bl.Extra = p.stringLit(p.pos, p.lit)
+		switch p.tok {
+		case token.STRING, token.CSTRING, token.PYSTRING:
+			if len(p.lit) > 1 {
+				bl.Extra = p.stringLit(bl.End()-token.Pos(len(p.lit)), p.lit)
+			}
 		}
 		p.next()
 		if p.tok == token.UNIT {
@@ -2092,6 +2139,9 @@ func (p *parser) parseOperand(flags int) (x ast.Expr, exprKind int) {
 
 	case token.ENV:
 		return p.parseEnvExpr(), 0
+
+	case token.GO: // XGo: go [call1, call2, ...]
+		return p.parseGoExpr(), 0
 	}
 
 	typ, result := p.tryIdentOrType(stateArrayTypeOrSliceLit, nil)
@@ -2108,7 +2158,11 @@ func (p *parser) parseOperand(flags int) (x ast.Expr, exprKind int) {
 	// we have an error
 	pos := p.pos
 	p.errorExpected(pos, "operand", 2)
-	p.advance(stmtStart)
+	if p.mode&ErrorRecovery == 0 {
+		p.advance(stmtStart)
+	} else if !exprEnd[p.tok] { // keep the token that ends the expression
+		p.next()
+	}
 	return &ast.BadExpr{From: pos, To: p.pos}, 0
 }
 
@@ -2278,7 +2332,12 @@ func (p *parser) parseCallOrConversion(fun ast.Expr, isCmd bool) *ast.CallExpr {
 	} else {
 		lparen, endTok = p.expect(token.LPAREN), token.RPAREN
 	}
-	p.exprLev++
+	// XGo: a command-style call in a control clause (eg. `match x {...}`)
+	// ends before '{'
+	inCtrl := isCmd && p.exprLev < 0
+	if !inCtrl {
+		p.exprLev++
+	}
 	var args []ast.Expr
 	var kwargs []*ast.KwargExpr
 	var ellipsis token.Pos
@@ -2297,7 +2356,7 @@ func (p *parser) parseCallOrConversion(fun ast.Expr, isCmd bool) *ast.CallExpr {
 				p.next()
 			}
 		}
-		if isCmd && p.tok == token.RBRACE {
+		if isCmd && (p.tok == token.RBRACE || inCtrl && p.tok == token.LBRACE) {
 			break
 		}
 		if !p.atComma("argument list", endTok) {
@@ -2305,7 +2364,9 @@ func (p *parser) parseCallOrConversion(fun ast.Expr, isCmd bool) *ast.CallExpr {
 		}
 		p.next()
 	}
-	p.exprLev--
+	if !inCtrl {
+		p.exprLev--
+	}
 	var noParenEnd token.Pos
 	if isCmd {
 		noParenEnd = p.pos
@@ -2414,11 +2475,13 @@ func (p *parser) parseElementListOrComprehension() (list []ast.Expr, mce *ast.Co
 	for p.tok != token.RBRACE && p.tok != token.EOF {
 		list = append(list, p.parseElement())
 		if p.tok == token.FOR { // for k, v <- container
-			if len(list) != 1 {
-				log.Panicln("TODO: invalid comprehension: too may elements.")
+			elt := list[0]
+			if n := len(list); n != 1 {
+				p.error(list[1].Pos(), "invalid comprehension: too many elements")
+				elt = &ast.BadExpr{From: elt.Pos(), To: list[n-1].End()}
 			}
 			phrases := p.parseForPhrases()
-			return nil, &ast.ComprehensionExpr{Elt: list[0], Fors: phrases}
+			return nil, &ast.ComprehensionExpr{Elt: elt, Fors: phrases}
 		}
 		if !p.atComma("composite literal", token.RBRACE) {
 			break
@@ -2469,6 +2532,7 @@ func (p *parser) checkExpr(x ast.Expr) ast.Expr {
 	case *ast.FuncLit:
 	case *ast.CompositeLit:
 	case *ast.SliceLit:
+	case *ast.MatrixLit:
 	case *ast.ComprehensionExpr:
 	case *ast.SelectorExpr:
 	case *ast.AnySelectorExpr:
@@ -2493,6 +2557,8 @@ func (p *parser) checkExpr(x ast.Expr) ast.Expr {
 	case *ast.BinaryExpr:
 	case *ast.RangeExpr:
 	case *ast.ErrWrapExpr:
+	case *ast.OptChainExpr:
+	case *ast.GoExpr:
 	case *ast.LambdaExpr:
 	case *ast.LambdaExpr2:
 	case *ast.TupleLit:
@@ -2714,6 +2780,22 @@ L:
 		case token.QUESTION: // ?
 			x = &ast.ErrWrapExpr{X: x, Tok: p.tok, TokPos: p.pos}
 			p.next()
+		case token.OPTCHAIN: // ?.
+			if lhs {
+				p.resolve(x)
+			}
+			x = &ast.OptChainExpr{X: p.checkExpr(x), Quest: p.pos}
+			p.next()
+			switch p.tok {
+			case token.IDENT:
+				x = p.parseSelector(x)
+			case token.LPAREN:
+				x = p.parseTypeAssertion(x)
+			default:
+				pos := p.pos
+				p.errorExpected(pos, "selector or type assertion after '?.'", 2)
+				x = &ast.SelectorExpr{X: x, Sel: &ast.Ident{NamePos: pos, Name: "_"}}
+			}
 		case token.ASSIGN: // =
 			if flags&flagAllowKwargExpr != 0 {
 				if name, ok := x.(*ast.Ident); ok { // name=expr
@@ -2756,7 +2838,7 @@ func (p *parser) checkCmd() bool {
 	case token.IDENT, token.DRARROW,
 		token.STRING, token.CSTRING, token.PYSTRING,
 		token.INT, token.FLOAT, token.IMAG, token.CHAR, token.RAT,
-		token.FUNC, token.GOTO, token.TYPE, token.MAP, token.INTERFACE,
+		token.FUNC, token.GO, token.GOTO, token.TYPE, token.MAP, token.INTERFACE,
 		token.CHAN, token.STRUCT, token.ENV:
 		return true
 	case token.SUB, token.AND, token.MUL, token.ARROW, token.XOR, token.ADD:
@@ -2953,6 +3035,15 @@ func (p *parser) parseLambdaExpr(flags int) (x ast.Expr, exprKind int) {
 		case token.LBRACE: // {
 			body = p.parseBlockStmt()
 		default:
+			if p.mode&ErrorRecovery != 0 && p.file.Line(p.pos) > p.file.Line(rarrow) {
+				// the body is missing: don't take the next line as the body,
+				// but end the statement at the newline
+				end := rarrow + 2
+				p.errorExpected(end, "lambda body", 2)
+				rhs = []ast.Expr{&ast.BadExpr{From: end, To: end}}
+				p.unget(p.pos, token.SEMICOLON, "\n")
+				break
+			}
 			rhs = []ast.Expr{p.parseExpr(0)}
 		}
 		var lhs []*ast.Ident
@@ -3216,6 +3307,25 @@ func (p *parser) parseGoStmt() ast.Stmt {
 	return &ast.GoStmt{Go: pos, Call: call}
 }
 
+// go [call1, call2, ...]
+// go [call for k, v in container if cond]
+func (p *parser) parseGoExpr() ast.Expr {
+	if p.trace {
+		defer un(trace(p, "GoExpr"))
+	}
+
+	pos := p.expect(token.GO)
+	if p.tok != token.LBRACK {
+		p.errorExpected(p.pos, "'['", 2)
+		return &ast.BadExpr{From: pos, To: p.pos}
+	}
+	x, _ := p.parseOperand(0)
+	if debugParseOutput {
+		log.Printf("ast.GoExpr{X: %v}\n", x)
+	}
+	return &ast.GoExpr{Go: pos, X: x}
+}
+
 func (p *parser) parseDeferStmt() ast.Stmt {
 	if p.trace {
 		defer un(trace(p, "DeferStmt"))
@@ -3572,6 +3682,88 @@ func (p *parser) parseSwitchStmt() ast.Stmt {
 	return &ast.SwitchStmt{Switch: pos, Init: s1, Tag: p.makeExpr(s2, "switch expression"), Body: body}
 }
 
+// tryMatchStmt parses a match statement. XGo: match isn't a keyword, so it
+// returns nil and restores the parser state if match isn't followed by an
+// expression (eg. `match := 1`). A command-style call `match x` is also
+// parsed as a simple statement if x isn't followed by '{'.
+func (p *parser) tryMatchStmt(flags int) ast.Stmt {
+	if p.trace {
+		defer un(trace(p, "MatchStmt"))
+	}
+
+	pos, lit := p.pos, p.lit
+	p.next()
+	switch p.tok {
+	case token.IDENT, token.INT, token.FLOAT, token.IMAG, token.RAT, token.CHAR,
+		token.STRING, token.CSTRING, token.PYSTRING, token.LPAREN:
+	default:
+		p.unget(pos, token.IDENT, lit)
+		return nil
+	}
+	p.unget(pos, token.IDENT, lit)
+
+	prevLev := p.exprLev
+	p.exprLev = -1
+	s := p.parseSimpleStmt(basic, flags)
+	p.exprLev = prevLev
+
+	x := matchSubject(s)
+	if x == nil || p.tok != token.LBRACE {
+		p.expectSemi()
+		return s
+	}
+	lbrace := p.expect(token.LBRACE)
+	var list []ast.Stmt
+	for p.tok == token.CASE || p.tok == token.DEFAULT {
+		list = append(list, p.parseMatchClause())
+	}
+	rbrace := p.expect(token.RBRACE)
+	p.expectSemi()
+	body := &ast.BlockStmt{Lbrace: lbrace, List: list, Rbrace: rbrace}
+	return &ast.MatchStmt{Match: pos, X: x, Body: body}
+}
+
+// matchSubject returns x if s is `match x` or `match(x)`.
+func matchSubject(s ast.Stmt) ast.Expr {
+	if es, ok := s.(*ast.ExprStmt); ok {
+		if call, ok := es.X.(*ast.CallExpr); ok && len(call.Args) == 1 && call.Ellipsis == token.NoPos {
+			if fn, ok := call.Fun.(*ast.Ident); ok && fn.Name == "match" {
+				return call.Args[0]
+			}
+		}
+	}
+	return nil
+}
+
+func (p *parser) parseMatchClause() *ast.MatchClause {
+	if p.trace {
+		defer un(trace(p, "MatchClause"))
+	}
+
+	pos := p.pos
+	var list []ast.Expr
+	var ifPos token.Pos
+	var guard ast.Expr
+	if p.tok == token.CASE {
+		p.next()
+		list = p.parseRHSList()
+		if p.tok == token.IF {
+			ifPos = p.pos
+			p.next()
+			guard = p.parseRHS()
+		}
+	} else {
+		p.expect(token.DEFAULT)
+	}
+
+	colon := p.expect(token.COLON)
+	p.openScope()
+	body := p.parseStmtList()
+	p.closeScope()
+
+	return &ast.MatchClause{Case: pos, List: list, If: ifPos, Guard: guard, Colon: colon, Body: body}
+}
+
 func (p *parser) parseCommClause() *ast.CommClause {
 	if p.trace {
 		defer un(trace(p, "CommClause"))
@@ -3678,9 +3870,10 @@ func (p *parser) parseForPhraseStmtPart(lhs []ast.Expr) *ast.ForPhraseStmt {
 	}
 	switch len(lhs) {
 	case 1:
-		stmt.Value = p.toIdent(lhs[0])
+		stmt.Value, stmt.Tuple = p.toIdentOrTuple(lhs[0])
 	case 2:
-		stmt.Key, stmt.Value = p.toIdent(lhs[0]), p.toIdent(lhs[1])
+		stmt.Key = p.toIdent(lhs[0])
+		stmt.Value, stmt.Tuple = p.toIdentOrTuple(lhs[1])
 	default:
 		p.errorExpected(lhs[0].Pos(), "expect 1 or 2 identifiers", 2)
 	}
@@ -3701,6 +3894,59 @@ func (p *parser) toIdent(e ast.Expr) *ast.Ident {
 	return nil
 }
 
+// toIdentOrTuple converts e into an identifier or a tuple pattern of
+// identifiers (eg. `(a, b)` in `for (a, b) in container`).
+func (p *parser) toIdentOrTuple(e ast.Expr) (*ast.Ident, *ast.TupleLit) {
+	if t, ok := e.(*ast.TupleLit); ok {
+		p.checkTuplePattern(t)
+		return nil, t
+	}
+	return p.toIdent(e), nil
+}
+
+func (p *parser) checkTuplePattern(t *ast.TupleLit) {
+	if t.Ellipsis != token.NoPos {
+		p.error(t.Ellipsis, "unexpected ... in tuple pattern")
+	}
+	for _, elt := range t.Elts {
+		if v, ok := elt.(*ast.TupleLit); ok {
+			p.checkTuplePattern(v)
+		} else {
+			p.toIdent(elt)
+		}
+	}
+}
+
+// parseTuplePattern parses a tuple pattern of identifiers, eg. `(a, (b, c))`.
+func (p *parser) parseTuplePattern() *ast.TupleLit {
+	if p.trace {
+		defer un(trace(p, "TuplePattern"))
+	}
+
+	lparen := p.expect(token.LPAREN)
+	var elts []ast.Expr
+	for p.tok != token.RPAREN && p.tok != token.EOF {
+		if p.tok == token.LPAREN {
+			elts = append(elts, p.parseTuplePattern())
+		} else {
+			elts = append(elts, p.parseIdent())
+		}
+		if !p.atComma("tuple pattern", token.RPAREN) {
+			break
+		}
+		p.next()
+	}
+	rparen := p.expectClosing(token.RPAREN, "tuple pattern")
+	return &ast.TupleLit{Lparen: lparen, Elts: elts, Rparen: rparen}
+}
+
+func (p *parser) parseForPhraseVar() (*ast.Ident, *ast.TupleLit) {
+	if p.tok == token.LPAREN {
+		return nil, p.parseTuplePattern()
+	}
+	return p.parseIdent(), nil
+}
+
 func (p *parser) parseForPhrase() *ast.ForPhrase { // for k, v in container if cond
 	if p.trace {
 		defer un(trace(p, "ForPhrase"))
@@ -3711,10 +3957,14 @@ func (p *parser) parseForPhrase() *ast.ForPhrase { // for k, v in container if c
 	defer p.closeScope()
 
 	var k, v *ast.Ident
-	v = p.parseIdent()
+	v, tuple := p.parseForPhraseVar()
 	if p.tok == token.COMMA { // k, v
 		p.next()
-		k, v = v, p.parseIdent()
+		if tuple != nil {
+			p.errorExpected(tuple.Pos(), "'IDENT'", 2)
+		}
+		k = v
+		v, tuple = p.parseForPhraseVar()
 	}
 
 	tokPos := p.expectIn() // in container
@@ -3727,7 +3977,7 @@ func (p *parser) parseForPhrase() *ast.ForPhrase { // for k, v in container if c
 		p.next()
 		init, cond = p.parseForPhraseCond()
 	}
-	return &ast.ForPhrase{For: pos, Key: k, Value: v, TokPos: tokPos, X: x, IfPos: ifPos, Init: init, Cond: cond}
+	return &ast.ForPhrase{For: pos, Key: k, Value: v, Tuple: tuple, TokPos: tokPos, X: x, IfPos: ifPos, Init: init, Cond: cond}
 }
 
 func (p *parser) parseForStmt() ast.Stmt {
@@ -3845,6 +4095,11 @@ func (p *parser) parseStmt(flags int) (s ast.Stmt) {
 		flags = 0
 		fallthrough
 	case token.IDENT, token.MAP: // operands
+		if p.tok == token.IDENT && p.lit == "match" {
+			if s = p.tryMatchStmt(flags); s != nil {
+				break
+			}
+		}
 		s = p.parseSimpleStmt(labelOk, flags)
 		// because of the required look-ahead, labeled statements are
 		// parsed by parseSimpleStmt - don't expect a semicolon after
@@ -3853,6 +4108,15 @@ func (p *parser) parseStmt(flags int) (s ast.Stmt) {
 			p.expectSemi()
 		}
 	case token.GO:
+		oldpos, oldlit := p.pos, p.lit // XGo: go [call1, call2, ...] is an expression
+		p.next()
+		tok := p.tok
+		p.unget(oldpos, token.GO, oldlit)
+		if tok == token.LBRACK {
+			s = p.parseSimpleStmt(basic, 0)
+			p.expectSemi()
+			break
+		}
 		s = p.parseGoStmt()
 	case token.DEFER:
 		s = p.parseDeferStmt()
@@ -4335,6 +4599,11 @@ func (p *parser) parseDecl(sync map[token.Token]bool) ast.Decl {
 	}
 	var f parseSpecFunction
 	pos := p.pos
+	if p.tok == token.IDENT && p.lit == "enum" { // XGo: enum declaration
+		if decl := p.tryEnumDecl(); decl != nil {
+			return decl
+		}
+	}
 	switch p.tok {
 	case token.CONST, token.VAR:
 		f = p.parseValueSpec
@@ -4343,7 +4612,7 @@ func (p *parser) parseDecl(sync map[token.Token]bool) ast.Decl {
 	case token.FUNC:
 		decl, call := p.parseFuncDeclOrCall()
 		if decl != nil {
-			if p.errors.Len() != 0 {
+			if p.errors.Len() != 0 && p.mode&ErrorRecovery == 0 {
 				p.advance(sync)
 			}
 			return decl
@@ -4355,11 +4624,75 @@ func (p *parser) parseDecl(sync map[token.Token]bool) ast.Decl {
 	return p.parseGenDecl(p.tok, f)
 }
 
+// tryEnumDecl parses an enum declaration if the "enum" identifier is followed
+// by an identifier. Otherwise it returns nil without consuming any token.
+func (p *parser) tryEnumDecl() *ast.EnumDecl {
+	if p.trace {
+		defer un(trace(p, "EnumDecl"))
+	}
+	doc := p.leadComment
+	pos, lit := p.pos, p.lit
+	p.next()
+	if p.tok != token.IDENT {
+		p.unget(pos, token.IDENT, lit)
+		return nil
+	}
+	name := p.parseIdent()
+	lbrace := p.expect(token.LBRACE)
+	var list []*ast.EnumVariant
+	for p.tok == token.IDENT {
+		list = append(list, p.parseEnumVariant())
+	}
+	rbrace := p.expect(token.RBRACE)
+	p.expectSemi()
+	decl := &ast.EnumDecl{
+		Doc: doc, Enum: pos, Name: name, Lbrace: lbrace, Variants: list, Rbrace: rbrace,
+	}
+	p.declare(decl, nil, p.topScope, ast.Typ, name)
+	kind := ast.Con
+	if decl.IsSumType() {
+		kind = ast.Typ
+	}
+	for _, v := range list {
+		p.declare(v, nil, p.topScope, kind, v.Name)
+	}
+	return decl
+}
+
+func (p *parser) parseEnumVariant() *ast.EnumVariant {
+	if p.trace {
+		defer un(trace(p, "EnumVariant"))
+	}
+	doc := p.leadComment
+	name := p.parseIdent()
+	var fields *ast.FieldList
+	if p.tok == token.LPAREN {
+		lparen := p.pos
+		p.next()
+		list := p.parseTupleFieldList()
+		rparen := p.expect(token.RPAREN)
+		fields = &ast.FieldList{Opening: lparen, List: list, Closing: rparen}
+	}
+	p.expectSemi()
+	return &ast.EnumVariant{Doc: doc, Name: name, Fields: fields, Comment: p.lineComment}
+}
+
 func (p *parser) parseGlobalStmts(sync map[token.Token]bool, pos token.Pos, stmts ...ast.Stmt) *ast.FuncDecl {
 	p.topScope = ast.NewScope(p.topScope)
 	doc := p.leadComment
 	p.openLabelScope()
 	list := p.parseStmtList()
+	if p.mode&ErrorRecovery != 0 {
+		// skip the unexpected token (a stray '}', case or default) that
+		// ends the statement list and go on parsing the rest statements
+		for p.tok != token.EOF {
+			from := p.pos
+			p.errorExpected(from, "statement", 2)
+			p.next()
+			list = append(list, &ast.BadStmt{From: from, To: p.pos})
+			list = append(list, p.parseStmtList()...)
+		}
+	}
 	p.closeLabelScope()
 	p.closeScope()
 	if stmts != nil {
@@ -4406,7 +4739,7 @@ func (p *parser) parseFile() *ast.File {
 
 	// Don't bother parsing the rest if we had errors scanning the first token.
 	// Likely not a Go source file at all.
-	if p.errors.Len() != 0 {
+	if p.errors.Len() != 0 && p.mode&ErrorRecovery == 0 {
 		return nil
 	}
 
@@ -4427,7 +4760,7 @@ func (p *parser) parseFile() *ast.File {
 
 		// Don't bother parsing the rest if we had errors parsing the package clause.
 		// Likely not a Go source file at all.
-		if p.errors.Len() != 0 {
+		if p.errors.Len() != 0 && p.mode&ErrorRecovery == 0 {
 			return nil
 		}
 	} else {
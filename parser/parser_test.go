@@ -18,6 +18,8 @@ package parser
 
 import (
 	"io/fs"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/goplus/xgo/ast"
@@ -452,3 +454,93 @@ onStart => {
 }
 
 // -----------------------------------------------------------------------------
+
+func testErrorRecovery(t *testing.T, code string, errExp string, stmts ...string) {
+	t.Helper()
+	testErrorRecoveryFile(t, "/foo/bar.xgo", code, errExp, stmts...)
+}
+
+// testErrorRecoveryFile checks the error of parsing code in ErrorRecovery
+// mode and the statements that survive the recovery, each of which is in the
+// form "<type>: <source code>".
+func testErrorRecoveryFile(t *testing.T, filename, code string, errExp string, stmts ...string) {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := ParseFile(fset, filename, code, ErrorRecovery)
+	if err == nil || err.Error() != errExp {
+		t.Fatal("testErrorRecovery error:", err)
+	}
+	if f.ShadowEntry == nil {
+		t.Fatal("testErrorRecovery: no shadow entry")
+	}
+	var ret []string
+	for _, stmt := range f.ShadowEntry.Body.List {
+		typ := reflect.TypeOf(stmt).Elem().Name()
+		from, to := fset.Position(stmt.Pos()).Offset, fset.Position(stmt.End()).Offset
+		ret = append(ret, strings.TrimSpace(typ+": "+code[from:to]))
+	}
+	if !reflect.DeepEqual(ret, stmts) {
+		t.Fatalf("testErrorRecovery: got statements\n%s\nexpected\n%s", strings.Join(ret, "\n"), strings.Join(stmts, "\n"))
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	testErrorRecovery(t, `x := [v for v in ]
+echo 1
+echo 2
+`, `/foo/bar.xgo:1:18: expected operand, found ']'`,
+		"AssignStmt: x := [v for v in ]", "ExprStmt: echo 1", "ExprStmt: echo 2")
+	testErrorRecovery(t, `x := {a, b for v in c}
+echo x
+`, `/foo/bar.xgo:1:10: invalid comprehension: too many elements`,
+		"AssignStmt: x := {a, b for v in c}", "ExprStmt: echo x")
+	testErrorRecovery(t, `x := 1
+y := (a, b) =>
+echo x
+echo y
+`, `/foo/bar.xgo:2:15: expected lambda body`,
+		"AssignStmt: x := 1", "AssignStmt: y := (a, b) =>", "ExprStmt: echo x", "ExprStmt: echo y")
+	testErrorRecovery(t, `func f() {
+	echo 1 +
+}
+echo 2
+}
+echo 3
+`, `/foo/bar.xgo:3:1: expected operand, found '}' (and 1 more errors)`,
+		"ExprStmt: echo 2", "BadStmt: }", "EmptyStmt:", "ExprStmt: echo 3")
+}
+
+func TestErrorRecoveryDomainText(t *testing.T) {
+	testErrorRecovery(t, "cl := tpl`expr = (INT`\necho cl\n",
+		`/foo/bar.xgo:1:22: expected ')', found newline (and 1 more errors)`,
+		"AssignStmt: cl := tpl`expr = (INT`", "ExprStmt: echo cl")
+	testErrorRecovery(t, "x := 1\ny := json`> x +`\necho y\n",
+		`/foo/bar.xgo:2:16: expected operand, found 'EOF'`,
+		"AssignStmt: x := 1", "AssignStmt: y := json`> x +`", "ExprStmt: echo y")
+	const labelInTpl = "cl := tpl`file = stmts => {\n\treturn &ast.Fgoile\n\t\tStmts: this.([]ast.Stmt),\n\t}\n}\n`!\necho cl\n"
+	if _, err := ParseFile(token.NewFileSet(), "/foo/bar.xgo", labelInTpl, 0); err == nil {
+		t.Fatal("ParseFile: no error")
+	}
+	testErrorRecovery(t, labelInTpl,
+		`/foo/bar.xgo:3:10: expected 1 expression (and 3 more errors)`,
+		"AssignStmt: cl := tpl`file = stmts => {\n\treturn &ast.Fgoile\n\t\tStmts: this.([]ast.Stmt),\n\t}\n}\n`!", "ExprStmt: echo cl")
+}
+
+func TestErrorRecoveryClassfile(t *testing.T) {
+	testErrorRecoveryFile(t, "/foo/Rect.gox", `var (
+	Width float64
+)
+
+onStart => {
+	echo Width +
+}
+onMsg "hi", =>
+echo "done"
+`, `/foo/Rect.gox:7:1: expected operand, found '}' (and 1 more errors)`,
+		"ExprStmt: onStart => {\n\techo Width +\n}", "ExprStmt: onMsg \"hi\", =>", `ExprStmt: echo "done"`)
+}
+
+func TestErrComprehensionTooManyElts(t *testing.T) {
+	testErrCode(t, `x := {a, b for v in c}
+`, `/foo/bar.xgo:1:10: invalid comprehension: too many elements`, ``)
+}
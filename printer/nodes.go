@@ -1111,6 +1111,12 @@ func (p *printer) expr1(expr ast.Expr, prec1, depth int) {
 			p.print(token.COLON)
 			p.expr(x.Default)
 		}
+	case *ast.OptChainExpr:
+		p.expr(x.X)
+		p.print(x.Quest, token.QUESTION)
+	case *ast.GoExpr:
+		p.print(token.GO, blank)
+		p.expr(x.X)
 	case *ast.LambdaExpr:
 		if x.LhsHasParen {
 			p.print(token.LPAREN)
@@ -1181,7 +1187,9 @@ func (p *printer) expr1(expr ast.Expr, prec1, depth int) {
 }
 
 var (
-	in = &ast.Ident{Name: "in"}
+	in    = &ast.Ident{Name: "in"}
+	match = &ast.Ident{Name: "match"}
+	enum  = &ast.Ident{Name: "enum"}
 )
 
 func (p *printer) listForPhrase(list []*ast.ForPhrase) {
@@ -1194,7 +1202,12 @@ func (p *printer) listForPhrase(list []*ast.ForPhrase) {
 			p.expr(x.Key)
 			p.print(token.COMMA, blank)
 		}
-		p.print(x.Value, blank)
+		if x.Tuple != nil {
+			p.expr(x.Tuple)
+			p.print(blank)
+		} else {
+			p.print(x.Value, blank)
+		}
 		p.print(x.TokPos, in, blank)
 		p.expr(x.X)
 		if x.Cond != nil {
@@ -1562,6 +1575,30 @@ func (p *printer) stmt(stmt ast.Stmt, nextIsRBrace bool) {
 		p.controlClause(false, s.Init, s.Tag, nil)
 		p.block(s.Body, 0)
 
+	case *ast.MatchClause:
+		if s.List != nil {
+			p.print(token.CASE, blank)
+			end := s.Colon
+			if s.Guard != nil {
+				end = s.If
+			}
+			p.exprList(s.Pos(), s.List, 1, 0, end, false)
+			if s.Guard != nil {
+				p.print(blank, s.If, token.IF, blank)
+				p.expr(s.Guard)
+			}
+		} else {
+			p.print(token.DEFAULT)
+		}
+		p.print(s.Colon, token.COLON)
+		p.stmtList(s.Body, 1, nextIsRBrace)
+
+	case *ast.MatchStmt:
+		p.print(s.Match, match, blank)
+		p.expr(stripParens(s.X))
+		p.print(blank)
+		p.block(s.Body, 0)
+
 	case *ast.TypeSwitchStmt:
 		p.print(token.SWITCH)
 		if s.Init != nil {
@@ -1623,7 +1660,11 @@ func (p *printer) stmt(stmt ast.Stmt, nextIsRBrace bool) {
 			p.expr(s.Key)
 			p.print(token.COMMA, blank)
 		}
-		p.expr(s.Value)
+		if s.Tuple != nil {
+			p.expr(s.Tuple)
+		} else {
+			p.expr(s.Value)
+		}
 		p.print(blank, s.TokPos, in, blank)
 		p.expr(s.X)
 		if s.Cond != nil {
@@ -2131,6 +2172,34 @@ func (p *printer) overloadFuncDecl(d *ast.OverloadFuncDecl) {
 	p.print(token.RPAREN)
 }
 
+func (p *printer) enumDecl(d *ast.EnumDecl) {
+	if debugFormat {
+		log.Println("==> Format Enum", d.Name.Name)
+	}
+	p.setComment(d.Doc)
+	p.print(d.Pos(), enum, blank)
+	p.expr(d.Name)
+	if len(d.Variants) == 0 && !p.commentBefore(p.posFor(d.Rbrace)) {
+		p.print(blank, d.Lbrace, token.LBRACE, d.Rbrace, token.RBRACE)
+		return
+	}
+	p.print(blank, d.Lbrace, token.LBRACE, indent, formfeed)
+	var line int
+	for i, v := range d.Variants {
+		if i > 0 {
+			p.linebreak(p.lineFor(v.Pos()), 1, ignore, p.linesFrom(line) > 0)
+		}
+		p.setComment(v.Doc)
+		p.recordLine(&line)
+		p.expr(v.Name)
+		if v.Fields != nil {
+			p.parameters(v.Fields)
+		}
+		p.setComment(v.Comment)
+	}
+	p.print(unindent, formfeed, d.Rbrace, token.RBRACE)
+}
+
 func (p *printer) decl(decl ast.Decl) {
 	switch d := decl.(type) {
 	case *ast.BadDecl:
@@ -2141,6 +2210,8 @@ func (p *printer) decl(decl ast.Decl) {
 		p.funcDecl(d)
 	case *ast.OverloadFuncDecl:
 		p.overloadFuncDecl(d)
+	case *ast.EnumDecl:
+		p.enumDecl(d)
 	default:
 		panic("unreachable")
 	}
@@ -1025,8 +1025,17 @@ scanAgain:
 		case '|':
 			tok = s.switch3(token.OR, token.OR_ASSIGN, '|', token.LOR)
 		case '?':
-			tok = token.QUESTION
-			insertSemi = true
+			switch s.ch {
+			case '.': // ?.
+				s.next()
+				tok = token.OPTCHAIN
+			case '?': // ??
+				s.next()
+				tok = token.COALESCE
+			default:
+				tok = token.QUESTION
+				insertSemi = true
+			}
 		case '$':
 			tok = token.ENV
 		case '~':
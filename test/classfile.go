@@ -17,7 +17,12 @@
 package test
 
 import (
+	"bytes"
+	"encoding/json"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -48,6 +53,81 @@ func (p Case) Run(name string, f func(t *testing.T)) bool {
 	return p.t.Run(name, f)
 }
 
+// Subtest runs f as a subtest of the case called name. T returns the subtest
+// while f is running, so that f can use the case with command syntax:
+//
+//	subtest "sub", => {
+//		t.log "in the subtest"
+//	}
+//
+// Don't call Parallel of the subtest in f.
+func (p *Case) Subtest(name string, f func()) bool {
+	return p.t.Run(name, func(t *testing.T) {
+		old := p.t
+		p.t = t
+		defer func() { p.t = old }()
+		f()
+	})
+}
+
+// Golden compares got with the golden file testdata/<name>.golden and reports
+// a failure if they differ. A string or []byte is compared as is, and other
+// values are compared in indented JSON. If the environment variable
+// XGO_UPDATE_GOLDEN is set, Golden updates the golden file with got instead.
+func (p Case) Golden(name string, got any) {
+	t := p.t
+	t.Helper()
+	var data []byte
+	switch v := got.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			t.Fatalf("Golden %s: %v", name, err)
+		}
+		data = append(b, '\n')
+	}
+	file := filepath.Join("testdata", name+".golden")
+	if os.Getenv("XGO_UPDATE_GOLDEN") != "" {
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatalf("Golden %s: %v", name, err)
+		}
+		if err := os.WriteFile(file, data, 0644); err != nil {
+			t.Fatalf("Golden %s: %v", name, err)
+		}
+		return
+	}
+	expected, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Golden %s: %v (set XGO_UPDATE_GOLDEN=1 to create it)", name, err)
+	}
+	if !bytes.Equal(data, expected) {
+		t.Errorf("Golden %s: mismatch\n==> got:\n%s\n==> expected:\n%s", name, data, expected)
+	}
+}
+
+type caseRunner interface {
+	Subtest(name string, f func()) bool
+}
+
+// Gopt_Case_Table runs f as a subtest of the case for each entry of cases in
+// the order of their names. T returns the subtest while f is running:
+//
+//	table {"two": 2, "ten": 10}, v => {
+//		if foo(v) != v*2 {
+//			t.fatal "foo(${v}) != ${v*2}"
+//		}
+//	}
+func Gopt_Case_Table[T any](p caseRunner, cases map[string]T, f func(c T)) {
+	for _, name := range slices.Sorted(maps.Keys(cases)) {
+		c := cases[name]
+		p.Subtest(name, func() { f(c) })
+	}
+}
+
 // Gopt_Case_TestMain is required by XGo compiler as the test case entry.
 func Gopt_Case_TestMain(c interface{ initCase(t *testing.T) }, t *testing.T) {
 	c.initCase(t)
@@ -56,6 +136,52 @@ func Gopt_Case_TestMain(c interface{ initCase(t *testing.T) }, t *testing.T) {
 
 // -----------------------------------------------------------------------------
 
+// Bench represents an XGo benchmark.
+type Bench struct {
+	b *testing.B
+}
+
+func (p *Bench) initBench(b *testing.B) {
+	p.b = b
+}
+
+// B returns the *testing.B object.
+func (p Bench) B() *testing.B { return p.b }
+
+// Run benchmarks f as a subbenchmark with the given name. It reports whether
+// there were any failures.
+func (p Bench) Run(name string, f func(b *testing.B)) bool {
+	return p.b.Run(name, f)
+}
+
+// Gopt_Bench_TestMain is required by XGo compiler as the benchmark entry.
+func Gopt_Bench_TestMain(c interface{ initBench(b *testing.B) }, b *testing.B) {
+	c.initBench(b)
+	c.(interface{ Main() }).Main()
+}
+
+// -----------------------------------------------------------------------------
+
+// Fuzz represents an XGo fuzz test.
+type Fuzz struct {
+	f *testing.F
+}
+
+func (p *Fuzz) initFuzz(f *testing.F) {
+	p.f = f
+}
+
+// F returns the *testing.F object.
+func (p Fuzz) F() *testing.F { return p.f }
+
+// Gopt_Fuzz_TestMain is required by XGo compiler as the fuzz test entry.
+func Gopt_Fuzz_TestMain(c interface{ initFuzz(f *testing.F) }, f *testing.F) {
+	c.initFuzz(f)
+	c.(interface{ Main() }).Main()
+}
+
+// -----------------------------------------------------------------------------
+
 // App represents an XGo testing main application.
 type App struct {
 	m *testing.M
@@ -0,0 +1,100 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newCase(t *testing.T) *Case {
+	p := new(Case)
+	p.initCase(t)
+	return p
+}
+
+func TestCaseRun(t *testing.T) {
+	p := newCase(t)
+	var sub string
+	if !p.Run("sub0", func(t *testing.T) { sub = t.Name() }) {
+		t.Fatal("Run: failed")
+	}
+	if sub != t.Name()+"/sub0" {
+		t.Fatal("Run:", sub)
+	}
+	ok := p.Subtest("sub1", func() {
+		sub = p.T().Name()
+		p.Subtest("nested", func() { sub = p.T().Name() })
+	})
+	if !ok || sub != t.Name()+"/sub1/nested" {
+		t.Fatal("Subtest:", ok, sub)
+	}
+	if p.T() != t {
+		t.Fatal("Subtest: T isn't restored")
+	}
+}
+
+func TestCaseTable(t *testing.T) {
+	p := newCase(t)
+	var names []string
+	var vals []int
+	Gopt_Case_Table(p, map[string]int{"ten": 10, "two": 2, "five": 5}, func(v int) {
+		names = append(names, p.T().Name())
+		vals = append(vals, v)
+	})
+	prefix := t.Name() + "/"
+	if !reflect.DeepEqual(names, []string{prefix + "five", prefix + "ten", prefix + "two"}) {
+		t.Fatal("Gopt_Case_Table: names", names)
+	}
+	if !reflect.DeepEqual(vals, []int{5, 10, 2}) {
+		t.Fatal("Gopt_Case_Table: values", vals)
+	}
+}
+
+func TestCaseGolden(t *testing.T) {
+	t.Chdir(t.TempDir())
+	p := newCase(t)
+	type result struct {
+		Name string
+		N    int
+	}
+	t.Setenv("XGO_UPDATE_GOLDEN", "1")
+	p.Golden("str", "hello\n")
+	p.Golden("sub/bytes", []byte("world"))
+	p.Golden("json", result{"foo", 3})
+
+	for name, expected := range map[string]string{
+		"str":       "hello\n",
+		"sub/bytes": "world",
+		"json":      "{\n  \"Name\": \"foo\",\n  \"N\": 3\n}\n",
+	} {
+		b, err := os.ReadFile(filepath.Join("testdata", name+".golden"))
+		if err != nil {
+			t.Fatal("Golden:", err)
+		}
+		if string(b) != expected {
+			t.Fatalf("Golden %s: %q, expected %q", name, b, expected)
+		}
+	}
+
+	t.Setenv("XGO_UPDATE_GOLDEN", "")
+	p.Golden("str", "hello\n")
+	p.Golden("sub/bytes", "world")
+	p.Golden("json", &result{"foo", 3})
+}
@@ -143,7 +143,9 @@ const (
 	additional_op1
 	additional_op2
 	additional_op3
-	additional_end = additional_op3
+	additional_op4
+	additional_op5
+	additional_end = additional_op5
 
 	additional_literal_beg = 96
 	additional_literal_end = 97
@@ -151,6 +153,9 @@ const (
 	AT  = additional_op2 // @
 	ENV = additional_op3 // ${name}
 
+	OPTCHAIN = additional_op4 // ?. (optional chaining)
+	COALESCE = additional_op5 // ?? (nil-coalescing)
+
 	PYSTRING = additional_literal_beg // py"Hello"
 	UNIT     = additional_literal_end // 1m, 2.3s, 3ms, 4us, 5ns, 6.5m, 7h, 8d, 9w, 10y
 
@@ -245,6 +250,8 @@ var tokens = [...]string{
 	ENV:       "$",
 	TILDE:     "~",
 	AT:        "@",
+	OPTCHAIN:  "?.",
+	COALESCE:  "??",
 
 	BREAK:    "break",
 	CASE:     "case",
@@ -309,7 +316,7 @@ const (
 // is LowestPrecedence.
 func (op Token) Precedence() int {
 	switch op {
-	case LOR:
+	case LOR, COALESCE:
 		return 1
 	case LAND:
 		return 2
@@ -40,7 +40,13 @@ func TestArrowOp(t *testing.T) {
 	if v := AT.String(); v != "@" {
 		t.Fatal("AT.String:", v)
 	}
-	if v := (additional_end + 100).String(); v != "token(191)" {
+	if v := OPTCHAIN.String(); v != "?." || !OPTCHAIN.IsOperator() {
+		t.Fatal("OPTCHAIN.String:", v)
+	}
+	if COALESCE.Precedence() != LOR.Precedence() {
+		t.Fatal("COALESCE.Precedence")
+	}
+	if v := (additional_end + 100).String(); v != "token(193)" {
 		t.Fatal("token.String:", v)
 	}
 }
@@ -0,0 +1,536 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"go/constant"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goplus/gogen"
+	"github.com/goplus/mod/modfile"
+)
+
+// -----------------------------------------------------------------------------
+
+// ErrNoClassFramework is returned by CheckClasses if the module doesn't
+// register any class framework.
+var ErrNoClassFramework = errors.New("no class framework registered in gox.mod")
+
+// ClassDiag represents a diagnostic of checking a class framework.
+type ClassDiag struct {
+	Pos     string // position in gox.mod or in the framework package, maybe empty
+	Msg     string
+	Warning bool
+}
+
+func (p *ClassDiag) String() string {
+	var b strings.Builder
+	if p.Pos != "" {
+		b.WriteString(p.Pos)
+		b.WriteString(": ")
+	}
+	if p.Warning {
+		b.WriteString("warning: ")
+	}
+	b.WriteString(p.Msg)
+	return b.String()
+}
+
+// WorkClass represents a work class of a class framework.
+type WorkClass struct {
+	*modfile.Class
+	Features []string // Classfname and Classclone if its prototype requires them
+}
+
+// ClassFramework represents a class framework checked by CheckClass.
+type ClassFramework struct {
+	*modfile.Project
+	Main  string // entry of the project class, eg. Gopt_Game_Main
+	Works []*WorkClass
+	Diags []*ClassDiag
+}
+
+// Errors returns the number of errors (not warnings) found.
+func (p *ClassFramework) Errors() (n int) {
+	for _, diag := range p.Diags {
+		if !diag.Warning {
+			n++
+		}
+	}
+	return
+}
+
+// CheckClasses checks class frameworks of the specified classfile extensions
+// (eg. _yap.gox), or all class frameworks registered by gox.mod of conf.Mod if
+// exts is empty.
+func CheckClasses(exts []string, conf *Config) (ret []*ClassFramework, err error) {
+	var projs []*modfile.Project
+	if len(exts) == 0 {
+		if projs = conf.Mod.Projects(); len(projs) == 0 {
+			return nil, ErrNoClassFramework
+		}
+	} else {
+		for _, ext := range exts {
+			proj, ok := conf.Mod.LookupClass(ext)
+			if !ok {
+				return nil, fmt.Errorf("class framework of %s not found", ext)
+			}
+			if !hasProject(projs, proj) {
+				projs = append(projs, proj)
+			}
+		}
+	}
+	for _, proj := range projs {
+		ret = append(ret, CheckClass(proj, conf))
+	}
+	return
+}
+
+func hasProject(projs []*modfile.Project, proj *modfile.Project) bool {
+	for _, v := range projs {
+		if v == proj {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckClass loads the class framework proj and checks if it follows the
+// conventions required by the XGo compiler, eg. the signature of the
+// Gopt_<Class>_Main function of the project class and the prototypes of the
+// work classes.
+func CheckClass(proj *modfile.Project, conf *Config) *ClassFramework {
+	ret := &ClassFramework{Project: proj}
+	for _, w := range proj.Works {
+		ret.Works = append(ret.Works, &WorkClass{Class: w})
+	}
+	p := &classChecker{ClassFramework: ret, conf: conf, modfile: "gox.mod"}
+	if hasProject(conf.Mod.Projects(), proj) {
+		if syn := conf.Mod.Opt.Syntax; syn != nil && syn.Name != "" {
+			p.modfile = relPath(syn.Name)
+		}
+	}
+	p.check()
+	return ret
+}
+
+func relPath(file string) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, file); err == nil && !strings.HasPrefix(rel, "..") {
+			return rel
+		}
+	}
+	return file
+}
+
+type classChecker struct {
+	*ClassFramework
+	conf    *Config
+	modfile string
+	pkgs    []*types.Package
+}
+
+func (p *classChecker) diag(pos string, warning bool, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	p.Diags = append(p.Diags, &ClassDiag{Pos: pos, Msg: msg, Warning: warning})
+}
+
+func (p *classChecker) errorf(pos string, format string, args ...any) {
+	p.diag(pos, false, format, args...)
+}
+
+func (p *classChecker) warnf(pos string, format string, args ...any) {
+	p.diag(pos, true, format, args...)
+}
+
+func (p *classChecker) linePos(line *modfile.Line) string {
+	if line == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", p.modfile, line.Start.Line)
+}
+
+func (p *classChecker) objPos(o types.Object) string {
+	if pos := o.Pos(); pos.IsValid() {
+		return relPath(p.conf.Fset.Position(pos).String())
+	}
+	return ""
+}
+
+func (p *classChecker) check() {
+	proj := p.Project
+	pos := p.linePos(proj.Syntax)
+	if len(proj.PkgPaths) == 0 {
+		p.errorf(pos, "no package of class framework %s", proj.Ext)
+		return
+	}
+	for _, pkgPath := range proj.PkgPaths {
+		pkg, err := p.conf.Importer.Import(pkgPath)
+		if err != nil {
+			p.errorf(pos, "cannot import %s: %v", pkgPath, err)
+			return
+		}
+		p.pkgs = append(p.pkgs, pkg)
+	}
+	for _, imp := range proj.Import {
+		if _, err := p.conf.Importer.Import(imp.Path); err != nil {
+			p.errorf(p.linePos(imp.Syntax), "cannot import %s: %v", imp.Path, err)
+		}
+	}
+	pkg := p.pkgs[0]
+	scope := pkg.Scope()
+	if scope.Lookup("XGoPackage") == nil && scope.Lookup("GopPackage") == nil {
+		for _, name := range scope.Names() {
+			if strings.HasPrefix(name, "Gopt_") || strings.HasPrefix(name, "XGot_") {
+				p.errorf(p.objPos(scope.Lookup(name)),
+					"%s is ignored since %s isn't an XGo package: declare `const XGoPackage = true`", name, pkg.Path())
+				break
+			}
+		}
+	}
+
+	var game *types.TypeName
+	if proj.Class != "" {
+		game = p.lookupClass(proj.Class, pos)
+	}
+	works := make([]*types.TypeName, len(p.Works))
+	for i, w := range p.Works {
+		wpos := p.linePos(w.Syntax)
+		works[i] = p.lookupClass(w.Class.Class, wpos)
+		if len(p.Works) > 1 && w.Proto == "" {
+			p.errorf(wpos, "work class %s should have a prototype since there are multiple work classes", w.Class.Class)
+		}
+		if w.Proto != "" {
+			if o := scope.Lookup(w.Proto); o == nil {
+				p.errorf(wpos, "prototype %s.%s not found", pkg.Name(), w.Proto)
+			} else if !types.IsInterface(o.Type()) {
+				p.errorf(p.objPos(o), "prototype %s of work class %s isn't an interface", w.Proto, w.Class.Class)
+			}
+		}
+		if w.Embedded && proj.Class == "" {
+			p.errorf(wpos, "work class %s can't be embedded since there is no project class", w.Class.Class)
+		}
+	}
+	if game == nil {
+		return
+	}
+	if strings.HasSuffix(proj.Ext, "test.gox") {
+		p.checkTestMain(game, "M")
+		for _, work := range works {
+			if work != nil {
+				p.checkTestMain(work, "T")
+			}
+		}
+		for _, kind := range [...]struct{ class, param string }{{"Bench", "B"}, {"Fuzz", "F"}} {
+			if o, ok := scope.Lookup(kind.class).(*types.TypeName); ok {
+				p.checkTestMain(o, kind.param)
+			}
+		}
+	} else {
+		p.checkMain(game, works)
+	}
+	p.checkSched(scope)
+}
+
+// lookupClass looks up a project or work base class, eg. Game or *Game.
+func (p *classChecker) lookupClass(class, pos string) *types.TypeName {
+	pkg := p.pkgs[0]
+	name := strings.TrimPrefix(class, "*")
+	o := pkg.Scope().Lookup(name)
+	if o == nil {
+		p.errorf(pos, "class %s.%s not found", pkg.Name(), name)
+		return nil
+	}
+	tn, ok := o.(*types.TypeName)
+	if !ok {
+		p.errorf(p.objPos(o), "class %s isn't a type", name)
+		return nil
+	}
+	if _, ok := tn.Type().(*types.Named); !ok {
+		p.errorf(p.objPos(o), "class %s isn't a named type", name)
+		return nil
+	}
+	return tn
+}
+
+// checkMain checks the entry of the project class game, which is either a
+// Main method or a Gopt_<Game>_Main function:
+//
+//	func (p *Game) Main(sprites ...Sprite)
+//	func Gopt_Game_Main(game interface{ initGame() }, sprites []SpriteProto, ...)
+func (p *classChecker) checkMain(game *types.TypeName, works []*types.TypeName) {
+	var sig *types.Signature
+	var params []*types.Var
+	var entry types.Object
+	if m := findMethodOf(game.Type(), "Main"); m != nil {
+		entry, sig = m, m.Type().(*types.Signature)
+		p.Main = "(*" + game.Name() + ").Main"
+		if _, ok := sig.Recv().Type().(*types.Pointer); !ok {
+			p.errorf(p.objPos(m), "Main of project class %s should have a pointer receiver", game.Name())
+			return
+		}
+		for i, n := 0, sig.Params().Len(); i < n; i++ {
+			params = append(params, sig.Params().At(i))
+		}
+	} else {
+		fn, name := lookupTemplate(game, "Main")
+		if fn == nil {
+			p.errorf(p.objPos(game), "project class %s has neither a Main method nor a %s function", game.Name(), name)
+			return
+		}
+		entry, sig = fn, fn.Type().(*types.Signature)
+		p.Main = name
+		in := sig.Params()
+		if in.Len() == 0 {
+			p.errorf(p.objPos(fn), "%s should have the project class as its first parameter", name)
+			return
+		}
+		p.checkImplements(fn, game, in.At(0).Type(), name+": the first parameter")
+		for i, n := 1, in.Len(); i < n; i++ {
+			params = append(params, in.At(i))
+		}
+	}
+
+	pos := p.objPos(entry)
+	switch {
+	case len(params) == 0:
+		if len(p.Works) > 0 {
+			p.warnf(pos, "work classes aren't passed to %s, so they are never instantiated", p.Main)
+		}
+	case len(p.Works) == 0:
+		p.errorf(pos, "%s has parameters of work classes, but no work class is registered", p.Main)
+	case len(p.Works) == 1 && p.Works[0].Proto == "": // single work class without prototype
+		if len(params) != 1 || !sig.Variadic() {
+			p.errorf(pos, "%s should have a single variadic parameter of work classes, eg. ...%s", p.Main, p.Works[0].Class.Class)
+			return
+		}
+		elt := params[0].Type().(*types.Slice).Elem()
+		p.checkProto(p.Works[0], works[0], elt, pos)
+	default:
+		if sig.Variadic() {
+			p.errorf(pos, "%s shouldn't be variadic since work classes have prototypes", p.Main)
+			return
+		}
+		passed := make([]bool, len(p.Works))
+		for _, param := range params {
+			tslice, ok := param.Type().(*types.Slice)
+			if !ok {
+				p.errorf(pos, "parameter %s of %s should be a slice of a work class prototype", param.Name(), p.Main)
+				continue
+			}
+			tn, ok := tslice.Elem().(*types.Named)
+			if !ok {
+				p.errorf(pos, "parameter %s of %s should be a slice of a work class prototype", param.Name(), p.Main)
+				continue
+			}
+			i := workByProto(p.Works, tn.Obj().Name())
+			if i < 0 {
+				p.errorf(pos, "parameter %s of %s: %s isn't the prototype of any work class", param.Name(), p.Main, tn.Obj().Name())
+				continue
+			}
+			passed[i] = true
+			p.checkProto(p.Works[i], works[i], tn, pos)
+		}
+		for i, w := range p.Works {
+			if !passed[i] && w.Proto != "" {
+				p.warnf(p.linePos(w.Syntax), "work class %s isn't passed to %s, so it is never instantiated", w.Class.Class, p.Main)
+			}
+		}
+	}
+}
+
+func workByProto(works []*WorkClass, proto string) int {
+	for i, w := range works {
+		if w.Proto == proto {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkProto checks if a work class can implement its prototype proto, and
+// collects features of the work class required by proto. An exported method
+// of proto that the work base class doesn't have is only a warning since it
+// can be defined in classfiles.
+func (p *classChecker) checkProto(w *WorkClass, work *types.TypeName, proto types.Type, pos string) {
+	intf, ok := proto.Underlying().(*types.Interface)
+	if !ok {
+		return
+	}
+	protoName := types.TypeString(proto, types.RelativeTo(p.pkgs[0]))
+	w.Features = w.Features[:0]
+	for i, n := 0, intf.NumMethods(); i < n; i++ {
+		m := intf.Method(i)
+		sig := m.Type().(*types.Signature)
+		switch m.Name() {
+		case "Classfname": // generated by XGo compiler
+			if sig.Params().Len() != 0 || sig.Results().Len() != 1 || !types.Identical(sig.Results().At(0).Type(), types.Typ[types.String]) {
+				p.errorf(p.objPos(m), "Classfname of %s should be `Classfname() string`", protoName)
+			}
+			w.Features = append(w.Features, "Classfname")
+			continue
+		case "Classclone": // generated by XGo compiler
+			if sig.Params().Len() != 0 || sig.Results().Len() != 1 {
+				p.errorf(p.objPos(m), "Classclone of %s should have no parameters and a single result", protoName)
+			}
+			w.Features = append(w.Features, "Classclone")
+			continue
+		case "Main": // generated by XGo compiler
+			if work == nil {
+				continue
+			}
+			want := types.NewSignatureType(nil, nil, nil, nil, nil, false)
+			if base := findMethodOf(work.Type(), "Main"); base != nil {
+				bsig := base.Type().(*types.Signature)
+				want = types.NewSignatureType(nil, nil, nil, bsig.Params(), bsig.Results(), bsig.Variadic())
+			}
+			if !types.Identical(sig, want) {
+				p.errorf(p.objPos(m), "Main of %s is %v, but Main of work class %s will be %v", protoName, sig, w.Class.Class, want)
+			}
+			continue
+		}
+		if work == nil {
+			continue
+		}
+		o, _, _ := types.LookupFieldOrMethod(types.NewPointer(work.Type()), true, m.Pkg(), m.Name())
+		fn, ok := o.(*types.Func)
+		if !ok {
+			if m.Exported() { // can be defined in classfiles
+				p.warnf(pos, "work class %s doesn't have method %s of %s, so it must be defined in each %s classfile",
+					w.Class.Class, m.Name(), protoName, w.Ext)
+			} else {
+				p.errorf(pos, "work class %s doesn't implement %s: missing unexported method %s", w.Class.Class, protoName, m.Name())
+			}
+			continue
+		}
+		got := fn.Type().(*types.Signature)
+		got = types.NewSignatureType(nil, nil, nil, got.Params(), got.Results(), got.Variadic())
+		if !types.Identical(got, sig) {
+			p.errorf(p.objPos(fn), "work class %s doesn't implement %s: wrong type for method %s, have %v, want %v",
+				w.Class.Class, protoName, m.Name(), got, sig)
+		}
+	}
+}
+
+// checkImplements checks if the pointer to class implements typ, which should
+// be an interface.
+func (p *classChecker) checkImplements(at types.Object, class *types.TypeName, typ types.Type, what string) {
+	intf, ok := typ.Underlying().(*types.Interface)
+	if !ok {
+		p.errorf(p.objPos(at), "%s should be an interface implemented by *%s", what, class.Name())
+		return
+	}
+	ptr := types.NewPointer(class.Type())
+	if m, wrongType := types.MissingMethod(ptr, intf, true); m != nil {
+		if wrongType {
+			p.errorf(p.objPos(at), "%s: *%s has wrong type for method %s", what, class.Name(), m.Name())
+		} else {
+			p.errorf(p.objPos(at), "%s: *%s doesn't have method %s", what, class.Name(), m.Name())
+		}
+	}
+}
+
+// checkTestMain checks the entry of a test class:
+//
+//	func Gopt_Case_TestMain(c interface{ initCase(t *testing.T) }, t *testing.T)
+func (p *classChecker) checkTestMain(class *types.TypeName, param string) {
+	fn, name := lookupTemplate(class, "TestMain")
+	if fn == nil {
+		p.errorf(p.objPos(class), "test class %s has no %s function", class.Name(), name)
+		return
+	}
+	if class.Name() == p.Project.Class {
+		p.Main = name
+	}
+	in := fn.Type().(*types.Signature).Params()
+	want := "*testing." + param
+	if in.Len() != 2 || in.At(1).Type().String() != want {
+		p.errorf(p.objPos(fn), "%s should have two parameters: the test class and %s", name, want)
+		return
+	}
+	p.checkImplements(fn, class, in.At(0).Type(), name+": the first parameter")
+}
+
+// checkSched checks the Gop_sched constant, which lists one or two functions
+// called by the compiler to schedule goroutines, eg. "Sched,SchedNow".
+func (p *classChecker) checkSched(scope *types.Scope) {
+	o := scope.Lookup("Gop_sched")
+	if o == nil {
+		return
+	}
+	c, ok := o.(*types.Const)
+	if !ok || c.Val().Kind() != constant.String {
+		p.errorf(p.objPos(o), "Gop_sched should be a string constant")
+		return
+	}
+	names := strings.Split(constant.StringVal(c.Val()), ",")
+	if len(names) > 2 {
+		p.errorf(p.objPos(o), "Gop_sched should list one or two functions, but got %d", len(names))
+	}
+	for _, name := range names {
+		fn := p.lookupFunc(name)
+		if fn == nil {
+			p.errorf(p.objPos(o), "Gop_sched: function %s not found", name)
+		} else if fn.Type().(*types.Signature).Params().Len() != 0 {
+			p.errorf(p.objPos(fn), "Gop_sched: function %s should have no parameters", name)
+		}
+	}
+}
+
+func (p *classChecker) lookupFunc(name string) *types.Func {
+	for _, pkg := range p.pkgs {
+		if fn, ok := pkg.Scope().Lookup(name).(*types.Func); ok {
+			return fn
+		}
+	}
+	return nil
+}
+
+// lookupTemplate looks up the template method XGot_<Class>_<Method> (or
+// Gopt_<Class>_<Method>) of class, and returns its name.
+func lookupTemplate(class *types.TypeName, method string) (*types.Func, string) {
+	scope := class.Pkg().Scope()
+	for _, prefix := range [...]string{"XGot_", "Gopt_"} {
+		name := prefix + class.Name() + "_" + method
+		if fn, ok := scope.Lookup(name).(*types.Func); ok {
+			return fn, name
+		}
+	}
+	return nil, "Gopt_" + class.Name() + "_" + method
+}
+
+// findMethodOf finds a method declared by typ, skipping overload and template
+// methods added by gogen.
+func findMethodOf(typ types.Type, name string) *types.Func {
+	if t, ok := typ.(*types.Named); ok {
+		for i, n := 0, t.NumMethods(); i < n; i++ {
+			if m := t.Method(i); m.Name() == name {
+				if _, ok := gogen.CheckSigFuncEx(m.Type().(*types.Signature)); !ok {
+					return m
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
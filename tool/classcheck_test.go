@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"reflect"
+	"testing"
+)
+
+const classGoxMod = `xgo 1.5
+
+project _app.gox App example.com/foo/fw
+class _spr.gox Sprite SpriteProto
+`
+
+const classFramework = `package fw
+
+const XGoPackage = true
+
+type App struct{}
+
+func (p *App) initApp() {}
+
+type Sprite struct{}
+
+func (p *Sprite) Move(n int) {}
+`
+
+func TestCheckClass(t *testing.T) {
+	cases := []struct {
+		name     string
+		fw       string
+		diags    []string
+		nerr     int
+		features []string
+	}{
+		{"good", `
+type SpriteProto interface {
+	Move(n int)
+	Main()
+	Classfname() string
+}
+
+func Gopt_App_Main(app interface{ initApp() }, sprites []SpriteProto) {}
+`, nil, 0, []string{"Classfname"}},
+		{"methodInClassfile", `
+type SpriteProto interface {
+	Move(n int)
+	Say(msg string)
+	Main()
+}
+
+func Gopt_App_Main(app interface{ initApp() }, sprites []SpriteProto) {}
+`, []string{
+			"warning: work class Sprite doesn't have method Say of SpriteProto, so it must be defined in each _spr.gox classfile",
+		}, 0, nil},
+		{"unexportedMethod", `
+type SpriteProto interface {
+	Move(n int)
+	initSprite()
+}
+
+func Gopt_App_Main(app interface{ initApp() }, sprites []SpriteProto) {}
+`, []string{
+			"work class Sprite doesn't implement SpriteProto: missing unexported method initSprite",
+		}, 1, nil},
+		{"wrongType", `
+type SpriteProto interface {
+	Move(n float64)
+	Main(n int)
+}
+
+func Gopt_App_Main(app interface{ initApp() }, sprites []SpriteProto) {}
+`, []string{
+			"Main of SpriteProto is func(n int), but Main of work class Sprite will be func()",
+			"work class Sprite doesn't implement SpriteProto: wrong type for method Move, have func(n int), want func(n float64)",
+		}, 2, nil},
+		{"noMain", `
+type SpriteProto interface {
+	Move(n int)
+}
+`, []string{
+			"project class App has neither a Main method nor a Gopt_App_Main function",
+		}, 1, nil},
+		{"noWorkParam", `
+type SpriteProto interface {
+	Move(n int)
+}
+
+func Gopt_App_Main(app interface{ initApp() }) {}
+`, []string{
+			"warning: work classes aren't passed to Gopt_App_Main, so they are never instantiated",
+		}, 0, nil},
+		{"notProto", `
+type SpriteProto struct{}
+
+func Gopt_App_Main(app interface{ initApp() }, sprites []SpriteProto) {}
+`, []string{
+			"prototype SpriteProto of work class Sprite isn't an interface",
+		}, 1, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, conf := writeGenModule(t, map[string]string{
+				"gox.mod":  classGoxMod,
+				"fw/fw.go": classFramework + c.fw,
+			})
+			projs, err := CheckClasses(nil, conf)
+			if err != nil {
+				t.Fatal("CheckClasses:", err)
+			}
+			if len(projs) != 1 {
+				t.Fatal("CheckClasses: projects", len(projs))
+			}
+			proj := projs[0]
+			var diags []string
+			for _, d := range proj.Diags {
+				d := *d
+				d.Pos = ""
+				diags = append(diags, d.String())
+			}
+			if !reflect.DeepEqual(diags, c.diags) {
+				t.Fatalf("diags:\n%q\nexpected:\n%q", diags, c.diags)
+			}
+			if n := proj.Errors(); n != c.nerr {
+				t.Fatal("Errors:", n)
+			}
+			if c.features != nil && !reflect.DeepEqual(proj.Works[0].Features, c.features) {
+				t.Fatal("Features:", proj.Works[0].Features)
+			}
+		})
+	}
+}
+
+func TestCheckClassesErr(t *testing.T) {
+	_, conf := writeGenModule(t, map[string]string{})
+	if _, err := CheckClasses(nil, conf); err != ErrNoClassFramework {
+		t.Fatal("CheckClasses:", err)
+	}
+	_, conf = writeGenModule(t, map[string]string{
+		"gox.mod":  classGoxMod,
+		"fw/fw.go": classFramework,
+	})
+	if _, err := CheckClasses([]string{"_unknown.gox"}, conf); err == nil {
+		t.Fatal("CheckClasses: no error")
+	}
+}
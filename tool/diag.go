@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goplus/gogen"
+	"github.com/goplus/xgo/scanner"
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgo/x/typesutil"
+	"github.com/qiniu/x/errors"
+)
+
+// -----------------------------------------------------------------------------
+
+// Severity represents the severity of a diagnostic.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"   // errors of parsing and compiling
+	SeverityWarning Severity = "warning" // eg. diagnostics reported by xgo vet
+)
+
+// Related represents a position related to a diagnostic, eg. the previous
+// declaration of a redeclared name.
+type Related struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// Diagnostic represents a machine-readable diagnostic reported by XGo tools.
+type Diagnostic struct {
+	File      string         `json:"file,omitempty"`
+	Line      int            `json:"line,omitempty"`
+	Column    int            `json:"column,omitempty"`
+	EndLine   int            `json:"endLine,omitempty"`
+	EndColumn int            `json:"endColumn,omitempty"`
+	Severity  Severity       `json:"severity"`
+	Code      typesutil.Code `json:"code,omitempty"`
+	CodeName  string         `json:"codeName,omitempty"`
+	Message   string         `json:"message"`
+	Related   []Related      `json:"related,omitempty"`
+}
+
+// Diagnostics converts err returned by XGo tools (eg. GenGoEx, LoadDir or
+// cl.NewPackage) into a list of diagnostics. fset should be the file set used
+// to parse and compile the packages (see Config.Fset). It returns nil if err
+// is nil.
+func Diagnostics(fset *token.FileSet, err error) (ret []*Diagnostic) {
+	var conv diagConv
+	conv.fset = fset
+	conv.add(err)
+	return conv.ret
+}
+
+// Warnings is like Diagnostics but for warnings of compiling XGo code (see
+// Config.Warning): it reports diagnostics with severity SeverityWarning.
+func Warnings(fset *token.FileSet, err error) (ret []*Diagnostic) {
+	ret = Diagnostics(fset, err)
+	for _, d := range ret {
+		d.Severity = SeverityWarning
+	}
+	return
+}
+
+// WriteDiagnostics writes diagnostics of err to w as a stream of JSON objects,
+// one per line.
+func WriteDiagnostics(w io.Writer, fset *token.FileSet, err error) error {
+	return writeDiags(w, Diagnostics(fset, err))
+}
+
+// WriteWarnings writes warnings of err to w like WriteDiagnostics.
+func WriteWarnings(w io.Writer, fset *token.FileSet, err error) error {
+	return writeDiags(w, Warnings(fset, err))
+}
+
+func writeDiags(w io.Writer, diags []*Diagnostic) error {
+	enc := json.NewEncoder(w)
+	for _, d := range diags {
+		if e := enc.Encode(d); e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+type positioner interface {
+	Position(p token.Pos) token.Position
+}
+
+type diagConv struct {
+	fset *token.FileSet
+	ret  []*Diagnostic
+}
+
+func (p *diagConv) add(err error) {
+	switch v := err.(type) {
+	case nil:
+	case errors.List:
+		for _, e := range v {
+			p.add(e)
+		}
+	case scanner.ErrorList:
+		for _, e := range v {
+			p.addPos(e.Pos, token.Position{}, 0, e.Msg)
+		}
+	case *scanner.Error:
+		p.addPos(v.Pos, token.Position{}, 0, v.Msg)
+	case *gogen.ImportError:
+		d := p.newDiag(p.pos(v.Fset, v.Pos), p.pos(v.Fset, v.End), 0, "")
+		if nested := Diagnostics(p.fset, v.Err); hasPos(nested) {
+			d.Message = "could not import " + v.Path
+			for _, n := range nested {
+				d.Related = append(d.Related, Related{
+					File: n.File, Line: n.Line, Column: n.Column, Message: n.Message,
+				})
+			}
+		} else {
+			d.Message = v.Err.Error()
+		}
+		p.ret = append(p.ret, d)
+	case typesutil.Error:
+		p.addPos(p.pos(v.Fset, v.Pos), p.pos(v.Fset, v.End), v.Code, v.Msg)
+	default:
+		if e, ok := typesutil.NewError(p.fset, err); ok {
+			p.add(e)
+			return
+		}
+		if next := errors.Unwrap(err); next != nil && next != err {
+			p.add(next)
+			return
+		}
+		p.ret = append(p.ret, &Diagnostic{Severity: SeverityError, Message: err.Error()})
+	}
+}
+
+// hasPos reports whether any of diags has a position, ie. whether they are
+// errors in the source code of an imported package.
+func hasPos(diags []*Diagnostic) bool {
+	for _, d := range diags {
+		if d.File != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *diagConv) pos(fset positioner, pos token.Pos) token.Position {
+	if pos == token.NoPos {
+		return token.Position{}
+	}
+	if v, ok := fset.(*token.FileSet); !ok || v == nil {
+		// the compiler reports positions relative to Config.RelativeBase (see
+		// gogen.ImportError), so use p.fset to keep file names consistent.
+		if p.fset != nil {
+			return p.fset.Position(pos)
+		}
+		if !ok && fset != nil {
+			return fset.Position(pos)
+		}
+		return token.Position{}
+	}
+	return fset.Position(pos)
+}
+
+func (p *diagConv) addPos(pos, end token.Position, code typesutil.Code, msg string) {
+	p.ret = append(p.ret, p.newDiag(pos, end, code, msg))
+}
+
+func (p *diagConv) newDiag(pos, end token.Position, code typesutil.Code, msg string) *Diagnostic {
+	msg, related := splitRelated(msg)
+	d := &Diagnostic{
+		File: pos.Filename, Line: pos.Line, Column: pos.Column,
+		EndLine: end.Line, EndColumn: end.Column,
+		Severity: SeverityError, Message: msg, Related: related,
+	}
+	if code != 0 {
+		d.Code, d.CodeName = code, code.String()
+	}
+	return d
+}
+
+// relatedLine matches lines like "\tprevious declaration at a.xgo:3:6".
+var relatedLine = regexp.MustCompile(`^\t(.*) at (.+):(\d+):(\d+)$`)
+
+// splitRelated splits related positions of a multi-line message (the format
+// used by the compiler, eg. for redeclared names) from the message itself.
+func splitRelated(msg string) (string, []Related) {
+	lines := strings.Split(msg, "\n")
+	if len(lines) == 1 {
+		return msg, nil
+	}
+	var related []Related
+	n := 1
+	for _, line := range lines[1:] {
+		if m := relatedLine.FindStringSubmatch(line); m != nil {
+			lineNo, _ := strconv.Atoi(m[3])
+			col, _ := strconv.Atoi(m[4])
+			related = append(related, Related{File: m[2], Line: lineNo, Column: col, Message: m[1]})
+		} else {
+			lines[n] = line
+			n++
+		}
+	}
+	return strings.Join(lines[:n], "\n"), related
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/goplus/gogen"
+	"github.com/goplus/xgo/scanner"
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgo/x/typesutil"
+	qerrors "github.com/qiniu/x/errors"
+)
+
+func TestSplitRelated(t *testing.T) {
+	cases := []struct {
+		msg     string
+		ret     string
+		related []Related
+	}{
+		{"undefined: x", "undefined: x", nil},
+		{
+			"x redeclared in this block\n\tprevious declaration at a.xgo:3:6",
+			"x redeclared in this block",
+			[]Related{{File: "a.xgo", Line: 3, Column: 6, Message: "previous declaration"}},
+		},
+		{
+			"duplicate case 1 in switch\n\tprevious case at /foo/b.xgo:10:7\nmore details",
+			"duplicate case 1 in switch\nmore details",
+			[]Related{{File: "/foo/b.xgo", Line: 10, Column: 7, Message: "previous case"}},
+		},
+		{"first line\nsecond line", "first line\nsecond line", nil},
+	}
+	for _, c := range cases {
+		ret, related := splitRelated(c.msg)
+		if ret != c.ret || !reflect.DeepEqual(related, c.related) {
+			t.Errorf("splitRelated(%q) = %q, %v; want %q, %v", c.msg, ret, related, c.ret, c.related)
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("a.xgo", -1, 100)
+	f.SetLines([]int{0, 10, 20, 30})
+	pos := f.Pos(12) // a.xgo:2:3
+	end := f.Pos(15) // a.xgo:2:6
+
+	if ret := Diagnostics(fset, nil); ret != nil {
+		t.Fatal("Diagnostics(nil):", ret)
+	}
+
+	var scanErrs scanner.ErrorList
+	scanErrs.Add(token.Position{Filename: "b.xgo", Line: 1, Column: 2}, "expected ';'")
+	err := qerrors.List{
+		scanErrs,
+		typesutil.Error{Fset: fset, Pos: pos, End: end, Msg: "x redeclared in this block\n\tprevious declaration at a.xgo:1:5"},
+		&gogen.CodeError{Fset: fset, Pos: pos, End: end, Msg: "undefined: y"},
+		fmt.Errorf("wrapped: %w", &gogen.CodeError{Fset: fset, Pos: pos, Msg: "undefined: z"}),
+		errors.New("no position"),
+	}
+	ret := Diagnostics(fset, err)
+	expected := []*Diagnostic{
+		{File: "b.xgo", Line: 1, Column: 2, Severity: SeverityError, Message: "expected ';'"},
+		{
+			File: "a.xgo", Line: 2, Column: 3, EndLine: 2, EndColumn: 6, Severity: SeverityError,
+			Message: "x redeclared in this block",
+			Related: []Related{{File: "a.xgo", Line: 1, Column: 5, Message: "previous declaration"}},
+		},
+		{File: "a.xgo", Line: 2, Column: 3, EndLine: 2, EndColumn: 6, Severity: SeverityError, Message: "undefined: y"},
+		{File: "a.xgo", Line: 2, Column: 3, Severity: SeverityError, Message: "undefined: z"},
+		{Severity: SeverityError, Message: "no position"},
+	}
+	if !reflect.DeepEqual(ret, expected) {
+		t.Fatalf("Diagnostics:\n%s\nexpected:\n%s", diagsString(ret), diagsString(expected))
+	}
+}
+
+func TestDiagnosticsCode(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "a.go", "package a\n\nvar x int = \"s\"\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conf := &types.Config{Error: func(error) {}}
+	_, err = conf.Check("a", fset, []*ast.File{f}, nil)
+	if err == nil {
+		t.Fatal("types.Config.Check: no error")
+	}
+	ret := Diagnostics(fset, err)
+	if len(ret) != 1 {
+		t.Fatal("Diagnostics:", diagsString(ret))
+	}
+	d := ret[0]
+	if d.File != "a.go" || d.Line != 3 || d.Column != 13 || d.Code == 0 || d.CodeName != d.Code.String() {
+		t.Fatal("Diagnostics:", diagsString(ret))
+	}
+}
+
+func TestDiagnosticsImportError(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("a.xgo", -1, 100)
+	pos := f.Pos(7)
+	nested := &gogen.CodeError{Fset: fset, Pos: pos, Msg: "undefined: foo"}
+	err := &gogen.ImportError{Fset: fset, Pos: pos, Path: "example.com/bar", Err: nested}
+	ret := Diagnostics(fset, err)
+	expected := []*Diagnostic{{
+		File: "a.xgo", Line: 1, Column: 8, Severity: SeverityError,
+		Message: "could not import example.com/bar",
+		Related: []Related{{File: "a.xgo", Line: 1, Column: 8, Message: "undefined: foo"}},
+	}}
+	if !reflect.DeepEqual(ret, expected) {
+		t.Fatalf("Diagnostics:\n%s\nexpected:\n%s", diagsString(ret), diagsString(expected))
+	}
+
+	err = &gogen.ImportError{Fset: fset, Pos: pos, Path: "example.com/bar", Err: errors.New("not found")}
+	ret = Diagnostics(fset, err)
+	if len(ret) != 1 || ret[0].Message != "not found" || ret[0].Related != nil {
+		t.Fatal("Diagnostics:", diagsString(ret))
+	}
+
+	// the compiler reports import errors with file names relative to
+	// RelativeBase: use fset to keep them consistent with other diagnostics.
+	abs := fset.AddFile("/foo/b.xgo", -1, 100)
+	err = &gogen.ImportError{Fset: relPositioner{fset}, Pos: abs.Pos(3), Path: "example.com/bar", Err: errors.New("not found")}
+	ret = Diagnostics(fset, err)
+	if len(ret) != 1 || ret[0].File != "/foo/b.xgo" || ret[0].Line != 1 || ret[0].Column != 4 {
+		t.Fatal("Diagnostics:", diagsString(ret))
+	}
+	ret = Diagnostics(nil, err)
+	if len(ret) != 1 || ret[0].File != "b.xgo" {
+		t.Fatal("Diagnostics:", diagsString(ret))
+	}
+}
+
+type relPositioner struct {
+	fset *token.FileSet
+}
+
+func (p relPositioner) Position(pos token.Pos) token.Position {
+	ret := p.fset.Position(pos)
+	ret.Filename = filepath.Base(ret.Filename)
+	return ret
+}
+
+func TestWriteDiagnostics(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("a.xgo", -1, 100)
+	err := qerrors.List{
+		&gogen.CodeError{Fset: fset, Pos: f.Pos(0), Msg: "undefined: x"},
+		errors.New("failed"),
+	}
+	var buf bytes.Buffer
+	if e := WriteDiagnostics(&buf, fset, err); e != nil {
+		t.Fatal("WriteDiagnostics:", e)
+	}
+	const expected = `{"file":"a.xgo","line":1,"column":1,"severity":"error","message":"undefined: x"}
+{"severity":"error","message":"failed"}
+`
+	if ret := buf.String(); ret != expected {
+		t.Fatalf("WriteDiagnostics:\n%s\nexpected:\n%s", ret, expected)
+	}
+}
+
+func TestWriteWarnings(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("a.xgo", -1, 100)
+	err := &gogen.CodeError{Fset: fset, Pos: f.Pos(0), Msg: "unused variable x"}
+	var buf bytes.Buffer
+	if e := WriteWarnings(&buf, fset, err); e != nil {
+		t.Fatal("WriteWarnings:", e)
+	}
+	const expected = `{"file":"a.xgo","line":1,"column":1,"severity":"warning","message":"unused variable x"}
+`
+	if ret := buf.String(); ret != expected {
+		t.Fatalf("WriteWarnings:\n%s\nexpected:\n%s", ret, expected)
+	}
+}
+
+func diagsString(diags []*Diagnostic) string {
+	var buf bytes.Buffer
+	for _, d := range diags {
+		fmt.Fprintf(&buf, "%+v\n", *d)
+	}
+	return buf.String()
+}
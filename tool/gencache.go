@@ -0,0 +1,302 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goplus/gogen/packages/cache"
+	"github.com/goplus/mod/modfile"
+	"github.com/goplus/mod/xgomod"
+	"github.com/goplus/xgo/parser"
+	"github.com/goplus/xgo/token"
+)
+
+// -----------------------------------------------------------------------------
+
+// GenCacheEnv is the environment variable to specify the directory of the
+// code generation cache. Set it to "off" to disable the cache.
+const GenCacheEnv = "XGOCACHE"
+
+const (
+	genCacheVer  = "xgo-gencache-v2"
+	genCacheMeta = "meta"
+)
+
+// GenCache represents a content-addressed cache of the XGo => Go code
+// generation step. An entry is keyed on the source files and the location of
+// a package, the hashes of the packages it imports, the module files and the
+// options used to compile it, and it holds the generated xgo_autogen*.go
+// files. Packages importing a package which can't be located in the module
+// and packages compiled with warnings aren't cached.
+type GenCache struct {
+	dir string
+}
+
+// NewGenCache creates a code generation cache stored in dir.
+func NewGenCache(dir string) *GenCache {
+	return &GenCache{dir: dir}
+}
+
+// DefaultGenCache returns the default code generation cache. It returns nil
+// if the cache is disabled by setting XGOCACHE=off.
+func DefaultGenCache() *GenCache {
+	dir := os.Getenv(GenCacheEnv)
+	switch dir {
+	case "off":
+		return nil
+	case "":
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return nil
+		}
+		dir = filepath.Join(cacheDir, "xgo-build", "gen")
+	}
+	return NewGenCache(dir)
+}
+
+// Dir returns the root directory of the cache.
+func (p *GenCache) Dir() string {
+	return p.dir
+}
+
+// Clean removes all entries of the cache.
+func (p *GenCache) Clean() error {
+	return os.RemoveAll(p.dir)
+}
+
+func (p *GenCache) entryDir(key string) string {
+	return filepath.Join(p.dir, key[:2], key)
+}
+
+// restore writes files of the cache entry specified by key into dir, and
+// saves the XGo dependencies of the package like compiling it does. It
+// returns false if there is no such entry. If checkOnly is true, it only
+// checks the entry exists (that is, the package compiled successfully).
+func (p *GenCache) restore(key, dir string, conf *Config, checkOnly bool) bool {
+	entry := p.entryDir(key)
+	meta, err := os.ReadFile(filepath.Join(entry, genCacheMeta))
+	if err != nil {
+		return false
+	}
+	var deps, testDeps int
+	if _, err = fmt.Sscan(string(meta), &deps, &testDeps); err != nil {
+		return false
+	}
+	for _, fname := range []string{autoGenFile, autoGenTestFile, autoGen2TestFile} {
+		if checkOnly {
+			break
+		}
+		file := filepath.Join(dir, fname)
+		data, err := os.ReadFile(filepath.Join(entry, fname))
+		if err != nil {
+			if os.IsNotExist(err) { // not generated, remove the stale one if any
+				if err = os.Remove(file); err == nil || os.IsNotExist(err) {
+					continue
+				}
+			}
+			return false
+		}
+		if old, e := os.ReadFile(file); e == nil && bytes.Equal(old, data) {
+			continue // keep mtime unchanged to avoid invalidating dependents
+		}
+		if err = os.WriteFile(file, data, 0644); err != nil {
+			return false
+		}
+	}
+	saveXGoDeps(conf.Mod, conf.XGo, deps, testDeps, conf)
+	return true
+}
+
+// store saves the generated files in dir as the cache entry specified by key,
+// with the XGo dependencies of the package and its test package.
+func (p *GenCache) store(key, dir string, files []string, deps, testDeps int) {
+	entry := p.entryDir(key)
+	if err := os.MkdirAll(filepath.Dir(entry), 0755); err != nil {
+		return
+	}
+	tmp, err := os.MkdirTemp(filepath.Dir(entry), key+".tmp")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(tmp)
+	for _, fname := range files {
+		data, err := os.ReadFile(filepath.Join(dir, fname))
+		if err != nil {
+			return
+		}
+		if err = os.WriteFile(filepath.Join(tmp, fname), data, 0644); err != nil {
+			return
+		}
+	}
+	meta := []byte(fmt.Sprintf("%d %d\n", deps, testDeps))
+	if err := os.WriteFile(filepath.Join(tmp, genCacheMeta), meta, 0644); err != nil {
+		return
+	}
+	os.RemoveAll(entry)
+	os.Rename(tmp, entry)
+}
+
+// -----------------------------------------------------------------------------
+
+// genCacheKey calculates the cache key of generating Go code for the XGo
+// package in dir. It returns "" if the package can't be cached.
+func genCacheKey(dir string, conf *Config, genTestPkg bool) string {
+	imp := conf.Importer
+	mod := conf.Mod
+	if imp == nil || mod == nil || conf.XGo == nil {
+		return ""
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ""
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\ngo\t%s\nxgo\t%s\n", genCacheVer, runtime.Version(), conf.XGo.Version)
+	fmt.Fprintf(h, "tags\t%s\ntest\t%v\nign\t%v\n", imp.impFrom.Tags(), genTestPkg, conf.IgnoreNotatedError)
+	// generated code refers to source files by paths relative to RelativeBase
+	// (eg. //line comments), and to the package by its path.
+	base := relativeBaseOf(mod)
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(base, abs)
+	if err != nil {
+		return ""
+	}
+	fmt.Fprintf(h, "base\t%s\ndir\t%s\npkg\t%s\n", base, filepath.ToSlash(rel), importPathOf(mod, dir))
+	if root := mod.Root(); root != "" {
+		for _, fname := range []string{"go.mod", "gox.mod", "gop.mod"} {
+			hashFile(h, "mod\t"+fname, filepath.Join(root, fname))
+		}
+	}
+
+	var files []string
+	for _, d := range entries {
+		fname := d.Name()
+		if d.IsDir() || strings.HasPrefix(fname, "_") || isAutoGenFile(fname) || !canCl(mod, fname) {
+			continue
+		}
+		if conf.Filter != nil {
+			fi, err := d.Info()
+			if err != nil || !conf.Filter(fi) {
+				continue
+			}
+		}
+		if !hashFile(h, "file\t"+fname, filepath.Join(dir, fname)) {
+			return ""
+		}
+		files = append(files, fname)
+	}
+	if len(files) == 0 {
+		return ""
+	}
+
+	imports, ok := pkgImports(dir, mod, conf)
+	if !ok {
+		return ""
+	}
+	for _, pkgPath := range imports {
+		hash, ok := imp.pkgHash(pkgPath, false)
+		if !ok || hash == cache.HashInvalid {
+			return "" // the package isn't cached if an import can't be hashed
+		}
+		fmt.Fprintf(h, "import\t%s\t%s\n", pkgPath, hash)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// pkgImports returns the sorted import paths of the XGo package in dir,
+// including packages imported implicitly by classfiles.
+func pkgImports(dir string, mod *xgomod.Module, conf *Config) (imports []string, ok bool) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDirEx(fset, dir, parser.Config{
+		ClassKind: mod.ClassKind,
+		Filter:    conf.Filter,
+		Mode:      parser.ImportsOnly,
+	})
+	if err != nil {
+		return
+	}
+	seen := make(map[string]bool)
+	add := func(pkgPath string) {
+		if !seen[pkgPath] {
+			seen[pkgPath] = true
+			imports = append(imports, pkgPath)
+		}
+	}
+	for _, pkg := range pkgs {
+		for fname, f := range pkg.Files {
+			for _, spec := range f.Imports {
+				if pkgPath, e := strconv.Unquote(spec.Path.Value); e == nil {
+					add(pkgPath)
+				}
+			}
+			if f.IsClass {
+				if c, ok := mod.LookupClass(modfile.ClassExt(filepath.Base(fname))); ok {
+					for _, pkgPath := range c.PkgPaths {
+						add(pkgPath)
+					}
+				}
+			}
+		}
+		for _, f := range pkg.GoFiles {
+			for _, spec := range f.Imports {
+				if pkgPath, e := strconv.Unquote(spec.Path.Value); e == nil {
+					add(pkgPath)
+				}
+			}
+		}
+	}
+	sort.Strings(imports)
+	return imports, true
+}
+
+func hashFile(h io.Writer, tag, file string) bool {
+	f, err := os.Open(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return true
+		}
+		return false
+	}
+	defer f.Close()
+	fh := sha256.New()
+	if _, err = io.Copy(fh, f); err != nil {
+		return false
+	}
+	fmt.Fprintf(h, "%s\t%x\n", tag, fh.Sum(nil))
+	return true
+}
+
+func isAutoGenFile(fname string) bool {
+	return strings.HasPrefix(fname, "xgo_autogen") || strings.HasPrefix(fname, "gop_autogen")
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,208 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenCacheRestore(t *testing.T) {
+	dir, conf := writeGenModule(t, map[string]string{})
+	c := NewGenCache(t.TempDir())
+	src := t.TempDir()
+	writeFiles(t, src, map[string]string{
+		autoGenFile:     "package foo\n",
+		autoGenTestFile: "package foo\n\n// test\n",
+	})
+	const key = "0123456789abcdef"
+	c.store(key, src, []string{autoGenFile, autoGenTestFile}, 1, 2)
+
+	dst := t.TempDir()
+	writeFiles(t, dst, map[string]string{
+		autoGen2TestFile: "package foo_test\n", // stale
+	})
+	var deps int
+	conf.XGoDeps = &deps
+	if !c.restore(key, dst, conf, true) {
+		t.Fatal("restore checkOnly: not found")
+	}
+	if _, err := os.Stat(filepath.Join(dst, autoGenFile)); err == nil {
+		t.Fatal("restore checkOnly: files are written")
+	}
+	if gomod, err := os.ReadFile(filepath.Join(dir, "go.mod")); err != nil || !strings.Contains(string(gomod), "github.com/goplus/xgo") {
+		t.Fatalf("restore checkOnly: go.mod isn't updated:\n%s", gomod)
+	}
+	if !c.restore(key, dst, conf, false) || deps != 1 {
+		t.Fatal("restore: failed", deps)
+	}
+	got := readFiles(t, dst)
+	if len(got) != 2 || got[autoGenFile] != "package foo\n" || got[autoGenTestFile] != "package foo\n\n// test\n" {
+		t.Fatalf("restore: %q", got)
+	}
+
+	// a file with the same content isn't rewritten
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	file := filepath.Join(dst, autoGenFile)
+	if err := os.Chtimes(file, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if !c.restore(key, dst, conf, false) {
+		t.Fatal("restore again: failed")
+	}
+	if fi, err := os.Stat(file); err != nil || !fi.ModTime().Equal(old) {
+		t.Fatal("restore again: file is rewritten")
+	}
+
+	// restoring an entry without test files removes stale ones
+	c.store("fedcba9876543210", src, []string{autoGenFile}, 0, 0)
+	if !c.restore("fedcba9876543210", dst, conf, false) {
+		t.Fatal("restore: failed")
+	}
+	if got = readFiles(t, dst); len(got) != 1 || got[autoGenFile] != "package foo\n" {
+		t.Fatalf("restore: %q", got)
+	}
+
+	if c.restore("ffffffffffffffff", dst, conf, false) {
+		t.Fatal("restore: unexpected entry")
+	}
+	if err := c.Clean(); err != nil || c.restore(key, dst, conf, true) {
+		t.Fatal("Clean:", err)
+	}
+}
+
+func readFiles(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := make(map[string]string)
+	for _, e := range entries {
+		if data, err := os.ReadFile(filepath.Join(dir, e.Name())); err == nil {
+			ret[e.Name()] = string(data)
+		}
+	}
+	return ret
+}
+
+func TestGenCacheKey(t *testing.T) {
+	root, _ := filepath.Abs("..")
+	t.Setenv("XGOROOT", root)
+	t.Setenv("GOWORK", "off")
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":      "module example.com/foo\n\ngo 1.21\n",
+		"foo.xgo":     "import \"example.com/foo/bar\"\n\necho bar.Name\n",
+		"bar/bar.xgo": "package bar\n\nconst Name = \"bar\"\n",
+		"ext/a.xgo":   "package ext\n\nimport \"example.org/missing/pkg\"\n",
+		"none/a.txt":  "",
+		"a/p.xgo":     "package p\n\nconst Name = \"p\"\n",
+		"b/p.xgo":     "package p\n\nconst Name = \"p\"\n",
+	})
+	conf, err := NewDefaultConf(dir, ConfFlagNoCacheFile|ConfFlagNoGenCache)
+	if err != nil {
+		t.Fatal("NewDefaultConf:", err)
+	}
+	key := func() string {
+		return genCacheKey(dir, conf, false)
+	}
+	base := key()
+	if base == "" {
+		t.Fatal("genCacheKey: package isn't cached")
+	}
+	if genCacheKey(dir, conf, true) == base {
+		t.Fatal("genCacheKey: genTestPkg isn't in the key")
+	}
+	same := func(name, data string) {
+		t.Helper()
+		writeFiles(t, dir, map[string]string{name: data})
+		if k := key(); k != base {
+			t.Fatalf("genCacheKey: changed by %s", name)
+		}
+	}
+	changed := func(name, data string) {
+		t.Helper()
+		writeFiles(t, dir, map[string]string{name: data})
+		k := key()
+		if k == "" || k == base {
+			t.Fatalf("genCacheKey: not changed by %s", name)
+		}
+		base = k
+	}
+	same(autoGenFile, "package main\n")
+	same("_skip.xgo", "echo 1\n")
+	same("a.txt", "")
+	changed("foo.xgo", "import \"example.com/foo/bar\"\n\necho bar.Name, 1\n")
+	changed("b.xgo", "func hello() {}\n")
+	changed("bar/bar.xgo", "package bar\n\nconst Name = \"bar2\"\n") // imported packages
+	changed("go.mod", "module example.com/foo\n\ngo 1.22\n")
+	changed("gox.mod", "xgo 1.5\n")
+
+	conf.IgnoreNotatedError = true
+	if k := key(); k == base {
+		t.Fatal("genCacheKey: IgnoreNotatedError isn't in the key")
+	}
+	conf.IgnoreNotatedError = false
+	conf.Importer.SetTags("foo")
+	if k := key(); k == base {
+		t.Fatal("genCacheKey: tags aren't in the key")
+	}
+
+	if genCacheKey(filepath.Join(dir, "a"), conf, false) == genCacheKey(filepath.Join(dir, "b"), conf, false) {
+		t.Fatal("genCacheKey: the package directory isn't in the key")
+	}
+	if k := genCacheKey(filepath.Join(dir, "ext"), conf, false); k != "" {
+		t.Fatal("genCacheKey: package importing an unknown package is cached")
+	}
+	if k := genCacheKey(filepath.Join(dir, "none"), conf, false); k != "" {
+		t.Fatal("genCacheKey: package without XGo files is cached")
+	}
+}
+
+func TestGenGoCache(t *testing.T) {
+	const src = "package p\n\nfunc Name(ok bool) string {\n\treturn \"p\"\n}\n"
+	const warn = "package w\n\nfunc Name(ok bool) string {\n\tmatch ok {\n\tcase true:\n\t\treturn \"ok\"\n\t}\n\treturn \"\"\n}\n"
+	dir, conf := writeGenModule(t, map[string]string{
+		"a/p.xgo": src,
+		"b/p.xgo": src,
+		"w/w.xgo": warn,
+	})
+	conf.GenCache = NewGenCache(t.TempDir())
+	var warnings []string
+	conf.Warning = func(err error) {
+		warnings = append(warnings, err.Error())
+	}
+	for _, pkg := range []string{"a", "b", "a", "w", "w"} {
+		if err := genGoIn(filepath.Join(dir, pkg), conf, false, 0); err != nil {
+			t.Fatal("genGoIn:", pkg, err)
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "b", autoGenFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "a/p.xgo") || !strings.Contains(string(data), "b/p.xgo") {
+		t.Fatalf("b/%s restored from the entry of a:\n%s", autoGenFile, data)
+	}
+	if len(warnings) != 2 {
+		t.Fatal("warnings of a cached package:", warnings)
+	}
+}
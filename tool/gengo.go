@@ -69,6 +69,7 @@ func genGoDir(dir string, conf *Config, genTestPkg, recursively bool, flags GenF
 	if recursively {
 		var (
 			list errors.List
+			dirs []string
 			fn   func(path string, d fs.DirEntry, err error) error
 		)
 		if flags&GenFlagSingleFile != 0 {
@@ -84,12 +85,7 @@ func genGoDir(dir string, conf *Config, genTestPkg, recursively bool, flags GenF
 					if strings.HasPrefix(d.Name(), "_") || (path != dir && hasMod(path)) { // skip _
 						return filepath.SkipDir
 					}
-					if e := genGoIn(path, conf, genTestPkg, flags); e != nil && notIgnNotated(e, conf) {
-						if flags&GenFlagPrintError != 0 {
-							fmt.Fprintln(os.Stderr, e)
-						}
-						list.Add(e)
-					}
+					dirs = append(dirs, path)
 				}
 				return err
 			}
@@ -98,6 +94,7 @@ func genGoDir(dir string, conf *Config, genTestPkg, recursively bool, flags GenF
 		if err != nil {
 			return errors.NewWith(err, `filepath.WalkDir(dir, fn)`, -2, "filepath.WalkDir", dir, fn)
 		}
+		genGoDirs(&list, dirs, conf, genTestPkg, flags)
 		return list.ToError()
 	}
 	if flags&GenFlagSingleFile != 0 {
@@ -166,6 +163,29 @@ func genGoSingleFile(file string, extn int, conf *Config, flags GenFlags) (err e
 }
 
 func genGoIn(dir string, conf *Config, genTestPkg bool, flags GenFlags, gen ...*bool) (err error) {
+	var key string
+	if c := conf.GenCache; c != nil {
+		if key = genCacheKey(dir, conf, genTestPkg); key != "" {
+			_, e := os.Lstat(filepath.Join(dir, autoGenFile))
+			if c.restore(key, dir, conf, flags&GenFlagCheckOnly != 0) {
+				if gen != nil && e != nil && flags&GenFlagCheckOnly == 0 {
+					*gen[0] = true
+				}
+				return nil
+			}
+		}
+	}
+	warned := false
+	if key != "" { // packages compiled with warnings aren't cached
+		warnConf, warn := *conf, conf.Warning
+		warnConf.Warning = func(err error) {
+			warned = true
+			if warn != nil {
+				warn(err)
+			}
+		}
+		conf = &warnConf
+	}
 	out, test, err := LoadDir(dir, conf, genTestPkg, (flags&GenFlagPrompt) != 0)
 	if err != nil {
 		if NotFound(err) { // no XGo source files
@@ -186,11 +206,15 @@ func genGoIn(dir string, conf *Config, genTestPkg bool, flags GenFlags, gen ...*
 		*gen[0] = true
 	}
 
+	genFiles := []string{autoGenFile}
 	testFile := filepath.Join(dir, autoGenTestFile)
 	err = out.WriteFile(testFile, testingGoFile)
 	if err != nil && err != syscall.ENOENT {
 		return errors.NewWith(err, `out.WriteFile(testFile, testingGoFile)`, -2, "(*gogen.Package).WriteFile", out, testFile, testingGoFile)
 	}
+	if err == nil {
+		genFiles = append(genFiles, autoGenTestFile)
+	}
 
 	if test != nil {
 		testFile = filepath.Join(dir, autoGen2TestFile)
@@ -198,9 +222,21 @@ func genGoIn(dir string, conf *Config, genTestPkg bool, flags GenFlags, gen ...*
 		if err != nil {
 			return errors.NewWith(err, `test.WriteFile(testFile, testingGoFile)`, -2, "(*gogen.Package).WriteFile", test, testFile, testingGoFile)
 		}
+		genFiles = append(genFiles, autoGen2TestFile)
 	} else {
 		err = nil
 	}
+
+	if c := conf.GenCache; c != nil && key != "" && !warned {
+		// go.mod may be updated by LoadDir, so we recalculate the key.
+		if key = genCacheKey(dir, conf, genTestPkg); key != "" {
+			testDeps := 0
+			if test != nil {
+				testDeps = checkGopDeps(test)
+			}
+			c.store(key, dir, genFiles, checkGopDeps(out), testDeps)
+		}
+	}
 	return
 }
 
@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/goplus/mod/xgomod"
+	"github.com/qiniu/x/errors"
+)
+
+// -----------------------------------------------------------------------------
+
+// GenGoDirs generates xgo_autogen.go for a list of XGo package directories.
+// If conf.Parallel > 1, independent packages are generated concurrently in the
+// order of their import dependencies. Errors are returned (and printed if
+// GenFlagPrintError is set) in the order of dirs.
+func GenGoDirs(dirs []string, conf *Config, genTestPkg bool, flags GenFlags) error {
+	if conf == nil {
+		conf = new(Config)
+	}
+	var list errors.List
+	genGoDirs(&list, dirs, conf, genTestPkg, flags)
+	return list.ToError()
+}
+
+type genTask struct {
+	dir   string
+	deps  int   // number of unfinished dependencies
+	users []int // tasks which depend on this task
+	err   error
+	done  chan none
+}
+
+type none = struct{}
+
+func genGoDirs(list *errors.List, dirs []string, conf *Config, genTestPkg bool, flags GenFlags) {
+	n := conf.Parallel
+	if n > len(dirs) {
+		n = len(dirs)
+	}
+	if n <= 1 || conf.Importer == nil || conf.Mod == nil {
+		for _, dir := range dirs {
+			if e := genGoIn(dir, conf, genTestPkg, flags); e != nil && notIgnNotated(e, conf) {
+				if flags&GenFlagPrintError != 0 {
+					fmt.Fprintln(os.Stderr, e)
+				}
+				list.Add(e)
+			}
+		}
+		return
+	}
+
+	tasks := newGenTasks(dirs, conf)
+	ready := make(chan int, len(tasks))
+	for i, t := range tasks {
+		if t.deps == 0 {
+			ready <- i
+		}
+	}
+
+	var mutex sync.Mutex
+	pending := len(tasks)
+	for i := 0; i < n; i++ {
+		workerConf := *conf
+		workerConf.Importer = conf.Importer.fork()
+		workerConf.XGoDeps = nil
+		go func() {
+			for i := range ready {
+				t := tasks[i]
+				t.err = genGoIn(t.dir, &workerConf, genTestPkg, flags)
+				close(t.done)
+				mutex.Lock()
+				for _, u := range t.users {
+					if tasks[u].deps--; tasks[u].deps == 0 {
+						ready <- u
+					}
+				}
+				if pending--; pending == 0 {
+					close(ready)
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	for _, t := range tasks {
+		<-t.done
+		if e := t.err; e != nil && notIgnNotated(e, conf) {
+			if flags&GenFlagPrintError != 0 {
+				fmt.Fprintln(os.Stderr, e)
+			}
+			list.Add(e)
+		}
+	}
+}
+
+// newGenTasks creates tasks for dirs and links them by their imports. Import
+// cycles are broken so that every task can be scheduled.
+func newGenTasks(dirs []string, conf *Config) []*genTask {
+	mod := conf.Mod
+	tasks := make([]*genTask, len(dirs))
+	index := make(map[string]int, len(dirs))
+	for i, dir := range dirs {
+		tasks[i] = &genTask{dir: dir, done: make(chan none)}
+		if abs, err := filepath.Abs(dir); err == nil {
+			index[abs] = i
+		}
+	}
+	deps := make([][]int, len(dirs))
+	for i, t := range tasks {
+		imports, _ := pkgImports(t.dir, mod, conf)
+		for _, pkgPath := range imports {
+			pkg, err := mod.Lookup(pkgPath)
+			if err != nil || (pkg.Type != xgomod.PkgtModule && pkg.Type != xgomod.PkgtLocal) {
+				continue
+			}
+			if abs, err := filepath.Abs(pkg.Dir); err == nil {
+				if j, ok := index[abs]; ok && j != i {
+					deps[i] = append(deps[i], j)
+				}
+			}
+		}
+	}
+
+	// edges between packages in the same import cycle are dropped
+	comp := sccs(deps)
+	for i, ds := range deps {
+		for _, j := range ds {
+			if comp[i] == comp[j] {
+				continue // break the cycle
+			}
+			tasks[i].deps++
+			tasks[j].users = append(tasks[j].users, i)
+		}
+	}
+	return tasks
+}
+
+// sccs returns the strongly connected component of each node of a graph,
+// where deps[i] are the nodes which node i has edges to. Nodes in the same
+// import cycle are in the same component.
+func sccs(deps [][]int) []int {
+	n := len(deps)
+	comp := make([]int, n)
+	index := make([]int, n) // 0 means unvisited, otherwise visiting order + 1
+	low := make([]int, n)
+	onStack := make([]bool, n)
+	stack := make([]int, 0, n)
+	next, ncomp := 0, 0
+	var visit func(i int)
+	visit = func(i int) {
+		next++
+		index[i], low[i] = next, next
+		stack = append(stack, i)
+		onStack[i] = true
+		for _, j := range deps[i] {
+			if index[j] == 0 {
+				visit(j)
+				low[i] = min(low[i], low[j])
+			} else if onStack[j] {
+				low[i] = min(low[i], index[j])
+			}
+		}
+		if low[i] == index[i] {
+			for {
+				j := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[j] = false
+				comp[j] = ncomp
+				if j == i {
+					break
+				}
+			}
+			ncomp++
+		}
+	}
+	for i := range deps {
+		if index[i] == 0 {
+			visit(i)
+		}
+	}
+	return comp
+}
+
+// -----------------------------------------------------------------------------
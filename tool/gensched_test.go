@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/qiniu/x/errors"
+)
+
+func writeGenModule(t *testing.T, files map[string]string) (string, *Config) {
+	t.Helper()
+	root, _ := filepath.Abs("..")
+	t.Setenv("XGOROOT", root)
+	t.Setenv("GOWORK", "off")
+	dir := t.TempDir()
+	if _, ok := files["go.mod"]; !ok {
+		files["go.mod"] = "module example.com/foo\n\ngo 1.21\n"
+	}
+	writeFiles(t, dir, files)
+	conf, err := NewDefaultConf(dir, ConfFlagNoCacheFile|ConfFlagNoGenCache)
+	if err != nil {
+		t.Fatal("NewDefaultConf:", err)
+	}
+	return dir, conf
+}
+
+func taskDeps(tasks []*genTask) (deps []int, users [][]int) {
+	for _, t := range tasks {
+		deps = append(deps, t.deps)
+		u := append([]int(nil), t.users...)
+		sort.Ints(u)
+		users = append(users, u)
+	}
+	return
+}
+
+func TestNewGenTasks(t *testing.T) {
+	dir, conf := writeGenModule(t, map[string]string{
+		"a/a.xgo": "package a\n\nimport (\n\t\"fmt\"\n\t\"example.com/foo/b\"\n\t\"example.com/foo/c\"\n)\n",
+		"b/b.xgo": "package b\n\nimport \"example.com/foo/c\"\n",
+		"c/c.xgo": "package c\n",
+		"x/x.xgo": "package x\n\nimport \"example.com/foo/y\"\n",
+		"y/y.xgo": "package y\n\nimport \"example.com/foo/x\"\n",
+		"z/z.xgo": "package z\n\nimport \"example.com/foo/x\"\n",
+	})
+	var dirs []string
+	for _, name := range []string{"a", "b", "c", "x", "y", "z"} {
+		dirs = append(dirs, filepath.Join(dir, name))
+	}
+	tasks := newGenTasks(dirs, conf)
+	deps, users := taskDeps(tasks)
+	wantDeps := []int{
+		2, // a: b, c
+		1, // b: c
+		0, // c
+		0, // x: y, in a cycle
+		0, // y: x, in a cycle
+		1, // z: x
+	}
+	wantUsers := [][]int{nil, {0}, {0, 1}, {5}, nil, nil}
+	for i := range tasks {
+		if deps[i] != wantDeps[i] || !equalInts(users[i], wantUsers[i]) {
+			t.Fatalf("tasks[%d]: deps %d users %v, want %d %v", i, deps[i], users[i], wantDeps[i], wantUsers[i])
+		}
+	}
+
+	// packages which aren't in dirs aren't dependencies
+	tasks = newGenTasks(dirs[:1], conf)
+	if deps, _ = taskDeps(tasks); deps[0] != 0 {
+		t.Fatal("newGenTasks: unexpected deps", deps)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGenGoDirsParallel(t *testing.T) {
+	dir, conf := writeGenModule(t, map[string]string{
+		"a/a.xgo":   "package a\n\nimport \"example.com/foo/b\"\n\nfunc A() string {\n\treturn b.B() + \"a\"\n}\n",
+		"b/b.xgo":   "package b\n\nimport \"example.com/foo/c\"\n\nfunc B() string {\n\treturn c.C + \"b\"\n}\n",
+		"c/c.xgo":   "package c\n\nconst C = \"c\"\n",
+		"d/d.xgo":   "package d\n\nfunc D() int {\n\treturn \"d\"\n}\n", // error
+		"e/e.xgo":   "package e\n\nimport \"example.com/foo/c\"\n\nvar E = c.C\n",
+		"f/f.xgo":   "package f\n\nfunc F() int {\n\treturn \"f\"\n}\n", // error
+		"g/g.xgo":   "package g\n\nvar G = 1\n",
+		"h/main.go": "package main\n\nfunc main() {}\n", // no XGo files
+	})
+	conf.Parallel = 4
+	var dirs []string
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		dirs = append(dirs, filepath.Join(dir, name))
+	}
+	err := GenGoDirs(dirs, conf, true, 0)
+	if err == nil {
+		t.Fatal("GenGoDirs: no error")
+	}
+	errs, ok := err.(errors.List)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("GenGoDirs: %v", err)
+	}
+	if !strings.Contains(errs[0].Error(), "d.xgo") || !strings.Contains(errs[1].Error(), "f.xgo") {
+		t.Fatalf("GenGoDirs: errors aren't in the order of dirs: %v", errs)
+	}
+	for _, name := range []string{"a", "b", "c", "e", "g"} {
+		if _, err := os.Stat(filepath.Join(dir, name, autoGenFile)); err != nil {
+			t.Fatalf("GenGoDirs: %s not generated: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "h", autoGenFile)); err == nil {
+		t.Fatal("GenGoDirs: h is generated")
+	}
+}
+
+func TestImporterFork(t *testing.T) {
+	_, conf := writeGenModule(t, map[string]string{})
+	p := conf.Importer
+	p.importStack["example.com/foo/a"] = true
+	p.genLocked = true
+	q := p.fork()
+	if q == p || q.shared != p.shared || q.impFrom != p.impFrom || q.mod != p.mod || q.work != p.work {
+		t.Fatal("fork: imported packages aren't shared")
+	}
+	if len(q.importStack) != 0 || q.genLocked {
+		t.Fatal("fork: import state is shared")
+	}
+	q.importStack["example.com/foo/b"] = true
+	if p.importStack["example.com/foo/b"] {
+		t.Fatal("fork: import stack is shared")
+	}
+	if pkg, err := q.Import("fmt"); err != nil || pkg.Path() != "fmt" {
+		t.Fatal("Import:", pkg, err)
+	}
+	if pkg, err := p.Import("fmt"); err != nil || pkg.Path() != "fmt" {
+		t.Fatal("Import:", pkg, err)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
+	"go/constant"
 	"go/token"
 	"go/types"
 	"io"
@@ -30,7 +31,9 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 
+	"github.com/goplus/gogen"
 	"github.com/goplus/gogen/packages"
 	"github.com/goplus/gogen/packages/cache"
 	"github.com/goplus/mod/env"
@@ -42,15 +45,25 @@ import (
 // -----------------------------------------------------------------------------
 
 // Importer represents an XGo importer.
+// It is safe for concurrent use by multiple compilations, as long as each of
+// them uses its own view of the importer (see Config.Parallel).
 type Importer struct {
 	impFrom *packages.Importer
 	mod     *xgomod.Module
+	work    *Workspace
 	xgo     *env.XGo
 	fset    *token.FileSet
 
 	Flags GenFlags // can change this for loading XGo modules
 
 	importStack map[string]bool
+	shared      *impShared
+	genLocked   bool
+}
+
+type impShared struct {
+	impMutex sync.Mutex // protects impFrom and initializing imported XGo packages
+	genMutex sync.Mutex // serializes generating Go code for imported packages
 }
 
 // NewImporter creates an XGo Importer.
@@ -66,13 +79,30 @@ func NewImporter(mod *xgomod.Module, xgo *env.XGo, fset *token.FileSet) *Importe
 			mod = xgomod.Default
 		}
 	}
+	var work *Workspace
+	if mod.HasModfile() {
+		work, _ = LoadWork(mod.Root())
+		work.apply(mod)
+	}
 	dir := mod.Root()
 	impFrom := packages.NewImporter(fset, dir)
-	ret := &Importer{mod: mod, xgo: xgo, impFrom: impFrom, fset: fset, Flags: defaultFlags, importStack: make(map[string]bool)}
+	ret := &Importer{
+		mod: mod, work: work, xgo: xgo, impFrom: impFrom, fset: fset, Flags: defaultFlags,
+		importStack: make(map[string]bool), shared: new(impShared),
+	}
 	impFrom.SetCache(cache.New(ret.PkgHash))
 	return ret
 }
 
+// fork creates a new view of the importer which shares all imported packages
+// with p. Each goroutine compiling packages should use its own view.
+func (p *Importer) fork() *Importer {
+	ret := *p
+	ret.importStack = make(map[string]bool)
+	ret.genLocked = false
+	return &ret
+}
+
 func (p *Importer) SetTags(tags string) {
 	p.impFrom.SetTags(tags)
 	if c, ok := p.impFrom.Cache().(*cache.Impl); ok {
@@ -108,24 +138,33 @@ func (p *Importer) Cache() *cache.Impl {
 // PkgHash calculates hash value for a package.
 // It is required by cache.New func.
 func (p *Importer) PkgHash(pkgPath string, self bool) string {
+	if hash, ok := p.pkgHash(pkgPath, self); ok {
+		return hash
+	}
+	log.Println("PkgHash: unexpected package -", pkgPath)
+	return cache.HashInvalid
+}
+
+// pkgHash is like PkgHash, but it returns false instead of logging if
+// pkgPath can't be located in the module (eg. its module isn't required).
+func (p *Importer) pkgHash(pkgPath string, self bool) (string, bool) {
 	if pkg, e := p.mod.Lookup(pkgPath); e == nil {
 		switch pkg.Type {
 		case xgomod.PkgtStandard:
-			return cache.HashSkip
+			return cache.HashSkip, true
 		case xgomod.PkgtExtern:
 			if pkg.Real.Version != "" {
-				return pkg.Real.String()
+				return pkg.Real.String(), true
 			}
 			fallthrough
 		case xgomod.PkgtModule:
-			return dirHash(p.mod, p.xgo, pkg.Dir, self)
+			return dirHash(p.mod, p.xgo, pkg.Dir, self), true
 		}
 	}
 	if isPkgInMod(pkgPath, xgoMod) || isPkgInMod(pkgPath, xMod) {
-		return cache.HashSkip
+		return cache.HashSkip, true
 	}
-	log.Println("PkgHash: unexpected package -", pkgPath)
-	return cache.HashInvalid
+	return cache.HashInvalid, false
 }
 
 const (
@@ -140,57 +179,138 @@ func (p *Importer) Import(pkgPath string) (pkg *types.Package, err error) {
 	}
 	p.importStack[pkgPath] = true
 	defer delete(p.importStack, pkgPath)
+	dir, err := p.resolve(pkgPath, true)
+	if err != nil {
+		return
+	}
+	p.shared.impMutex.Lock()
+	defer p.shared.impMutex.Unlock()
+	return p.importFrom(pkgPath, dir)
+}
+
+// resolve returns the directory from which pkgPath should be imported ("" means
+// the root of the current module). If gen is true, it also downloads the
+// package or generates Go code for it when needed.
+func (p *Importer) resolve(pkgPath string, gen bool) (dir string, err error) {
 	if strings.HasPrefix(pkgPath, xgoMod) {
 		if suffix := pkgPath[len(xgoMod):]; suffix == "" || suffix[0] == '/' {
 			xgoRoot := p.xgo.Root
-			if suffix == "/cl/internal/gop-in-go/foo" { // for test github.com/goplus/xgo/cl
+			if gen && suffix == "/cl/internal/gop-in-go/foo" { // for test github.com/goplus/xgo/cl
 				if err = p.genGoExtern(xgoRoot+suffix, false); err != nil {
 					return
 				}
 			}
-			return p.impFrom.ImportFrom(pkgPath, xgoRoot, 0)
+			return xgoRoot, nil
 		}
 	}
 	if isPkgInMod(pkgPath, xMod) {
-		return p.impFrom.ImportFrom(pkgPath, p.xgo.Root, 0)
+		return p.xgo.Root, nil
 	}
 	if mod := p.mod; mod.HasModfile() {
 		ret, e := mod.Lookup(pkgPath)
 		if e != nil {
-			return nil, e
+			return "", e
 		}
 		switch ret.Type {
 		case xgomod.PkgtExtern:
 			isExtern := ret.Real.Version != ""
-			if isExtern {
+			if !isExtern && p.work.contains(ret.ModDir) { // a module of the workspace
+				if gen {
+					err = p.genGoExtern(ret.Dir, false)
+				}
+				return
+			}
+			if gen && isExtern {
 				if _, err = modfetch.Get(ret.Real.String()); err != nil {
 					return
 				}
 			}
 			modDir := ret.ModDir
 			goModfile := filepath.Join(modDir, "go.mod")
-			if _, e := os.Lstat(goModfile); e != nil { // no go.mod
+			if _, e := os.Lstat(goModfile); gen && e != nil { // no go.mod
 				os.Chmod(modDir, modWritable)
 				defer os.Chmod(modDir, modReadonly)
 				os.WriteFile(goModfile, defaultGoMod(ret.ModPath), 0644)
 			}
-			return p.impFrom.ImportFrom(pkgPath, ret.ModDir, 0)
+			return modDir, nil
 		case xgomod.PkgtModule, xgomod.PkgtLocal:
 			if pkgPath == p.mod.Path() {
 				break
 			}
-			if err = p.genGoExtern(ret.Dir, false); err != nil {
-				return
+			if gen {
+				if err = p.genGoExtern(ret.Dir, false); err != nil {
+					return
+				}
 			}
 		case xgomod.PkgtStandard:
-			return p.impFrom.ImportFrom(pkgPath, p.xgo.Root, 0)
+			return p.xgo.Root, nil
+		}
+	}
+	return "", nil
+}
+
+// importFrom imports pkgPath from dir. It must be called with impMutex held.
+func (p *Importer) importFrom(pkgPath, dir string) (pkg *types.Package, err error) {
+	if dir == "" {
+		pkg, err = p.impFrom.Import(pkgPath)
+	} else {
+		pkg, err = p.impFrom.ImportFrom(pkgPath, dir, 0)
+	}
+	if err == nil {
+		p.initXGoPkg(pkg)
+	}
+	return
+}
+
+const (
+	xgoPackage1 = "XGoPackage"
+	xgoPackage2 = "GopPackage"
+	xgoPkgInit  = "__xgo_inited"
+)
+
+// initXGoPkg initializes an imported XGo package (and the XGo packages it
+// depends on) the same way gogen does. Doing it in advance under impMutex
+// makes sure the package is only read when shared by concurrent compilations.
+func (p *Importer) initXGoPkg(pkg *types.Package) {
+	scope := pkg.Scope()
+	objXGoPkg := scope.Lookup(xgoPackage1)
+	if objXGoPkg == nil {
+		if objXGoPkg = scope.Lookup(xgoPackage2); objXGoPkg == nil {
+			return // not is a XGo package
+		}
+	}
+	if scope.Lookup(xgoPkgInit) != nil { // initialized
+		return
+	}
+	scope.Insert(types.NewConst(
+		token.NoPos, pkg, xgoPkgInit, types.Typ[types.UntypedBool], constant.MakeBool(true),
+	))
+	pkgDeps, ok := objXGoPkg.(*types.Const)
+	if !ok {
+		return
+	}
+	gogen.InitXGoPackage(pkg)
+	if v := pkgDeps.Val(); v.Kind() == constant.String {
+		for _, depPath := range strings.Split(constant.StringVal(v), ",") {
+			if dir, err := p.resolve(depPath, false); err == nil {
+				p.importFrom(depPath, dir)
+			}
 		}
 	}
-	return p.impFrom.Import(pkgPath)
 }
 
 func (p *Importer) genGoExtern(dir string, isExtern bool) (err error) {
 	genfile := filepath.Join(dir, autoGenFile)
+	// xgo_autogen.go is checked with the lock held, because another view of
+	// the importer may be writing it
+	if !p.genLocked {
+		p.shared.genMutex.Lock()
+		p.genLocked = true
+		defer func() {
+			p.genLocked = false
+			p.shared.genMutex.Unlock()
+		}()
+	}
 	if _, err = os.Lstat(genfile); err != nil { // no xgo_autogen.go
 		if isExtern {
 			os.Chmod(dir, modWritable)
@@ -201,11 +321,12 @@ func (p *Importer) genGoExtern(dir string, isExtern bool) (err error) {
 		if err != nil {
 			return
 		}
-		if gen {
+		if gen && !p.work.contains(dir) { // go.mod of a workspace module is maintained by its owner
 			cmd := exec.Command("go", "mod", "tidy")
 			cmd.Stdout = os.Stdout
 			cmd.Stderr = os.Stderr
 			cmd.Dir = dir
+			setWorkEnv(cmd)
 			err = cmd.Run()
 		}
 	}
@@ -0,0 +1,461 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goplus/mod/modfile"
+	"github.com/goplus/mod/xgomod"
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/ast/mod"
+	"github.com/goplus/xgo/parser"
+	"github.com/goplus/xgo/token"
+)
+
+// -----------------------------------------------------------------------------
+
+// ClassFile represents a classfile of an XGo package.
+type ClassFile struct {
+	File  string // file name
+	Class string // class type, see GetFileClassType
+	Proj  bool   `json:",omitempty"` // is a project class or not
+	Test  bool   `json:",omitempty"` // is a test class or not
+}
+
+// PackageError represents an error loading a package.
+type PackageError struct {
+	Err string
+}
+
+// Package represents metadata of an XGo/Go package, see `xgo list`.
+type Package struct {
+	Dir        string `json:",omitempty"` // directory containing package sources
+	ImportPath string // import path of package in dir
+	Name       string `json:",omitempty"` // package name
+	Module     string `json:",omitempty"` // path of the module containing the package
+	Standard   bool   `json:",omitempty"` // is this package part of the standard Go library?
+
+	XGoFiles     []string    `json:",omitempty"` // .xgo and .gop source files
+	ClassFiles   []string    `json:",omitempty"` // .gox and registered classfiles
+	GoFiles      []string    `json:",omitempty"` // .go source files (excluding generated files)
+	TestXGoFiles []string    `json:",omitempty"` // _test.xgo and test classfiles
+	TestGoFiles  []string    `json:",omitempty"` // _test.go files
+	Project      string      `json:",omitempty"` // class type of the project classfile
+	Classes      []ClassFile `json:",omitempty"` // classfiles with their class types
+
+	Imports     []string `json:",omitempty"` // import paths used by this package
+	TestImports []string `json:",omitempty"` // imports from test files
+	Deps        []string `json:",omitempty"` // all (recursively) imported dependencies
+
+	AutoGen     string `json:",omitempty"` // path of xgo_autogen.go
+	Stale       bool   `json:",omitempty"` // would `xgo go` regenerate xgo_autogen.go?
+	StaleReason string `json:",omitempty"` // explanation for Stale==true
+
+	Error *PackageError `json:",omitempty"` // error loading package
+}
+
+// ListPackages returns metadata of packages matched by patterns. A pattern
+// can be a directory, a directory followed by "/..." or an import path in the
+// current module. If deps is true, all dependencies of the matched packages
+// are listed too, and each package is listed after its dependencies.
+func ListPackages(patterns []string, conf *Config, deps bool) (pkgs []*Package, err error) {
+	if conf == nil {
+		conf = new(Config)
+	}
+	if conf.Mod == nil {
+		if conf.Mod, err = LoadMod("."); err != nil {
+			return
+		}
+	}
+	var l lister
+	l.conf = conf
+	l.loaded = make(map[string]*Package)
+	for _, pattern := range patterns {
+		dirs, e := l.match(pattern)
+		if e != nil {
+			return nil, e
+		}
+		for _, dir := range dirs {
+			pkg := l.loadDir(dir)
+			if pkg == nil {
+				continue
+			}
+			if deps {
+				l.addDeps(pkg)
+			} else if !l.listed[pkg] {
+				l.add(pkg)
+			}
+		}
+	}
+	return l.pkgs, nil
+}
+
+type lister struct {
+	conf   *Config
+	loaded map[string]*Package // dir or import path => package
+	listed map[*Package]bool
+	pkgs   []*Package
+}
+
+func (p *lister) add(pkg *Package) {
+	if p.listed == nil {
+		p.listed = make(map[*Package]bool)
+	}
+	p.listed[pkg] = true
+	p.pkgs = append(p.pkgs, pkg)
+}
+
+func (p *lister) addDeps(pkg *Package) {
+	if p.listed[pkg] {
+		return
+	}
+	if p.listed == nil {
+		p.listed = make(map[*Package]bool)
+	}
+	p.listed[pkg] = true // mark first to stop at import cycles
+	seen := make(map[string]bool)
+	for _, imp := range pkg.Imports {
+		dep := p.loadImport(imp)
+		p.addDeps(dep)
+		for _, d := range append([]string{dep.ImportPath}, dep.Deps...) {
+			if !seen[d] {
+				seen[d] = true
+				pkg.Deps = append(pkg.Deps, d)
+			}
+		}
+	}
+	sort.Strings(pkg.Deps)
+	p.pkgs = append(p.pkgs, pkg)
+}
+
+func (p *lister) match(pattern string) (dirs []string, err error) {
+	if strings.HasSuffix(pattern, "/...") || pattern == "..." {
+		root := strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+		if root == "" {
+			root = "."
+		}
+		if !isDirPattern(root) {
+			if root, err = p.pkgDir(root); err != nil {
+				return
+			}
+		}
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && d.IsDir() {
+				if path != root && (strings.HasPrefix(d.Name(), "_") || strings.HasPrefix(d.Name(), ".") ||
+					d.Name() == "testdata" || hasMod(path)) {
+					return filepath.SkipDir
+				}
+				dirs = append(dirs, path)
+			}
+			return err
+		})
+		return
+	}
+	if isDirPattern(pattern) {
+		return []string{pattern}, nil
+	}
+	dir, err := p.pkgDir(pattern)
+	if err != nil {
+		return
+	}
+	return []string{dir}, nil
+}
+
+func isDirPattern(pattern string) bool {
+	return pattern == "." || pattern == ".." || strings.HasPrefix(pattern, "./") ||
+		strings.HasPrefix(pattern, "../") || filepath.IsAbs(pattern)
+}
+
+func (p *lister) pkgDir(pkgPath string) (string, error) {
+	pkg, err := p.conf.Mod.Lookup(pkgPath)
+	if err != nil {
+		return "", err
+	}
+	return pkg.Dir, nil
+}
+
+func (p *lister) loadImport(pkgPath string) *Package {
+	if pkg, ok := p.loaded[pkgPath]; ok {
+		return pkg
+	}
+	mod := p.conf.Mod
+	if ret, err := mod.Lookup(pkgPath); err == nil {
+		switch ret.Type {
+		case xgomod.PkgtModule, xgomod.PkgtLocal:
+			if pkg := p.loadDir(ret.Dir); pkg != nil {
+				p.loaded[pkgPath] = pkg
+				return pkg
+			}
+		case xgomod.PkgtStandard:
+			pkg := &Package{ImportPath: pkgPath, Standard: true}
+			p.loaded[pkgPath] = pkg
+			return pkg
+		case xgomod.PkgtExtern:
+			pkg := &Package{ImportPath: pkgPath, Dir: ret.Dir, Module: ret.ModPath}
+			p.loaded[pkgPath] = pkg
+			return pkg
+		}
+	}
+	pkg := &Package{ImportPath: pkgPath}
+	p.loaded[pkgPath] = pkg
+	return pkg
+}
+
+// loadDir loads metadata of the package in dir. It returns nil if there are
+// no XGo or Go source files in dir.
+func (p *lister) loadDir(dir string) *Package {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		abs = dir
+	}
+	if pkg, ok := p.loaded[abs]; ok {
+		return pkg
+	}
+	pkg := loadPackage(abs, p.conf)
+	p.loaded[abs] = pkg
+	if pkg != nil {
+		p.loaded[pkg.ImportPath] = pkg
+	}
+	return pkg
+}
+
+func loadPackage(dir string, conf *Config) *Package {
+	xgoMod := conf.Mod
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDirEx(fset, dir, parser.Config{
+		ClassKind: xgoMod.ClassKind,
+		Filter:    conf.Filter,
+	})
+	if len(pkgs) == 0 && err == nil {
+		return nil
+	}
+	ret := &Package{Dir: dir, ImportPath: importPathOf(xgoMod, dir)}
+	if xgoMod.HasModfile() {
+		ret.Module = xgoMod.Path()
+	}
+	if err != nil {
+		ret.Error = &PackageError{Err: err.Error()}
+	}
+
+	imports := make(map[string]bool)
+	testImports := make(map[string]bool)
+	importsOf := func(isTest bool) map[string]bool {
+		if isTest {
+			return testImports
+		}
+		return imports
+	}
+	depsOf := func(isTest bool) mod.Deps {
+		return mod.Deps{HandlePkg: func(pkgPath string) {
+			importsOf(isTest)[pkgPath] = true
+		}}
+	}
+	names := make([]string, 0, len(pkgs))
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		pkg := pkgs[name]
+		if !strings.HasSuffix(name, "_test") {
+			ret.Name = name
+		}
+		for file, f := range pkg.Files {
+			fname := filepath.Base(file)
+			classType, isTest := GetFileClassType(xgoMod, f, fname)
+			isTest = isTest || strings.HasSuffix(name, "_test")
+			switch {
+			case isTest:
+				ret.TestXGoFiles = append(ret.TestXGoFiles, fname)
+			case f.IsClass:
+				ret.ClassFiles = append(ret.ClassFiles, fname)
+			default:
+				ret.XGoFiles = append(ret.XGoFiles, fname)
+			}
+			if f.IsClass {
+				ret.Classes = append(ret.Classes, ClassFile{File: fname, Class: classType, Proj: f.IsProj, Test: isTest})
+				if f.IsProj && !isTest {
+					ret.Project = classType
+				}
+			}
+			depsOf(isTest).LoadFile(f, true)
+			implicitImports(xgoMod, fset, f, fname, func(pkgPath, _ string) {
+				importsOf(isTest)[pkgPath] = true
+			})
+		}
+		for file, f := range pkg.GoFiles {
+			fname := filepath.Base(file)
+			isTest := strings.HasSuffix(fname, "_test.go")
+			if isTest {
+				ret.TestGoFiles = append(ret.TestGoFiles, fname)
+			} else {
+				ret.GoFiles = append(ret.GoFiles, fname)
+			}
+			depsOf(isTest).LoadGoFile(f)
+		}
+	}
+	for _, list := range [][]string{ret.XGoFiles, ret.ClassFiles, ret.GoFiles, ret.TestXGoFiles, ret.TestGoFiles} {
+		sort.Strings(list)
+	}
+	sort.Slice(ret.Classes, func(i, j int) bool {
+		return ret.Classes[i].File < ret.Classes[j].File
+	})
+	ret.Imports = sortedKeys(imports)
+	ret.TestImports = sortedKeys(testImports)
+
+	if len(ret.XGoFiles)+len(ret.ClassFiles)+len(ret.TestXGoFiles) > 0 {
+		ret.AutoGen = filepath.Join(dir, autoGenFile)
+		ret.Stale, ret.StaleReason = autoGenStale(dir, conf)
+	}
+	return ret
+}
+
+const (
+	xgoTplPkg      = "github.com/goplus/xgo/tpl"
+	xgoEncodingPkg = "github.com/goplus/xgo/encoding/"
+)
+
+// implicitImports calls add for each package that f imports implicitly, with
+// the reason of the import: packages of the class framework of a classfile,
+// packages of domain text literals (eg. json`...` imports
+// github.com/goplus/xgo/encoding/json) and packages used by the methods
+// generated for enum types.
+func implicitImports(xgoMod *xgomod.Module, fset *token.FileSet, f *ast.File, fname string, add func(pkgPath, reason string)) {
+	if f.IsClass {
+		if c, ok := xgoMod.LookupClass(modfile.ClassExt(fname)); ok {
+			for _, pkgPath := range c.PkgPaths {
+				add(pkgPath, "classfile "+fname)
+			}
+		}
+	}
+	names := importNames(f)
+	ast.Inspect(f, func(node ast.Node) bool {
+		switch v := node.(type) {
+		case *ast.DomainTextLit:
+			if name := v.Domain.Name; !names[name] {
+				pkgPath := xgoEncodingPkg + name
+				if name == "tpl" {
+					pkgPath = xgoTplPkg
+				}
+				pos := fset.Position(v.Pos())
+				add(pkgPath, fmt.Sprintf("domain text literal %s`...` at %s:%d", name, fname, pos.Line))
+			}
+		case *ast.EnumDecl:
+			pkgPaths := enumImports
+			if v.IsSumType() {
+				pkgPaths = pkgPaths[:2] // String of a sum type doesn't use strconv
+			}
+			pos := fset.Position(v.Pos())
+			for _, pkgPath := range pkgPaths {
+				add(pkgPath, fmt.Sprintf("enum %s at %s:%d", v.Name.Name, fname, pos.Line))
+			}
+		}
+		return true
+	})
+}
+
+// enumImports are packages used by the methods generated for an enum type.
+var enumImports = []string{"encoding/json", "fmt", "strconv"}
+
+// importNames returns the names of packages imported by f.
+func importNames(f *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	for _, imp := range f.Imports {
+		if imp.Name != nil {
+			names[imp.Name.Name] = true
+		} else if s, err := strconv.Unquote(imp.Path.Value); err == nil {
+			names[s[strings.LastIndexByte(s, '/')+1:]] = true
+		}
+	}
+	return names
+}
+
+func sortedKeys(m map[string]bool) []string {
+	if len(m) == 0 {
+		return nil
+	}
+	ret := make([]string, 0, len(m))
+	for k := range m {
+		ret = append(ret, k)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func importPathOf(mod *xgomod.Module, dir string) string {
+	if mod.HasModfile() {
+		if abs, err := filepath.Abs(dir); err == nil {
+			if rel, err := filepath.Rel(mod.Root(), abs); err == nil && !strings.HasPrefix(rel, "..") {
+				if rel == "." {
+					return mod.Path()
+				}
+				return path.Join(mod.Path(), filepath.ToSlash(rel))
+			}
+		}
+	}
+	return dir
+}
+
+// autoGenStale reports whether xgo_autogen.go in dir is out of date.
+func autoGenStale(dir string, conf *Config) (bool, string) {
+	autogen := filepath.Join(dir, autoGenFile)
+	fi, err := os.Stat(autogen)
+	if err != nil {
+		return true, autoGenFile + " not found"
+	}
+	if c := conf.GenCache; c != nil {
+		if key := genCacheKey(dir, conf, true); key != "" {
+			if data, err := os.ReadFile(filepath.Join(c.entryDir(key), autoGenFile)); err == nil {
+				if old, err := os.ReadFile(autogen); err == nil && bytes.Equal(old, data) {
+					return false, ""
+				}
+				return true, autoGenFile + " differs from the generated code"
+			}
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return true, err.Error()
+	}
+	var newest time.Time
+	var newestFile string
+	for _, d := range entries {
+		fname := d.Name()
+		if d.IsDir() || strings.HasPrefix(fname, "_") || isAutoGenFile(fname) || !canCl(conf.Mod, fname) {
+			continue
+		}
+		if info, err := d.Info(); err == nil && info.ModTime().After(newest) {
+			newest, newestFile = info.ModTime(), fname
+		}
+	}
+	if newest.After(fi.ModTime()) {
+		return true, "newer source file " + newestFile
+	}
+	return false, ""
+}
+
+// -----------------------------------------------------------------------------
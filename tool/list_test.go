@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestListPackages(t *testing.T) {
+	dir, conf := writeGenModule(t, map[string]string{
+		"gox.mod":           classGoxMod,
+		"fw/fw.go":          classFramework,
+		"a/a.xgo":           "package a\n\nimport \"example.com/foo/b\"\n\nvar A = b.B\n\nvar doc = json`{\"a\": 1}`\n",
+		"a/a_test.xgo":      "package a\n\nimport \"testing\"\n\nfunc TestA(t *testing.T) {}\n",
+		"b/b.xgo":           "package b\n\nimport json \"encoding/json\"\n\nvar B, _ = json.Marshal(1)\n",
+		"game/main_app.gox": "echo \"hi\"\n",
+		"game/hero_spr.gox": "var x = tpl`expr = INT`\n",
+	})
+	pkgs, err := ListPackages([]string{filepath.Join(dir, "...")}, conf, false)
+	if err != nil {
+		t.Fatal("ListPackages:", err)
+	}
+	byPath := make(map[string]*Package)
+	for _, pkg := range pkgs {
+		if pkg.Error != nil {
+			t.Fatalf("%s: %s", pkg.ImportPath, pkg.Error.Err)
+		}
+		byPath[pkg.ImportPath] = pkg
+	}
+
+	a := byPath["example.com/foo/a"]
+	if a == nil {
+		t.Fatal("package a isn't listed")
+	}
+	if a.Name != "a" || a.Module != "example.com/foo" {
+		t.Fatal("package a:", a.Name, a.Module)
+	}
+	if !reflect.DeepEqual(a.XGoFiles, []string{"a.xgo"}) || !reflect.DeepEqual(a.TestXGoFiles, []string{"a_test.xgo"}) {
+		t.Fatal("package a files:", a.XGoFiles, a.TestXGoFiles)
+	}
+	if want := []string{"example.com/foo/b", "github.com/goplus/xgo/encoding/json"}; !reflect.DeepEqual(a.Imports, want) {
+		t.Fatal("package a imports:", a.Imports)
+	}
+	if want := []string{"testing"}; !reflect.DeepEqual(a.TestImports, want) {
+		t.Fatal("package a test imports:", a.TestImports)
+	}
+	if !a.Stale || a.StaleReason != autoGenFile+" not found" {
+		t.Fatal("package a stale:", a.Stale, a.StaleReason)
+	}
+
+	if b := byPath["example.com/foo/b"]; b == nil || !reflect.DeepEqual(b.Imports, []string{"encoding/json"}) {
+		t.Fatal("package b:", b)
+	}
+
+	game := byPath["example.com/foo/game"]
+	if game == nil {
+		t.Fatal("package game isn't listed")
+	}
+	if want := []string{"example.com/foo/fw", "github.com/goplus/xgo/tpl"}; !reflect.DeepEqual(game.Imports, want) {
+		t.Fatal("package game imports:", game.Imports)
+	}
+	wantClasses := []ClassFile{
+		{File: "hero_spr.gox", Class: "hero"},
+		{File: "main_app.gox", Class: "App", Proj: true},
+	}
+	if !reflect.DeepEqual(game.Classes, wantClasses) || game.Project != "App" {
+		t.Fatal("package game classes:", game.Classes, game.Project)
+	}
+
+	if fw := byPath["example.com/foo/fw"]; fw == nil || fw.AutoGen != "" || fw.Stale {
+		t.Fatal("package fw:", fw)
+	}
+}
+
+func TestListPackagesDeps(t *testing.T) {
+	dir, conf := writeGenModule(t, map[string]string{
+		"a/a.xgo": "package a\n\nimport \"example.com/foo/b\"\n\nvar A = b.B\n",
+		"b/b.xgo": "package b\n\nvar B = 1\n",
+	})
+	pkgs, err := ListPackages([]string{filepath.Join(dir, "a")}, conf, true)
+	if err != nil {
+		t.Fatal("ListPackages:", err)
+	}
+	var paths []string
+	for _, pkg := range pkgs {
+		paths = append(paths, pkg.ImportPath)
+	}
+	if want := []string{"example.com/foo/b", "example.com/foo/a"}; !reflect.DeepEqual(paths, want) {
+		t.Fatal("ListPackages:", paths)
+	}
+}
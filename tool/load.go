@@ -22,6 +22,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
 
 	"github.com/goplus/gogen"
 	"github.com/goplus/mod/env"
@@ -130,6 +131,18 @@ type Config struct {
 	// CacheFile specifies the file path of the cache.
 	CacheFile string
 
+	// GenCache specifies the cache of generated Go code. If nil, Go code is
+	// always regenerated.
+	GenCache *GenCache
+
+	// Parallel specifies the maximum number of packages to generate Go code
+	// for concurrently. 0 or 1 means packages are generated one by one.
+	Parallel int
+
+	// Warning is called to report a warning of compiling XGo code, eg. a
+	// non-exhaustive match statement (optional).
+	Warning func(err error)
+
 	IgnoreNotatedError bool
 	DontUpdateGoMod    bool
 }
@@ -142,6 +155,7 @@ const (
 	ConfFlagDontUpdateGoMod
 	ConfFlagNoTestFiles
 	ConfFlagNoCacheFile
+	ConfFlagNoGenCache
 )
 
 // NewDefaultConf creates a dfault configuration for common cases.
@@ -160,17 +174,25 @@ func NewDefaultConf(dir string, flags ConfFlags, tags ...string) (conf *Config,
 		XGo: xgo, Fset: fset, Mod: mod, Importer: imp,
 		IgnoreNotatedError: flags&ConfFlagIgnoreNotatedError != 0,
 		DontUpdateGoMod:    flags&ConfFlagDontUpdateGoMod != 0,
+		Warning:            printWarning,
 	}
 	if flags&ConfFlagNoCacheFile == 0 {
 		conf.CacheFile = imp.CacheFile()
 		imp.Cache().Load(conf.CacheFile)
 	}
+	if flags&ConfFlagNoGenCache == 0 {
+		conf.GenCache = DefaultGenCache()
+	}
 	if flags&ConfFlagNoTestFiles != 0 {
 		conf.Filter = FilterNoTestFiles
 	}
 	return
 }
 
+func printWarning(err error) {
+	fmt.Fprintln(os.Stderr, "warning:", err)
+}
+
 func (conf *Config) NewGoCmdConf() *gocmd.Config {
 	if cl := conf.Mod.Opt.Compiler; cl != nil {
 		if os.Getenv("XGO_GOCMD") == "" {
@@ -179,6 +201,7 @@ func (conf *Config) NewGoCmdConf() *gocmd.Config {
 	}
 	return &gocmd.Config{
 		XGo: conf.XGo,
+		Env: WorkEnv(conf.Mod.Root()),
 	}
 }
 
@@ -203,6 +226,12 @@ func LoadMod(dir string) (mod *xgomod.Module, err error) {
 	if mod == nil {
 		mod = xgomod.Default
 	}
+	work, err := LoadWork(dir)
+	if err != nil {
+		err = errors.NewWith(err, `LoadWork(dir)`, -2, "tool.LoadWork", dir)
+		return
+	}
+	work.apply(mod)
 	err = mod.ImportClasses()
 	if err != nil {
 		err = errors.NewWith(err, `mod.ImportClasses()`, -2, "(*xgomod.Module).ImportClasses", mod)
@@ -276,6 +305,7 @@ func LoadDir(dir string, conf *Config, genTestPkg bool, promptGenGo ...bool) (ou
 		RelativeBase: relativeBaseOf(mod),
 		Importer:     imp,
 		LookupClass:  mod.LookupClass,
+		Warning:      conf.Warning,
 	}
 
 	for name, pkg := range pkgs {
@@ -313,23 +343,38 @@ func LoadDir(dir string, conf *Config, genTestPkg bool, promptGenGo ...bool) (ou
 	return
 }
 
+// modMutex serializes updating go.mod when packages are loaded concurrently.
+var modMutex sync.Mutex
+
 func afterLoad(mod *xgomod.Module, xgo *env.XGo, out, test *gogen.Package, conf *Config) {
 	if mod.Path() == xgoMod { // nothing to do for XGo itself
 		return
 	}
 	updateMod := !conf.DontUpdateGoMod && mod.HasModfile()
 	if updateMod || conf.XGoDeps != nil {
-		flags := checkGopDeps(out)
-		if conf.XGoDeps != nil { // for `xgo run`
-			*conf.XGoDeps = flags
+		deps, testDeps := checkGopDeps(out), 0
+		if updateMod && test != nil {
+			testDeps = checkGopDeps(test)
 		}
-		if updateMod {
-			if test != nil {
-				flags |= checkGopDeps(test)
-			}
-			if flags != 0 {
-				mod.SaveWithXGoMod(xgo, flags)
-			}
+		saveXGoDeps(mod, xgo, deps, testDeps, conf)
+	}
+}
+
+// saveXGoDeps returns the XGo dependencies deps of a package by conf.XGoDeps,
+// and adds them to go.mod with those of its test package unless
+// conf.DontUpdateGoMod is set.
+func saveXGoDeps(mod *xgomod.Module, xgo *env.XGo, deps, testDeps int, conf *Config) {
+	if mod.Path() == xgoMod { // nothing to do for XGo itself
+		return
+	}
+	if conf.XGoDeps != nil { // for `xgo run`
+		*conf.XGoDeps = deps
+	}
+	if !conf.DontUpdateGoMod && mod.HasModfile() {
+		if flags := deps | testDeps; flags != 0 {
+			modMutex.Lock()
+			mod.SaveWithXGoMod(xgo, flags)
+			modMutex.Unlock()
 		}
 	}
 }
@@ -396,6 +441,7 @@ func LoadFiles(dir string, files []string, conf *Config) (out *gogen.Package, er
 			RelativeBase: relativeBaseOf(mod),
 			Importer:     imp,
 			LookupClass:  mod.LookupClass,
+			Warning:      conf.Warning,
 		}
 		out, err = cl.NewPackage("", pkg, clConf)
 		if err != nil {
@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/goplus/mod/modfile"
+	"github.com/goplus/mod/xgomod"
+	"github.com/qiniu/x/errors"
+)
+
+// -----------------------------------------------------------------------------
+
+// ModEdge represents an edge of the module graph, see `xgo mod graph`.
+type ModEdge struct {
+	From    string   // path of the main module, or path@version of a depended module
+	To      string   // path@version of the depended module
+	Classes []string `json:",omitempty"` // classfile exts if To is a class framework of From
+}
+
+// ModGraph returns the module graph of the module in dir. Besides the edges
+// reported by `go mod graph`, it includes the class framework dependencies
+// declared by gox.mod (project/class lines) and by requirements marked as
+// `//xgo:class` in go.mod.
+func ModGraph(dir string) (edges []*ModEdge, err error) {
+	mod, err := LoadMod(dir)
+	if err != nil {
+		return
+	}
+	if !mod.HasModfile() {
+		return nil, ErrNotFound
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.Command("go", "mod", "graph")
+	cmd.Dir = mod.Root()
+	setWorkEnv(cmd)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err = cmd.Run(); err != nil {
+		return nil, errors.NewWith(err, `cmd.Run()`, -2, "(*exec.Cmd).Run")
+	}
+	index := make(map[string]*ModEdge)
+	addEdge := func(from, to string) *ModEdge {
+		key := from + " " + to
+		e, ok := index[key]
+		if !ok {
+			e = &ModEdge{From: from, To: to}
+			index[key] = e
+			edges = append(edges, e)
+		}
+		return e
+	}
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		if from, to, ok := strings.Cut(scanner.Text(), " "); ok {
+			addEdge(from, to)
+		}
+	}
+
+	main := mod.Path()
+	err = mod.ImportClasses(func(c *modfile.Project) {
+		if c == xgomod.TestProject || c == xgomod.GshProject || c == xgomod.SpxProject {
+			return // builtin class frameworks
+		}
+		exts := classExts(c)
+		from := main
+		for i, pkgPath := range c.PkgPaths {
+			to, ok := classModOf(mod, pkgPath)
+			if !ok {
+				continue
+			}
+			if to != from {
+				e := addEdge(from, to)
+				e.Classes = appendUnique(e.Classes, exts...)
+			}
+			if i == 0 { // the framework package: other packages are depended by it
+				from = to
+			}
+		}
+	})
+	return
+}
+
+func classExts(c *modfile.Project) []string {
+	exts := []string{c.Ext}
+	for _, w := range c.Works {
+		exts = appendUnique(exts, w.Ext)
+	}
+	return exts
+}
+
+// classModOf returns path@version of the depended module which contains pkgPath.
+func classModOf(mod *xgomod.Module, pkgPath string) (string, bool) {
+	pkg, err := mod.Lookup(pkgPath)
+	if err != nil || pkg.Type != xgomod.PkgtExtern {
+		return "", false
+	}
+	for _, r := range mod.Require {
+		if r.Mod.Path == pkg.ModPath {
+			return r.Mod.String(), true
+		}
+	}
+	return pkg.ModPath, true // used from the workspace
+}
+
+func appendUnique(list []string, vals ...string) []string {
+	for _, v := range vals {
+		found := false
+		for _, e := range list {
+			if e == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"reflect"
+	"testing"
+)
+
+// fwModFiles are files of a module requiring the class framework example.com/fw,
+// which is replaced by a local directory.
+func fwModFiles(files map[string]string) map[string]string {
+	files["go.mod"] = `module example.com/foo
+
+go 1.21
+
+require example.com/fw v1.0.0
+
+replace example.com/fw => ./_fw
+`
+	files["gox.mod"] = "xgo 1.5\n\nproject _app.gox App example.com/fw\nclass _spr.gox Sprite\n"
+	files["_fw/go.mod"] = "module example.com/fw\n\ngo 1.21\n"
+	files["_fw/fw.go"] = "package fw\n\nconst XGoPackage = true\n\ntype App struct{}\n\ntype Sprite struct{}\n"
+	return files
+}
+
+func TestModGraph(t *testing.T) {
+	t.Setenv("GOPROXY", "off")
+	dir, _ := writeGenModule(t, fwModFiles(map[string]string{
+		"main.xgo": "echo \"hi\"\n",
+	}))
+	edges, err := ModGraph(dir)
+	if err != nil {
+		t.Fatal("ModGraph:", err)
+	}
+	var fw *ModEdge
+	for _, e := range edges {
+		if e.To == "example.com/fw@v1.0.0" {
+			fw = e
+		}
+	}
+	if fw == nil {
+		t.Fatal("ModGraph: no edge to example.com/fw")
+	}
+	want := &ModEdge{From: "example.com/foo", To: "example.com/fw@v1.0.0", Classes: []string{"_app.gox", "_spr.gox"}}
+	if !reflect.DeepEqual(fw, want) {
+		t.Fatalf("ModGraph: got %+v, want %+v", fw, want)
+	}
+}
+
+func TestModGraphNoModfile(t *testing.T) {
+	if _, err := ModGraph(t.TempDir()); err != ErrNotFound {
+		t.Fatal("ModGraph:", err)
+	}
+}
@@ -0,0 +1,240 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"bufio"
+	"bytes"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goplus/mod/xgomod"
+	"github.com/goplus/xgo/ast/mod"
+	"github.com/goplus/xgo/parser"
+	"github.com/goplus/xgo/token"
+	"github.com/qiniu/x/errors"
+)
+
+// -----------------------------------------------------------------------------
+
+// WhyStep represents a package in an import chain, see `xgo mod why`.
+type WhyStep struct {
+	Pkg    string
+	Reason string `json:",omitempty"` // how the previous package imports Pkg if it's an implicit import
+}
+
+// ModWhy returns the shortest import chain from the packages of the module in
+// dir to each of targets. Besides import declarations, XGo code imports packages
+// implicitly by classfiles, domain text literals (eg. json`...` imports
+// github.com/goplus/xgo/encoding/json) and enum types (their generated methods
+// import encoding/json), and these imports are reported with a reason. If module is true, targets are module paths and the chain to any
+// package of the module is returned. The chain is nil if the main module
+// doesn't need the target.
+func ModWhy(dir string, targets []string, module bool) (chains [][]*WhyStep, err error) {
+	xgoMod, err := LoadMod(dir)
+	if err != nil {
+		return
+	}
+	if !xgoMod.HasModfile() {
+		return nil, ErrNotFound
+	}
+	g := &whyGraph{mod: xgoMod, edges: make(map[string][]*WhyStep), mains: make(map[string]bool)}
+	root := xgoMod.Root()
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			if path != root && (strings.HasPrefix(d.Name(), "_") || strings.HasPrefix(d.Name(), ".") ||
+				d.Name() == "testdata" || hasMod(path)) {
+				return filepath.SkipDir
+			}
+			g.loadDir(path)
+		}
+		return err
+	})
+	if err != nil {
+		return
+	}
+	if err = g.loadGoDeps(root); err != nil {
+		return
+	}
+	order, via := g.walk()
+	for _, target := range targets {
+		chains = append(chains, g.chain(order, via, target, module))
+	}
+	return
+}
+
+type whyGraph struct {
+	mod   *xgomod.Module
+	edges map[string][]*WhyStep // package => packages imported by it
+	mains map[string]bool       // packages of the main module
+	order []string              // packages of the main module in loading order
+}
+
+func (p *whyGraph) loadDir(dir string) {
+	fset := token.NewFileSet()
+	pkgs, _ := parser.ParseDirEx(fset, dir, parser.Config{ClassKind: p.mod.ClassKind})
+	if len(pkgs) == 0 {
+		return
+	}
+	pkgPath := importPathOf(p.mod, dir)
+	if p.mains[pkgPath] {
+		return
+	}
+	p.mains[pkgPath] = true
+	p.order = append(p.order, pkgPath)
+
+	var imports []*WhyStep
+	index := make(map[string]*WhyStep)
+	add := func(imp, reason string) {
+		if strings.HasPrefix(imp, ".") { // local package
+			imp = importPathOf(p.mod, filepath.Join(dir, imp))
+		}
+		if step, ok := index[imp]; ok {
+			if reason == "" { // an explicit import wins
+				step.Reason = ""
+			}
+			return
+		}
+		step := &WhyStep{Pkg: imp, Reason: reason}
+		index[imp] = step
+		imports = append(imports, step)
+	}
+	deps := mod.Deps{HandlePkg: func(imp string) { add(imp, "") }}
+	for _, name := range sortedNames(pkgs) {
+		pkg := pkgs[name]
+		for _, file := range sortedNames(pkg.Files) {
+			f := pkg.Files[file]
+			deps.LoadFile(f, true)
+			implicitImports(p.mod, fset, f, filepath.Base(file), add)
+		}
+		for _, file := range sortedNames(pkg.GoFiles) {
+			deps.LoadGoFile(pkg.GoFiles[file])
+		}
+	}
+	p.edges[pkgPath] = imports
+}
+
+// loadGoDeps loads the imports of the packages out of the main module by `go list`.
+func (p *whyGraph) loadGoDeps(root string) error {
+	args := []string{"list", "-e", "-deps", "-f", "{{.ImportPath}}{{range .Imports}} {{.}}{{end}}"}
+	n := len(args)
+	seen := make(map[string]bool)
+	for _, pkgPath := range p.order {
+		for _, imp := range p.edges[pkgPath] {
+			if !p.mains[imp.Pkg] && !seen[imp.Pkg] {
+				seen[imp.Pkg] = true
+				args = append(args, imp.Pkg)
+			}
+		}
+	}
+	if len(args) == n {
+		return nil
+	}
+	sort.Strings(args[n:])
+	var stdout bytes.Buffer
+	cmd := exec.Command("go", args...)
+	cmd.Dir = root
+	setWorkEnv(cmd)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errors.NewWith(err, `cmd.Run()`, -2, "(*exec.Cmd).Run")
+	}
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || p.mains[fields[0]] {
+			continue
+		}
+		imports := make([]*WhyStep, len(fields)-1)
+		for i, imp := range fields[1:] {
+			imports[i] = &WhyStep{Pkg: imp}
+		}
+		p.edges[fields[0]] = imports
+	}
+	return nil
+}
+
+// walk visits all packages reachable from the main module in breadth-first
+// order. It returns the visiting order and how each package is reached.
+func (p *whyGraph) walk() (order []string, via map[string]*whyVia) {
+	via = make(map[string]*whyVia)
+	order = append(order, p.order...)
+	sort.Strings(order)
+	for _, pkgPath := range order {
+		via[pkgPath] = nil
+	}
+	for i := 0; i < len(order); i++ {
+		pkgPath := order[i]
+		for _, imp := range p.edges[pkgPath] {
+			if _, ok := via[imp.Pkg]; !ok {
+				via[imp.Pkg] = &whyVia{from: pkgPath, step: imp}
+				order = append(order, imp.Pkg)
+			}
+		}
+	}
+	return
+}
+
+type whyVia struct {
+	from string
+	step *WhyStep
+}
+
+func (p *whyGraph) chain(order []string, via map[string]*whyVia, target string, module bool) (chain []*WhyStep) {
+	pkgPath := target
+	if module {
+		pkgPath = ""
+		for _, v := range order {
+			if isPkgInMod(v, target) {
+				pkgPath = v
+				break
+			}
+		}
+	}
+	if _, ok := via[pkgPath]; !ok {
+		return nil
+	}
+	for {
+		v := via[pkgPath]
+		if v == nil { // a package of the main module
+			chain = append(chain, &WhyStep{Pkg: pkgPath})
+			break
+		}
+		chain = append(chain, v.step)
+		pkgPath = v.from
+	}
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return
+}
+
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"strings"
+	"testing"
+)
+
+func whyString(chain []*WhyStep) string {
+	var b strings.Builder
+	for i, step := range chain {
+		if i > 0 {
+			b.WriteString(" -> ")
+		}
+		b.WriteString(step.Pkg)
+		if step.Reason != "" {
+			b.WriteString(" (" + step.Reason + ")")
+		}
+	}
+	return b.String()
+}
+
+func TestModWhy(t *testing.T) {
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOFLAGS", "-mod=readonly")
+	dir, _ := writeGenModule(t, fwModFiles(map[string]string{
+		"a/a.xgo":        "package a\n\nimport \"example.com/foo/b\"\n\nvar A = b.B\n",
+		"b/b.xgo":        "package b\n\nvar B = 1\n\nvar doc = json`{\"b\": 1}`\n",
+		"c/c.xgo":        "package c\n\nenum Color {\n\tRed\n\tGreen\n}\n",
+		"game/a_app.gox": "echo \"hi\"\n",
+	}))
+	targets := []string{
+		"github.com/goplus/xgo/encoding/json",
+		"encoding/json",
+		"strconv",
+		"example.com/fw",
+		"net/http",
+	}
+	chains, err := ModWhy(dir, targets, false)
+	if err != nil {
+		t.Fatal("ModWhy:", err)
+	}
+	want := []string{
+		"example.com/foo/b -> github.com/goplus/xgo/encoding/json (domain text literal json`...` at b.xgo:5)",
+		"example.com/foo/c -> encoding/json (enum Color at c.xgo:3)",
+		"example.com/foo/c -> strconv (enum Color at c.xgo:3)",
+		"example.com/foo/game -> example.com/fw (classfile a_app.gox)",
+		"",
+	}
+	for i, chain := range chains {
+		if got := whyString(chain); got != want[i] {
+			t.Errorf("ModWhy %s:\ngot:  %s\nwant: %s", targets[i], got, want[i])
+		}
+	}
+
+	chains, err = ModWhy(dir, []string{"example.com/fw"}, true)
+	if err != nil {
+		t.Fatal("ModWhy:", err)
+	}
+	if got := whyString(chains[0]); got != want[3] {
+		t.Fatal("ModWhy -m:", got)
+	}
+}
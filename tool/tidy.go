@@ -70,6 +70,7 @@ func Tidy(dir string, xgo *env.XGo) (err error) {
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Dir = modRoot
+	setWorkEnv(cmd)
 	err = cmd.Run()
 	if err != nil {
 		err = errors.NewWith(err, `cmd.Run()`, -2, "(*exec.Cmd).Run")
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/goplus/mod/env"
+	"github.com/goplus/mod/xgomod"
+	"github.com/qiniu/x/errors"
+)
+
+// Vendor makes a vendored copy of dependencies of the module in dir. Unlike
+// `go mod vendor`, it generates Go code for all XGo packages of the module and
+// of the depended modules first, so that the generated xgo_autogen.go files
+// are vendored too and the module can be built by `go build -mod=vendor`.
+// In workspace mode, it vendors the workspace by `go work vendor` like the go
+// command suggests.
+func Vendor(dir string, xgo *env.XGo) (err error) {
+	modObj, err := xgomod.Load(dir)
+	if err != nil {
+		return errors.NewWith(err, `xgomod.Load(dir)`, -2, "xgomod.Load", dir)
+	}
+
+	modRoot := modObj.Root()
+	conf := &Config{XGo: xgo}
+	err = genGoDir(modRoot, conf, true, true, 0)
+	if err != nil {
+		return errors.NewWith(err, `genGoDir(modRoot, conf, true, true)`, -2, "tool.genGoDir", modRoot, conf, true, true)
+	}
+
+	cmd := exec.Command("go", "mod", "vendor")
+	cmd.Dir = modRoot
+	if work := FindWork(modRoot); work != "" {
+		cmd = exec.Command("go", "work", "vendor")
+		cmd.Dir = filepath.Dir(work)
+	}
+	setWorkEnv(cmd)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	if err != nil {
+		err = errors.NewWith(err, `cmd.Run()`, -2, "(*exec.Cmd).Run")
+	}
+	return
+}
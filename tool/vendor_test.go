@@ -0,0 +1,41 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goplus/xgo/x/xgoenv"
+)
+
+func TestVendor(t *testing.T) {
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOFLAGS", "-mod=readonly")
+	dir, _ := writeGenModule(t, fwModFiles(map[string]string{
+		"a/a.xgo": "package a\n\nimport \"example.com/fw\"\n\nvar A = &fw.App{}\n",
+	}))
+	if err := Vendor(dir, xgoenv.Get()); err != nil {
+		t.Fatal("Vendor:", err)
+	}
+	for _, file := range []string{"a/xgo_autogen.go", "vendor/modules.txt", "vendor/example.com/fw/fw.go"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Fatal("Vendor:", err)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/goplus/mod/xgomod"
+	gomodfile "golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// -----------------------------------------------------------------------------
+
+const (
+	GoWorkFile  = "go.work"
+	XGoWorkFile = "xgo.work"
+)
+
+// ErrWorkExists is returned by InitWork if the workspace file already exists.
+var ErrWorkExists = errors.New("workspace file already exists")
+
+// WorkMod represents a module used by a workspace.
+type WorkMod struct {
+	Dir  string // absolute directory of the module
+	Path string // module path
+}
+
+// Workspace represents a Go workspace (go.work) or an XGo workspace (xgo.work).
+type Workspace struct {
+	File   string // absolute path of the workspace file
+	Mods   []*WorkMod
+	Syntax *gomodfile.WorkFile
+}
+
+// FindWork finds the workspace file of dir. Like the go command, it honours
+// the GOWORK environment variable ("off" disables workspace mode), otherwise
+// it looks for go.work (or xgo.work) in dir and its parent directories. It
+// returns "" if dir isn't in a workspace.
+func FindWork(dir string) string {
+	switch gowork := os.Getenv("GOWORK"); gowork {
+	case "off":
+		return ""
+	case "", "auto":
+	default:
+		if file, err := filepath.Abs(gowork); err == nil {
+			return file
+		}
+		return gowork
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		for _, name := range []string{GoWorkFile, XGoWorkFile} {
+			file := filepath.Join(dir, name)
+			if fi, e := os.Stat(file); e == nil && !fi.IsDir() {
+				return file
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// WorkEnv returns environment variables the go command needs to use the
+// workspace of dir. The go command doesn't know about xgo.work, so if dir is
+// in an xgo.work workspace and GOWORK isn't set, GOWORK is set to it. It
+// returns nil if the go command finds the workspace by itself.
+//
+// Note that `go list` run by the gogen importer doesn't get them, so Go
+// packages are imported in workspace mode only if the workspace is a go.work.
+func WorkEnv(dir string) []string {
+	if os.Getenv("GOWORK") != "" {
+		return nil
+	}
+	if file := FindWork(dir); filepath.Base(file) == XGoWorkFile {
+		return []string{"GOWORK=" + file}
+	}
+	return nil
+}
+
+// setWorkEnv makes cmd, a go command, use the workspace of its directory.
+func setWorkEnv(cmd *exec.Cmd) {
+	if env := WorkEnv(cmd.Dir); env != nil {
+		cmd.Env = append(os.Environ(), env...)
+	}
+}
+
+// LoadWork loads the workspace that dir belongs to. It returns (nil, nil) if
+// dir isn't in a workspace.
+func LoadWork(dir string) (work *Workspace, err error) {
+	file := FindWork(dir)
+	if file == "" {
+		return
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return
+	}
+	f, err := gomodfile.ParseWork(file, data, nil)
+	if err != nil {
+		return
+	}
+	work = &Workspace{File: file, Syntax: f}
+	if err = work.loadMods(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// InitWork creates a go.work file in dir which uses the modules in dirs.
+func InitWork(dir, goVer string, dirs []string) (work *Workspace, err error) {
+	file, err := filepath.Abs(filepath.Join(dir, GoWorkFile))
+	if err != nil {
+		return
+	}
+	if _, e := os.Lstat(file); e == nil {
+		return nil, fmt.Errorf("%s: %w", file, ErrWorkExists)
+	}
+	f, err := gomodfile.ParseWork(file, nil, nil)
+	if err != nil {
+		return
+	}
+	if err = f.AddGoStmt(goVer); err != nil {
+		return
+	}
+	work = &Workspace{File: file, Syntax: f}
+	if err = work.Use(dirs, false); err != nil {
+		return nil, err
+	}
+	return work, work.Save()
+}
+
+func (p *Workspace) loadMods() error {
+	root := filepath.Dir(p.File)
+	mods := make([]*WorkMod, 0, len(p.Syntax.Use))
+	for _, u := range p.Syntax.Use {
+		dir := filepath.FromSlash(u.Path)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(root, dir)
+		}
+		modPath, err := workModulePath(dir)
+		if err != nil {
+			return err
+		}
+		mods = append(mods, &WorkMod{Dir: dir, Path: modPath})
+	}
+	p.Mods = mods
+	return nil
+}
+
+func workModulePath(dir string) (string, error) {
+	gomod := filepath.Join(dir, "go.mod")
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		return "", err
+	}
+	modPath := gomodfile.ModulePath(data)
+	if modPath == "" {
+		return "", fmt.Errorf("%s: no module declaration", gomod)
+	}
+	return modPath, nil
+}
+
+// Use adds the modules in dirs to the workspace. If recursive is true, all
+// modules in the directory trees of dirs are added. A directory without go.mod
+// is removed from the workspace, as `go work use` does.
+func (p *Workspace) Use(dirs []string, recursive bool) (err error) {
+	for _, dir := range dirs {
+		if recursive {
+			err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+				if err != nil || !d.IsDir() {
+					return err
+				}
+				if path != dir {
+					if name := d.Name(); strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+						name == "testdata" || name == "vendor" {
+						return filepath.SkipDir
+					}
+				}
+				if _, e := os.Lstat(filepath.Join(path, "go.mod")); e == nil {
+					return p.use(path)
+				}
+				return nil
+			})
+		} else {
+			err = p.use(dir)
+		}
+		if err != nil {
+			return
+		}
+	}
+	p.Syntax.Cleanup()
+	return p.loadMods()
+}
+
+func (p *Workspace) use(dir string) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	diskPath, err := filepath.Rel(filepath.Dir(p.File), absDir)
+	if err != nil {
+		diskPath = absDir
+	} else if diskPath = filepath.ToSlash(diskPath); !strings.HasPrefix(diskPath, "../") {
+		diskPath = "./" + diskPath
+	}
+	if diskPath == "./." {
+		diskPath = "."
+	}
+	modPath, err := workModulePath(absDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return p.Syntax.DropUse(diskPath)
+		}
+		return err
+	}
+	return p.Syntax.AddUse(diskPath, modPath)
+}
+
+// Save saves all changes of the workspace.
+func (p *Workspace) Save() error {
+	p.Syntax.SortBlocks()
+	data := gomodfile.Format(p.Syntax.Syntax)
+	return os.WriteFile(p.File, data, 0644)
+}
+
+// contains checks if dir is in a module of the workspace.
+func (p *Workspace) contains(dir string) bool {
+	if p != nil {
+		for _, m := range p.Mods {
+			if rel, err := filepath.Rel(m.Dir, dir); err == nil && !strings.HasPrefix(rel, "..") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// apply makes the modules of the workspace (except mod itself) depended by mod
+// as local modules, so that their packages and class frameworks are resolved
+// from the workspace instead of the module cache.
+func (p *Workspace) apply(mod *xgomod.Module) {
+	if p == nil || !mod.HasModfile() {
+		return
+	}
+	deps := mod.DepMods()
+	for _, m := range p.Mods {
+		if m.Path != mod.Path() {
+			deps[m.Path] = module.Version{Path: m.Dir}
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
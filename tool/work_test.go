@@ -0,0 +1,236 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goplus/mod/xgomod"
+	"golang.org/x/mod/module"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, data := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindWork(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.work":         "go 1.21\n",
+		"a/b/c.txt":       "",
+		"x/xgo.work":      "go 1.21\n",
+		"x/y/c.txt":       "",
+		"x/go.work/c.txt": "", // not a workspace file
+	})
+	cases := []struct {
+		dir, work string
+	}{
+		{"", "go.work"},
+		{"a/b", "go.work"},
+		{"x", "x/xgo.work"},
+		{"x/y", "x/xgo.work"},
+		{"x/go.work", "x/xgo.work"},
+	}
+	for _, c := range cases {
+		want := filepath.Join(root, filepath.FromSlash(c.work))
+		if ret := FindWork(filepath.Join(root, c.dir)); ret != want {
+			t.Errorf("FindWork(%s) = %s, want %s", c.dir, ret, want)
+		}
+	}
+
+	t.Setenv("GOWORK", "off")
+	if ret := FindWork(root); ret != "" {
+		t.Fatal("FindWork(GOWORK=off):", ret)
+	}
+	t.Setenv("GOWORK", "auto")
+	if ret := FindWork(root); ret != filepath.Join(root, "go.work") {
+		t.Fatal("FindWork(GOWORK=auto):", ret)
+	}
+	other := filepath.Join(root, "x", "xgo.work")
+	t.Setenv("GOWORK", other)
+	if ret := FindWork(filepath.Join(root, "a")); ret != other {
+		t.Fatal("FindWork(GOWORK):", ret)
+	}
+}
+
+func TestWorkEnv(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.work":      "go 1.21\n\nuse ./a\n",
+		"a/go.mod":     "module example.com/a\n",
+		"x/xgo.work":   "go 1.21\n\nuse ./b\n",
+		"x/b/go.mod":   "module example.com/b\n",
+		"none/go.work": "", // directory
+	})
+	if env := WorkEnv(filepath.Join(root, "a")); env != nil {
+		t.Fatal("WorkEnv(go.work):", env)
+	}
+	xgoWork := filepath.Join(root, "x", "xgo.work")
+	env := WorkEnv(filepath.Join(root, "x", "b"))
+	if len(env) != 1 || env[0] != "GOWORK="+xgoWork {
+		t.Fatal("WorkEnv(xgo.work):", env)
+	}
+
+	work, err := LoadWork(filepath.Join(root, "x", "b"))
+	if err != nil || work == nil || work.File != xgoWork {
+		t.Fatal("LoadWork:", work, err)
+	}
+	if gowork := os.Getenv("GOWORK"); gowork != "" {
+		t.Fatal("LoadWork changed GOWORK:", gowork)
+	}
+	cmd := newGoCmd(filepath.Join(root, "x"))
+	if !hasEnv(cmd.Env, "GOWORK="+xgoWork) {
+		t.Fatal("setWorkEnv:", cmd.Env)
+	}
+	if cmd = newGoCmd(filepath.Join(root, "a")); cmd.Env != nil {
+		t.Fatal("setWorkEnv(go.work):", cmd.Env)
+	}
+
+	t.Setenv("GOWORK", "off")
+	if env := WorkEnv(filepath.Join(root, "x")); env != nil {
+		t.Fatal("WorkEnv(GOWORK=off):", env)
+	}
+}
+
+func newGoCmd(dir string) *exec.Cmd {
+	cmd := exec.Command("go", "env", "GOWORK")
+	cmd.Dir = dir
+	setWorkEnv(cmd)
+	return cmd
+}
+
+func hasEnv(env []string, kv string) bool {
+	for _, v := range env {
+		if v == kv {
+			return true
+		}
+	}
+	return false
+}
+
+func TestWorkUse(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"a/go.mod":          "module example.com/a\n",
+		"b/go.mod":          "module example.com/b\n",
+		"b/c/go.mod":        "module example.com/b/c\n",
+		"b/_skip/go.mod":    "module example.com/skip1\n",
+		"b/.hidden/go.mod":  "module example.com/skip2\n",
+		"b/testdata/go.mod": "module example.com/skip3\n",
+		"b/vendor/go.mod":   "module example.com/skip4\n",
+		"d/go.mod":          "// no module declaration\n",
+	})
+	work, err := InitWork(root, "1.21", []string{filepath.Join(root, "a")})
+	if err != nil {
+		t.Fatal("InitWork:", err)
+	}
+	if _, err = InitWork(root, "1.21", nil); !errors.Is(err, ErrWorkExists) {
+		t.Fatal("InitWork again:", err)
+	}
+	checkMods := func(work *Workspace, want ...string) {
+		t.Helper()
+		var mods []string
+		for _, m := range work.Mods {
+			rel, _ := filepath.Rel(root, m.Dir)
+			mods = append(mods, filepath.ToSlash(rel)+"="+m.Path)
+		}
+		if strings.Join(mods, " ") != strings.Join(want, " ") {
+			t.Fatalf("mods: %v, want %v", mods, want)
+		}
+	}
+	checkMods(work, "a=example.com/a")
+
+	if err = work.Use([]string{filepath.Join(root, "b")}, true); err != nil {
+		t.Fatal("Use recursive:", err)
+	}
+	checkMods(work, "a=example.com/a", "b=example.com/b", "b/c=example.com/b/c")
+	if err = work.Use([]string{filepath.Join(root, "d")}, false); err == nil {
+		t.Fatal("Use(d): no error")
+	}
+
+	// a directory without go.mod is dropped from the workspace
+	if err = os.Remove(filepath.Join(root, "b", "c", "go.mod")); err != nil {
+		t.Fatal(err)
+	}
+	if err = work.Use([]string{filepath.Join(root, "b", "c")}, false); err != nil {
+		t.Fatal("Use(DropUse):", err)
+	}
+	checkMods(work, "a=example.com/a", "b=example.com/b")
+	if err = work.Save(); err != nil {
+		t.Fatal("Save:", err)
+	}
+
+	loaded, err := LoadWork(filepath.Join(root, "a"))
+	if err != nil {
+		t.Fatal("LoadWork:", err)
+	}
+	checkMods(loaded, "a=example.com/a", "b=example.com/b")
+	data, _ := os.ReadFile(filepath.Join(root, GoWorkFile))
+	if s := string(data); !strings.Contains(s, "./a") || !strings.Contains(s, "./b") || strings.Contains(s, "./b/c") {
+		t.Fatal("go.work:", s)
+	}
+	if !loaded.contains(filepath.Join(root, "b", "c")) || loaded.contains(filepath.Join(root, "d")) {
+		t.Fatal("contains")
+	}
+}
+
+func TestWorkApply(t *testing.T) {
+	t.Setenv("GOWORK", "")
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.work":  "go 1.21\n\nuse (\n\t./a\n\t./b\n)\n",
+		"a/go.mod": "module example.com/a\n\ngo 1.21\n\nrequire example.com/b v1.0.0\n",
+		"b/go.mod": "module example.com/b\n\ngo 1.21\n",
+	})
+	work, err := LoadWork(filepath.Join(root, "a"))
+	if err != nil {
+		t.Fatal("LoadWork:", err)
+	}
+	mod, err := xgomod.Load(filepath.Join(root, "a"))
+	if err != nil {
+		t.Fatal("xgomod.Load:", err)
+	}
+	work.apply(mod)
+	deps := mod.DepMods()
+	if v := deps["example.com/b"]; v != (module.Version{Path: filepath.Join(root, "b")}) {
+		t.Fatal("apply: example.com/b =", v)
+	}
+	if _, ok := deps["example.com/a"]; ok {
+		t.Fatal("apply: the module itself is added")
+	}
+
+	var none *Workspace
+	none.apply(mod) // no workspace
+	none.apply(xgomod.Default)
+}
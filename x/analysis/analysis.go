@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package analysis defines the interface between a modular static analysis
+// of XGo code and an analysis driver (see `xgo vet`). It's modelled on
+// golang.org/x/tools/go/analysis, but an analyzer inspects XGo syntax trees
+// and type information recorded by x/typesutil.
+package analysis
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/types"
+	"unicode"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgo/x/typesutil"
+)
+
+// -----------------------------------------------------------------------------
+
+// An Analyzer describes an analysis function and its options.
+type Analyzer struct {
+	// Name of the analyzer. It must be a valid identifier, and is used as the
+	// name of the flag to enable or disable it.
+	Name string
+
+	// Doc is the documentation of the analyzer. The first line is a summary.
+	Doc string
+
+	// Flags defines any flags accepted by the analyzer. The driver exposes
+	// them as -Name.flag.
+	Flags flag.FlagSet
+
+	// Run applies the analyzer to a package. It returns an error if the
+	// analyzer failed, or a result used by analyzers which require it.
+	Run func(pass *Pass) (any, error)
+
+	// Requires is a set of analyzers that must run before this one. Their
+	// results are available in Pass.ResultOf.
+	Requires []*Analyzer
+}
+
+func (a *Analyzer) String() string {
+	return a.Name
+}
+
+// A Pass provides information to the Run function that applies an analyzer
+// to a package.
+type Pass struct {
+	Analyzer *Analyzer // the identity of the current analyzer
+
+	Fset      *token.FileSet  // file position information
+	Files     []*ast.File     // the XGo syntax trees of the package
+	Pkg       *types.Package  // type information about the package
+	TypesInfo *typesutil.Info // type information about the syntax trees
+
+	// IsTest reports whether f is a test file (eg. foo_test.xgo, foo_test.gox).
+	IsTest func(f *ast.File) bool
+
+	// ResultOf provides the results of the analyzers required by this one.
+	ResultOf map[*Analyzer]any
+
+	// Report reports a diagnostic about the package.
+	Report func(Diagnostic)
+}
+
+// Reportf reports a diagnostic at pos with a formatted message.
+func (p *Pass) Reportf(pos token.Pos, format string, args ...any) {
+	p.Report(Diagnostic{Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+// ReportRangef reports a diagnostic about node with a formatted message.
+func (p *Pass) ReportRangef(node ast.Node, format string, args ...any) {
+	p.Report(Diagnostic{Pos: node.Pos(), End: node.End(), Message: fmt.Sprintf(format, args...)})
+}
+
+// A Diagnostic is a message associated with a source location or range.
+type Diagnostic struct {
+	Pos      token.Pos
+	End      token.Pos // optional
+	Category string    // optional
+	Message  string
+}
+
+// -----------------------------------------------------------------------------
+
+// Validate reports an error if any of the analyzers is misconfigured: it has
+// no Run function, its name isn't a valid identifier or is used by another
+// analyzer, or its Requires graph has a cycle.
+func Validate(analyzers []*Analyzer) error {
+	names := make(map[string]*Analyzer)
+	const (
+		white = iota
+		grey
+		black
+	)
+	color := make(map[*Analyzer]int)
+	var visit func(a *Analyzer) error
+	visit = func(a *Analyzer) error {
+		switch color[a] {
+		case black:
+			return nil
+		case grey:
+			return fmt.Errorf("cycle detected involving analyzer %s", a.Name)
+		}
+		color[a] = grey
+		if !validIdent(a.Name) {
+			return fmt.Errorf("invalid analyzer name %q", a.Name)
+		}
+		if a.Run == nil {
+			return fmt.Errorf("analyzer %s has nil Run", a.Name)
+		}
+		if a.Doc == "" {
+			return fmt.Errorf("analyzer %s is undocumented", a.Name)
+		}
+		if prev, ok := names[a.Name]; ok && prev != a {
+			return fmt.Errorf("duplicate analyzer name %q", a.Name)
+		}
+		names[a.Name] = a
+		for _, req := range a.Requires {
+			if err := visit(req); err != nil {
+				return err
+			}
+		}
+		color[a] = black
+		return nil
+	}
+	for _, a := range analyzers {
+		if a == nil {
+			return errors.New("nil analyzer")
+		}
+		if err := visit(a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validIdent(name string) bool {
+	for i, r := range name {
+		if !(r == '_' || unicode.IsLetter(r) || i > 0 && unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,39 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goplus/xgo/x/analysis"
+)
+
+func run(*analysis.Pass) (any, error) { return nil, nil }
+
+func TestValidate(t *testing.T) {
+	a := &analysis.Analyzer{Name: "a", Doc: "a", Run: run}
+	b := &analysis.Analyzer{Name: "b", Doc: "b", Run: run, Requires: []*analysis.Analyzer{a}}
+	if err := analysis.Validate([]*analysis.Analyzer{a, b}); err != nil {
+		t.Fatal("Validate:", err)
+	}
+
+	cycle := &analysis.Analyzer{Name: "cycle", Doc: "cycle", Run: run}
+	cycle.Requires = []*analysis.Analyzer{{Name: "c2", Doc: "c2", Run: run, Requires: []*analysis.Analyzer{cycle}}}
+	cases := []struct {
+		analyzers []*analysis.Analyzer
+		err       string
+	}{
+		{[]*analysis.Analyzer{{Name: "a.b", Doc: "x", Run: run}}, `invalid analyzer name "a.b"`},
+		{[]*analysis.Analyzer{{Name: "1a", Doc: "x", Run: run}}, `invalid analyzer name "1a"`},
+		{[]*analysis.Analyzer{{Name: "norun", Doc: "x"}}, "analyzer norun has nil Run"},
+		{[]*analysis.Analyzer{{Name: "nodoc", Run: run}}, "analyzer nodoc is undocumented"},
+		{[]*analysis.Analyzer{a, {Name: "a", Doc: "a", Run: run}}, `duplicate analyzer name "a"`},
+		{[]*analysis.Analyzer{cycle}, "cycle detected involving analyzer cycle"},
+		{[]*analysis.Analyzer{nil}, "nil analyzer"},
+	}
+	for _, c := range cases {
+		err := analysis.Validate(c.analyzers)
+		if err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("Validate: got %v, want %q", err, c.err)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package analysistest provides utilities for testing analyzers.
+package analysistest
+
+import (
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/goplus/mod/xgomod"
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/parser"
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgo/tool"
+	"github.com/goplus/xgo/x/analysis"
+	"github.com/goplus/xgo/x/typesutil"
+	"github.com/goplus/xgo/x/xgoenv"
+)
+
+// A File is a source file of the package to be analyzed.
+type File struct {
+	Name string // eg. foo.xgo, foo_test.xgo, Rect.gox
+	Src  string
+}
+
+// Run type checks files as package pkgPath, applies analyzer a0 to it, and
+// checks that the diagnostics reported by a0 match the expectations written in
+// comments of the form
+//
+//	// want "regexp"
+//
+// A diagnostic is expected on each line having such a comment, and its
+// message must match the regular expression. Run returns the diagnostics.
+func Run(t *testing.T, a0 *analysis.Analyzer, pkgPath string, files ...File) []analysis.Diagnostic {
+	t.Helper()
+	if err := analysis.Validate([]*analysis.Analyzer{a0}); err != nil {
+		t.Fatal(err)
+	}
+	if os.Getenv("XGOROOT") == "" {
+		if dir, err := os.Getwd(); err == nil {
+			setXGoRoot(dir)
+		}
+	}
+
+	fset := token.NewFileSet()
+	var xfiles []*ast.File
+	var wants []*want
+	for _, file := range files {
+		mode := parser.ParseComments
+		if filepath.Ext(file.Name) == ".gox" {
+			mode |= parser.ParseXGoClass
+		}
+		f, err := parser.ParseFile(fset, file.Name, file.Src, mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		xfiles = append(xfiles, f)
+		wants = append(wants, parseWants(t, fset, f)...)
+	}
+
+	name := pkgPath[strings.LastIndex(pkgPath, "/")+1:]
+	if len(xfiles) > 0 && xfiles[0].Name != nil {
+		name = xfiles[0].Name.Name
+	}
+	pkg := types.NewPackage(pkgPath, name)
+	info := &typesutil.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Implicits:  make(map[ast.Node]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:     make(map[ast.Node]*types.Scope),
+		Overloads:  make(map[*ast.Ident]types.Object),
+	}
+	conf := &types.Config{Importer: tool.NewImporter(nil, xgoenv.Get(), fset)}
+	chk := typesutil.NewChecker(conf, &typesutil.Config{
+		Types: pkg,
+		Fset:  fset,
+		Mod:   xgomod.Default,
+	}, nil, info)
+	if err := chk.Files(nil, xfiles); err != nil {
+		t.Fatal("type check:", err)
+	}
+
+	var diags []analysis.Diagnostic
+	results := make(map[*analysis.Analyzer]any)
+	var run func(a *analysis.Analyzer) any
+	run = func(a *analysis.Analyzer) any {
+		if ret, ok := results[a]; ok {
+			return ret
+		}
+		pass := &analysis.Pass{
+			Analyzer:  a,
+			Fset:      fset,
+			Files:     xfiles,
+			Pkg:       pkg,
+			TypesInfo: info,
+			IsTest:    func(f *ast.File) bool { return isTest(fset, f) },
+			ResultOf:  make(map[*analysis.Analyzer]any),
+		}
+		for _, req := range a.Requires {
+			pass.ResultOf[req] = run(req)
+		}
+		pass.Report = func(d analysis.Diagnostic) {
+			if pass.Analyzer == a0 {
+				diags = append(diags, d)
+			}
+		}
+		ret, err := a.Run(pass)
+		if err != nil {
+			t.Fatalf("%s: %v", a.Name, err)
+		}
+		results[a] = ret
+		return ret
+	}
+	run(a0)
+
+	sort.Slice(diags, func(i, j int) bool { return diags[i].Pos < diags[j].Pos })
+	for _, d := range diags {
+		posn := fset.Position(d.Pos)
+		if !match(wants, posn, d.Message) {
+			t.Errorf("%v: unexpected diagnostic: %s", posn, d.Message)
+		}
+	}
+	for _, w := range wants {
+		if !w.matched {
+			t.Errorf("%s:%d: no diagnostic was reported matching %q", w.file, w.line, w.rx)
+		}
+	}
+	return diags
+}
+
+type want struct {
+	file    string
+	line    int
+	rx      *regexp.Regexp
+	matched bool
+}
+
+var wantRE = regexp.MustCompile(`^//\s*want\s+(.*)$`)
+
+func parseWants(t *testing.T, fset *token.FileSet, f *ast.File) (ret []*want) {
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			m := wantRE.FindStringSubmatch(c.Text)
+			if m == nil {
+				continue
+			}
+			posn := fset.Position(c.Pos())
+			rest := strings.TrimSpace(m[1])
+			for rest != "" {
+				lit, err := strconv.QuotedPrefix(rest)
+				if err != nil {
+					t.Fatalf("%v: invalid want comment: %v", posn, err)
+				}
+				s, _ := strconv.Unquote(lit)
+				rx, err := regexp.Compile(s)
+				if err != nil {
+					t.Fatalf("%v: %v", posn, err)
+				}
+				ret = append(ret, &want{file: posn.Filename, line: posn.Line, rx: rx})
+				rest = strings.TrimSpace(rest[len(lit):])
+			}
+		}
+	}
+	return
+}
+
+func match(wants []*want, posn token.Position, msg string) bool {
+	for _, w := range wants {
+		if !w.matched && w.file == posn.Filename && w.line == posn.Line && w.rx.MatchString(msg) {
+			w.matched = true
+			return true
+		}
+	}
+	return false
+}
+
+func isTest(fset *token.FileSet, f *ast.File) bool {
+	fname := filepath.Base(fset.Position(f.Pos()).Filename)
+	if pos := strings.Index(fname, "."); pos > 0 {
+		fname = fname[:pos]
+	}
+	return strings.HasSuffix(fname, "_test")
+}
+
+// setXGoRoot sets XGOROOT to the first parent directory of dir which is the
+// root of the XGo repository.
+func setXGoRoot(dir string) {
+	for {
+		if b, err := os.ReadFile(filepath.Join(dir, "go.mod")); err == nil &&
+			strings.HasPrefix(string(b), "module github.com/goplus/xgo\n") {
+			os.Setenv("XGOROOT", dir)
+			return
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			fmt.Fprintln(os.Stderr, "analysistest: XGOROOT not found")
+			return
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,269 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package checker defines the driver which runs analyzers on XGo packages,
+// as `xgo vet` does. It can also be used to build a standalone vet tool
+// with third-party analyzers, see Main.
+package checker
+
+import (
+	"fmt"
+	goast "go/ast"
+	"go/types"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/parser"
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgo/tool"
+	"github.com/goplus/xgo/x/analysis"
+	"github.com/goplus/xgo/x/typesutil"
+	"github.com/goplus/xgo/x/xgoenv"
+)
+
+// -----------------------------------------------------------------------------
+
+// A Diagnostic is a diagnostic reported by an analyzer.
+type Diagnostic struct {
+	Analyzer string // name of the analyzer
+	Posn     token.Position
+	End      token.Position // optional
+	Category string         // optional
+	Message  string
+}
+
+// A Package is the result of analyzing an XGo package.
+type Package struct {
+	ImportPath  string
+	Dir         string
+	Diagnostics []*Diagnostic
+	Errors      []error // errors of loading or type checking the package
+}
+
+// Run loads the XGo packages matched by patterns (see tool.ListPackages),
+// type checks them and applies analyzers to them. Packages without XGo
+// files are skipped. A package having errors isn't analyzed.
+func Run(patterns []string, conf *tool.Config, analyzers []*analysis.Analyzer) (pkgs []*Package, err error) {
+	if err = analysis.Validate(analyzers); err != nil {
+		return
+	}
+	if conf == nil {
+		conf = new(tool.Config)
+	}
+	if conf.Mod == nil {
+		if conf.Mod, err = tool.LoadMod("."); err != nil {
+			return
+		}
+	}
+	if conf.Fset == nil {
+		conf.Fset = token.NewFileSet()
+	}
+	if conf.XGo == nil {
+		conf.XGo = xgoenv.Get()
+	}
+	if conf.Importer == nil {
+		conf.Importer = tool.NewImporter(conf.Mod, conf.XGo, conf.Fset)
+	}
+	list, err := tool.ListPackages(patterns, conf, false)
+	if err != nil {
+		return
+	}
+	for _, p := range list {
+		if len(p.XGoFiles)+len(p.ClassFiles)+len(p.TestXGoFiles) == 0 {
+			continue
+		}
+		pkgs = append(pkgs, checkDir(p.ImportPath, p.Dir, conf, analyzers)...)
+	}
+	return
+}
+
+// checkDir analyzes the package in dir, and its external test package if
+// any.
+func checkDir(pkgPath, dir string, conf *tool.Config, analyzers []*analysis.Analyzer) []*Package {
+	fset := conf.Fset
+	mod := conf.Mod
+	pkgs, err := parser.ParseDirEx(fset, dir, parser.Config{
+		ClassKind: mod.ClassKind,
+		Filter:    conf.Filter,
+		Mode:      parser.ParseComments,
+	})
+	if err != nil {
+		return []*Package{{ImportPath: pkgPath, Dir: dir, Errors: []error{err}}}
+	}
+	names := make([]string, 0, len(pkgs))
+	for name := range pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var mains []string
+	for _, name := range names {
+		if !strings.HasSuffix(name, "_test") {
+			mains = append(mains, name)
+		}
+	}
+	if len(mains) > 1 {
+		err = fmt.Errorf("%w: %s", tool.ErrMultiPackges, strings.Join(mains, ", "))
+		return []*Package{{ImportPath: pkgPath, Dir: dir, Errors: []error{err}}}
+	}
+
+	var ret []*Package
+	for _, name := range names {
+		pkg := pkgs[name]
+		if len(pkg.Files) == 0 {
+			continue
+		}
+		path := pkgPath
+		if strings.HasSuffix(name, "_test") {
+			path += "_test"
+		}
+		ret = append(ret, checkPkg(path, dir, name, pkg, conf, analyzers))
+	}
+	return ret
+}
+
+func checkPkg(pkgPath, dir, name string, pkg *ast.Package, conf *tool.Config, analyzers []*analysis.Analyzer) *Package {
+	ret := &Package{ImportPath: pkgPath, Dir: dir}
+	fset := conf.Fset
+	files := sortedFiles(pkg.Files)
+	gofiles := sortedFiles(pkg.GoFiles)
+
+	isTest := make(map[*ast.File]bool)
+	for _, f := range files {
+		fname := filepath.Base(fset.Position(f.Pos()).Filename)
+		_, test := tool.GetFileClassType(conf.Mod, f, fname)
+		isTest[f] = test || strings.HasSuffix(name, "_test")
+	}
+
+	pkgTypes := types.NewPackage(pkgPath, name)
+	info := newInfo()
+	chkConf := &types.Config{
+		Importer: conf.Importer,
+		Error: func(err error) {
+			ret.Errors = append(ret.Errors, err)
+		},
+	}
+	chk := typesutil.NewChecker(chkConf, &typesutil.Config{
+		Types:      pkgTypes,
+		Fset:       fset,
+		WorkingDir: dir,
+		Mod:        conf.Mod,
+	}, nil, info)
+	if err := chk.Files(gofiles, files); err != nil && len(ret.Errors) == 0 {
+		ret.Errors = append(ret.Errors, err)
+	}
+	if len(ret.Errors) > 0 {
+		return ret
+	}
+
+	results := make(map[*analysis.Analyzer]any)
+	succeeded := make(map[*analysis.Analyzer]bool)
+	selected := make(map[*analysis.Analyzer]bool, len(analyzers))
+	for _, a := range analyzers {
+		selected[a] = true
+	}
+	var run func(a *analysis.Analyzer) bool
+	run = func(a *analysis.Analyzer) bool {
+		if ok, done := succeeded[a]; done {
+			return ok
+		}
+		succeeded[a] = false
+		pass := &analysis.Pass{
+			Analyzer:  a,
+			Fset:      fset,
+			Files:     files,
+			Pkg:       pkgTypes,
+			TypesInfo: info,
+			IsTest:    func(f *ast.File) bool { return isTest[f] },
+			ResultOf:  make(map[*analysis.Analyzer]any),
+		}
+		for _, req := range a.Requires {
+			if !run(req) {
+				return false
+			}
+			pass.ResultOf[req] = results[req]
+		}
+		pass.Report = func(d analysis.Diagnostic) {
+			if selected[a] {
+				ret.Diagnostics = append(ret.Diagnostics, &Diagnostic{
+					Analyzer: a.Name,
+					Posn:     fset.Position(d.Pos),
+					End:      position(fset, d.End),
+					Category: d.Category,
+					Message:  d.Message,
+				})
+			}
+		}
+		result, err := a.Run(pass)
+		if err != nil {
+			ret.Errors = append(ret.Errors, fmt.Errorf("analyzer %s: %w", a.Name, err))
+			return false
+		}
+		results[a] = result
+		succeeded[a] = true
+		return true
+	}
+	for _, a := range analyzers {
+		run(a)
+	}
+	sort.SliceStable(ret.Diagnostics, func(i, j int) bool {
+		a, b := ret.Diagnostics[i].Posn, ret.Diagnostics[j].Posn
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return ret
+}
+
+func position(fset *token.FileSet, pos token.Pos) token.Position {
+	if pos == token.NoPos {
+		return token.Position{}
+	}
+	return fset.Position(pos)
+}
+
+func newInfo() *typesutil.Info {
+	return &typesutil.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Instances:  make(map[*ast.Ident]types.Instance),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Implicits:  make(map[ast.Node]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:     make(map[ast.Node]*types.Scope),
+		Overloads:  make(map[*ast.Ident]types.Object),
+	}
+}
+
+func sortedFiles[F *ast.File | *goast.File](m map[string]F) []F {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	files := make([]F, len(names))
+	for i, name := range names {
+		files[i] = m[name]
+	}
+	return files
+}
+
+// -----------------------------------------------------------------------------
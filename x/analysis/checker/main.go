@@ -0,0 +1,174 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/goplus/xgo/tool"
+	"github.com/goplus/xgo/x/analysis"
+)
+
+// -----------------------------------------------------------------------------
+
+// RegisterFlags defines flags of analyzers in fs: a boolean flag -NAME for
+// each analyzer to enable or disable it, and a flag -NAME.FLAG for each flag
+// of its Flags. The returned function reports the analyzers to run after fs
+// is parsed: if any analyzer is enabled explicitly, only enabled analyzers
+// run; otherwise all analyzers but the disabled ones run.
+func RegisterFlags(fs *flag.FlagSet, analyzers []*analysis.Analyzer) func() []*analysis.Analyzer {
+	enabled := make([]*triState, len(analyzers))
+	for i, a := range analyzers {
+		enabled[i] = new(triState)
+		fs.Var(enabled[i], a.Name, "enable "+a.Name+" analysis")
+		a.Flags.VisitAll(func(f *flag.Flag) {
+			fs.Var(f.Value, a.Name+"."+f.Name, f.Usage)
+		})
+	}
+	return func() (ret []*analysis.Analyzer) {
+		hasTrue := false
+		for _, v := range enabled {
+			if *v == setTrue {
+				hasTrue = true
+			}
+		}
+		for i, a := range analyzers {
+			if v := *enabled[i]; v == setTrue || !hasTrue && v != setFalse {
+				ret = append(ret, a)
+			}
+		}
+		return
+	}
+}
+
+// triState is a boolean flag which records whether it is set.
+type triState int
+
+const (
+	unset triState = iota
+	setTrue
+	setFalse
+)
+
+func (ts *triState) IsBoolFlag() bool { return true }
+
+func (ts *triState) String() string {
+	switch *ts {
+	case setTrue:
+		return "true"
+	case setFalse:
+		return "false"
+	}
+	return "unset"
+}
+
+func (ts *triState) Set(value string) error {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	if b {
+		*ts = setTrue
+	} else {
+		*ts = setFalse
+	}
+	return nil
+}
+
+// PrintAnalyzers prints the name and summary of analyzers to w.
+func PrintAnalyzers(w io.Writer, analyzers []*analysis.Analyzer) {
+	for _, a := range analyzers {
+		summary, _, _ := strings.Cut(a.Doc, "\n")
+		fmt.Fprintf(w, "    %-14s %s\n", a.Name, summary)
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+// Main is the main function of a vet tool which runs analyzers, including
+// third-party ones, on XGo packages:
+//
+//	func main() {
+//		checker.Main(append(passes.All, myanalyzer.Analyzer)...)
+//	}
+//
+// The tool accepts the same command line as `xgo vet`, which can run it by
+// `xgo vet -vettool=prog [packages]`. It exits with status 1 if there are
+// any problems.
+func Main(analyzers ...*analysis.Analyzer) {
+	progname := filepath.Base(os.Args[0])
+	log.SetFlags(0)
+	log.SetPrefix(progname + ": ")
+	if err := analysis.Validate(analyzers); err != nil {
+		log.Fatal(err)
+	}
+
+	fs := flag.NewFlagSet(progname, flag.ExitOnError)
+	jsonOut := fs.Bool("json", false, "emit diagnostics in JSON format")
+	tags := fs.String("tags", "", "a comma-separated list of build tags")
+	selected := RegisterFlags(fs, analyzers)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [-json -tags tags] [-analyzer flags] [packages]\n\nRegistered analyzers:\n\n", progname)
+		PrintAnalyzers(os.Stderr, analyzers)
+		fmt.Fprintln(os.Stderr, "\nFlags:")
+		fs.PrintDefaults()
+	}
+	fs.Parse(os.Args[1:])
+
+	os.Exit(Vet(fs.Args(), *tags, *jsonOut, selected()))
+}
+
+// Vet runs analyzers on the XGo packages matched by patterns and prints the
+// results, see Main. It returns the exit status: 1 if there are any problems
+// reported as text, 0 otherwise.
+func Vet(patterns []string, tags string, jsonOut bool, analyzers []*analysis.Analyzer) int {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+	var tagList []string
+	if tags != "" {
+		tagList = strings.Split(tags, ",")
+	}
+	conf, err := tool.NewDefaultConf(".", tool.ConfFlagNoGenCache, tagList...)
+	if err != nil {
+		log.Println(err)
+		return 1
+	}
+	pkgs, err := Run(patterns, conf, analyzers)
+	conf.UpdateCache()
+	if err != nil {
+		log.Println(err)
+		return 1
+	}
+	if jsonOut {
+		Print(os.Stdout, conf.Fset, pkgs, true)
+		return 0
+	}
+	if Print(os.Stderr, conf.Fset, pkgs, false) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,85 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgo/tool"
+)
+
+// -----------------------------------------------------------------------------
+
+// Print prints errors and diagnostics of pkgs to w, and returns the number
+// of them. By default it prints them as text, like `go vet`: a line for each
+// of them, following a "# importPath" header line of each package having
+// any. If jsonOut is true, it prints them as a stream of JSON objects, one
+// per line, in the format of tool.Diagnostic (a diagnostic has the warning
+// severity and its analyzer name as codeName).
+func Print(w io.Writer, fset *token.FileSet, pkgs []*Package, jsonOut bool) (n int) {
+	enc := json.NewEncoder(w)
+	for _, pkg := range pkgs {
+		if len(pkg.Errors)+len(pkg.Diagnostics) == 0 {
+			continue
+		}
+		if !jsonOut {
+			fmt.Fprintln(w, "#", pkg.ImportPath)
+		}
+		for _, err := range pkg.Errors {
+			n++
+			if jsonOut {
+				for _, d := range tool.Diagnostics(fset, err) {
+					enc.Encode(d)
+				}
+			} else {
+				fmt.Fprintln(w, err)
+			}
+		}
+		for _, d := range pkg.Diagnostics {
+			n++
+			if jsonOut {
+				enc.Encode(&tool.Diagnostic{
+					File: d.Posn.Filename, Line: d.Posn.Line, Column: d.Posn.Column,
+					EndLine: d.End.Line, EndColumn: d.End.Column,
+					Severity: tool.SeverityWarning, CodeName: d.Analyzer, Message: d.Message,
+				})
+			} else {
+				fmt.Fprintf(w, "%s: %s\n", relPosn(d.Posn), d.Message)
+			}
+		}
+	}
+	return
+}
+
+// relPosn returns posn as a string, with its filename relative to the
+// current directory if possible.
+func relPosn(posn token.Position) string {
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, posn.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			posn.Filename = "." + string(filepath.Separator) + rel
+		}
+	}
+	return posn.String()
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,83 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package bang defines an Analyzer that checks for uses of the ! operator
+// in library code.
+package bang
+
+import (
+	"strings"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgo/x/analysis"
+	"github.com/goplus/xgo/x/analysis/passes/internal/analysisutil"
+)
+
+const Doc = `check for suspicious uses of ! in library code
+
+An expression such as
+
+	n := strconv.atoi(s)!
+
+panics if strconv.Atoi fails. It's handy in scripts and commands, but
+a package which isn't main shouldn't crash its importers: a function
+returning an error should use ? to pass the error to its caller.
+
+Test files, package-level variables, init functions and functions whose
+names start with Must (or must) are allowed to use !.`
+
+// Analyzer checks for uses of the ! operator in library code.
+var Analyzer = &analysis.Analyzer{
+	Name: "bang",
+	Doc:  Doc,
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	if pass.Pkg.Name() == "main" {
+		return nil, nil
+	}
+	info := pass.TypesInfo
+	for _, f := range pass.Files {
+		if pass.IsTest(f) {
+			continue
+		}
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.FuncDecl)
+			if !ok || d.Body == nil || allowed(d.Name.Name) {
+				continue
+			}
+			analysisutil.Inspect(d, func(n ast.Node, stack []ast.Node) bool {
+				x, ok := n.(*ast.ErrWrapExpr)
+				if !ok || x.Tok != token.NOT {
+					return true
+				}
+				if _, sig := analysisutil.EnclosingFunc(info, stack); analysisutil.ReturnsError(sig) {
+					pass.Reportf(x.TokPos, "! panics on error in library code; use ? to return the error instead")
+				} else {
+					pass.Reportf(x.TokPos, "! panics on error in library code")
+				}
+				return true
+			})
+		}
+	}
+	return nil, nil
+}
+
+func allowed(name string) bool {
+	return name == "init" || strings.HasPrefix(name, "Must") || strings.HasPrefix(name, "must")
+}
@@ -0,0 +1,49 @@
+package bang_test
+
+import (
+	"testing"
+
+	"github.com/goplus/xgo/x/analysis/analysistest"
+	"github.com/goplus/xgo/x/analysis/passes/bang"
+)
+
+func TestBang(t *testing.T) {
+	analysistest.Run(t, bang.Analyzer, "example.com/a", analysistest.File{Name: "a.xgo", Src: `package a
+
+import "strconv"
+
+var base = strconv.atoi("10")!
+
+func Parse(s string) (int, error) {
+	n := strconv.atoi(s)! // want "! panics on error in library code; use \\? to return the error instead"
+	return n + base, nil
+}
+
+func Print(s string) {
+	echo strconv.atoi(s)! // want "! panics on error in library code$"
+}
+
+func MustParse(s string) int {
+	return strconv.atoi(s)!
+}
+
+func init() {
+	base = strconv.atoi("16")!
+}
+`}, analysistest.File{Name: "a_test.xgo", Src: `package a
+
+import "strconv"
+
+func check(s string) int {
+	return strconv.atoi(s)!
+}
+`})
+}
+
+func TestBangMain(t *testing.T) {
+	analysistest.Run(t, bang.Analyzer, "example.com/cmd", analysistest.File{Name: "main.xgo", Src: `
+import "strconv"
+
+echo strconv.atoi("10")!
+`})
+}
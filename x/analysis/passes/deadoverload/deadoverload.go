@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package deadoverload defines an Analyzer that checks for members of an
+// overload function that can never be selected.
+package deadoverload
+
+import (
+	"go/types"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/x/analysis"
+)
+
+const Doc = `check for overloads that are never selected
+
+The members of an overload function
+
+	func show = (
+		func(v any) { ... }
+		func(v int) { ... }
+	)
+
+are tried in order, and the first one accepting the arguments of a call
+is selected. A member whose parameters are all assignable to those of an
+earlier member with the same arity is shadowed by it: no call can ever
+select it. Here show(1) calls the first member, so the second one is
+reported.`
+
+// Analyzer checks for members of an overload function that can never be
+// selected.
+var Analyzer = &analysis.Analyzer{
+	Name: "deadoverload",
+	Doc:  Doc,
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	info := pass.TypesInfo
+	for _, f := range pass.Files {
+		for _, decl := range f.Decls {
+			d, ok := decl.(*ast.OverloadFuncDecl)
+			if !ok {
+				continue
+			}
+			sigs := make([]*types.Signature, len(d.Funcs))
+			for i, fn := range d.Funcs {
+				sigs[i], _ = info.TypeOf(fn).(*types.Signature)
+			}
+			for j := 1; j < len(sigs); j++ {
+				for i := 0; i < j; i++ {
+					if covers(sigs[i], sigs[j]) {
+						pass.ReportRangef(d.Funcs[j], "overload #%d of %s is never selected: calls matching it match overload #%d first",
+							j+1, d.Name.Name, i+1)
+						break
+					}
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// covers reports whether any call accepted by sig is also accepted by prev.
+func covers(prev, sig *types.Signature) bool {
+	if prev == nil || sig == nil || prev.Variadic() != sig.Variadic() {
+		return false
+	}
+	p, q := prev.Params(), sig.Params()
+	if p.Len() != q.Len() {
+		return false
+	}
+	for i := 0; i < q.Len(); i++ {
+		if !types.AssignableTo(q.At(i).Type(), p.At(i).Type()) {
+			return false
+		}
+	}
+	return true
+}
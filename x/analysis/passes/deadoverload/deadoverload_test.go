@@ -0,0 +1,42 @@
+package deadoverload_test
+
+import (
+	"testing"
+
+	"github.com/goplus/xgo/x/analysis/analysistest"
+	"github.com/goplus/xgo/x/analysis/passes/deadoverload"
+)
+
+func TestDeadOverload(t *testing.T) {
+	analysistest.Run(t, deadoverload.Analyzer, "example.com/a", analysistest.File{Name: "a.xgo", Src: `package a
+
+func add = (
+	func(a, b int) int {
+		return a + b
+	}
+	func(a, b float64) float64 {
+		return a + b
+	}
+	func(a, b int) int { // want "overload #3 of add is never selected: calls matching it match overload #1 first"
+		return b + a
+	}
+	addStr
+)
+
+func addStr(a, b string) string {
+	return a + b
+}
+
+func show = (
+	func(v any) {
+		echo v
+	}
+	func(s string) { // want "overload #2 of show is never selected"
+		echo s
+	}
+	func(a, b string) {
+		echo a, b
+	}
+)
+`})
+}
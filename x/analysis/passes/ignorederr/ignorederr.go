@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package ignorederr defines an Analyzer that checks for errors which are
+// silently dropped by functions able to return them with the ? operator.
+package ignorederr
+
+import (
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/x/analysis"
+	"github.com/goplus/xgo/x/analysis/passes/internal/analysisutil"
+)
+
+const Doc = `check for ignored errors that could be returned with ?
+
+In a function whose last result is an error, a call such as
+
+	os.remove name
+
+used as a statement drops the error it returns. Writing
+
+	os.remove(name)?
+
+returns the error to the caller instead. Calls to functions which are
+commonly used without checking their errors, such as fmt.Println (echo)
+and writes to bytes.Buffer or strings.Builder, aren't reported.`
+
+// Analyzer checks for ignored errors that could be returned with ?.
+var Analyzer = &analysis.Analyzer{
+	Name: "ignorederr",
+	Doc:  Doc,
+	Run:  run,
+}
+
+var exempt = map[string]bool{
+	"fmt.Print":    true,
+	"fmt.Printf":   true,
+	"fmt.Println":  true,
+	"fmt.Fprint":   true,
+	"fmt.Fprintf":  true,
+	"fmt.Fprintln": true,
+
+	"(*bytes.Buffer).Write":          true,
+	"(*bytes.Buffer).WriteByte":      true,
+	"(*bytes.Buffer).WriteRune":      true,
+	"(*bytes.Buffer).WriteString":    true,
+	"(*strings.Builder).Write":       true,
+	"(*strings.Builder).WriteByte":   true,
+	"(*strings.Builder).WriteRune":   true,
+	"(*strings.Builder).WriteString": true,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	info := pass.TypesInfo
+	for _, f := range pass.Files {
+		analysisutil.Inspect(f, func(n ast.Node, stack []ast.Node) bool {
+			stmt, ok := n.(*ast.ExprStmt)
+			if !ok {
+				return true
+			}
+			call, ok := analysisutil.Unparen(stmt.X).(*ast.CallExpr)
+			if !ok || !analysisutil.LastIsError(info.TypeOf(call)) {
+				return true
+			}
+			if _, sig := analysisutil.EnclosingFunc(info, stack); !analysisutil.ReturnsError(sig) {
+				return true
+			}
+			name := "call"
+			if fn := analysisutil.Callee(info, call); fn != nil {
+				if exempt[fn.FullName()] {
+					return true
+				}
+				name = fn.Name()
+			}
+			pass.ReportRangef(call, "error result of %s is ignored; use ? to return it", name)
+			return true
+		})
+	}
+	return nil, nil
+}
@@ -0,0 +1,48 @@
+package ignorederr_test
+
+import (
+	"testing"
+
+	"github.com/goplus/xgo/x/analysis/analysistest"
+	"github.com/goplus/xgo/x/analysis/passes/ignorederr"
+)
+
+func TestIgnoredErr(t *testing.T) {
+	analysistest.Run(t, ignorederr.Analyzer, "example.com/a", analysistest.File{Name: "a.xgo", Src: `package a
+
+import (
+	"os"
+	"strings"
+)
+
+func Clean(name string) error {
+	os.remove name // want "error result of Remove is ignored; use \\? to return it"
+	os.Chdir(name) // want "error result of Chdir is ignored"
+	os.remove(name)?
+	echo name
+	var b strings.Builder
+	b.writeString name
+	run func() {
+		os.remove name
+	}
+	try func() error { // want "error result of try is ignored"
+		os.remove name // want "Remove"
+		return nil
+	}
+	return nil
+}
+
+func run(fn func()) {
+	fn()
+}
+
+func try(fn func() error) error {
+	fn() // want "error result of call is ignored"
+	return nil
+}
+
+func NoError(name string) {
+	os.remove name
+}
+`})
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package analysisutil defines helpers shared by the analyzers in passes.
+package analysisutil
+
+import (
+	"go/types"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/x/typesutil"
+)
+
+// Inspect traverses the syntax tree of node like ast.Inspect, passing the
+// stack of enclosing nodes (including n itself) to f.
+func Inspect(node ast.Node, f func(n ast.Node, stack []ast.Node) bool) {
+	var stack []ast.Node
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			stack = stack[:len(stack)-1]
+			return true
+		}
+		stack = append(stack, n)
+		if !f(n, stack) {
+			stack = stack[:len(stack)-1]
+			return false
+		}
+		return true
+	})
+}
+
+// EnclosingFunc returns the innermost function (a *ast.FuncDecl,
+// *ast.FuncLit or *ast.LambdaExpr2) in stack and its signature.
+func EnclosingFunc(info *typesutil.Info, stack []ast.Node) (ast.Node, *types.Signature) {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch v := stack[i].(type) {
+		case *ast.FuncDecl:
+			if fn, ok := info.Defs[v.Name].(*types.Func); ok {
+				return v, fn.Type().(*types.Signature)
+			}
+			return v, nil
+		case *ast.FuncLit, *ast.LambdaExpr2:
+			sig, _ := info.TypeOf(v.(ast.Expr)).(*types.Signature)
+			return v, sig
+		}
+	}
+	return nil, nil
+}
+
+// Unparen returns x with any enclosing parentheses removed.
+func Unparen(x ast.Expr) ast.Expr {
+	for {
+		p, ok := x.(*ast.ParenExpr)
+		if !ok {
+			return x
+		}
+		x = p.X
+	}
+}
+
+// Callee returns the function called by call, or nil if it isn't a call
+// of a function or a method (eg. a conversion or a builtin).
+func Callee(info *typesutil.Info, call *ast.CallExpr) *types.Func {
+	var id *ast.Ident
+	switch fun := Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id = fun.Sel
+	default:
+		return nil
+	}
+	fn, _ := info.Uses[id].(*types.Func)
+	return fn
+}
+
+// LastIsError reports whether typ is error or a tuple whose last element
+// is an error.
+func LastIsError(typ types.Type) bool {
+	if t, ok := typ.(*types.Tuple); ok {
+		if t.Len() == 0 {
+			return false
+		}
+		typ = t.At(t.Len() - 1).Type()
+	}
+	return typ != nil && types.Identical(typ, errorType)
+}
+
+var errorType = types.Universe.Lookup("error").Type()
+
+// ReturnsError reports whether the last result of sig is an error.
+func ReturnsError(sig *types.Signature) bool {
+	return sig != nil && LastIsError(sig.Results())
+}
@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package lambdashadow defines an Analyzer that checks for lambda
+// parameters shadowing local variables.
+package lambdashadow
+
+import (
+	"go/types"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/x/analysis"
+)
+
+const Doc = `check for lambda parameters that shadow local variables
+
+A lambda such as
+
+	n := 0
+	forEach x => { n += x }
+	filter n => n > 0
+
+makes its parameter n hide the variable n of the enclosing function, so
+the body of the lambda can't refer to it any more. This is rarely what
+was meant, since a lambda parameter has no explicit declaration and its
+type is inferred from the context.`
+
+// Analyzer checks for lambda parameters shadowing local variables.
+var Analyzer = &analysis.Analyzer{
+	Name: "lambdashadow",
+	Doc:  Doc,
+	Run:  run,
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch v := n.(type) {
+			case *ast.LambdaExpr:
+				check(pass, v.Lhs)
+			case *ast.LambdaExpr2:
+				check(pass, v.Lhs)
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+func check(pass *analysis.Pass, params []*ast.Ident) {
+	pkgScope := pass.Pkg.Scope()
+	for _, id := range params {
+		if id.Name == "_" {
+			continue
+		}
+		obj, ok := pass.TypesInfo.Defs[id].(*types.Var)
+		if !ok || obj.Parent() == nil || obj.Parent().Parent() == nil {
+			continue
+		}
+		scope, prev := obj.Parent().Parent().LookupParent(id.Name, id.Pos())
+		if scope == nil || scope == pkgScope || scope == types.Universe {
+			continue
+		}
+		if v, ok := prev.(*types.Var); ok && !v.IsField() {
+			line := pass.Fset.Position(v.Pos()).Line
+			pass.Reportf(id.Pos(), "lambda parameter %s shadows declaration at line %d", id.Name, line)
+		}
+	}
+}
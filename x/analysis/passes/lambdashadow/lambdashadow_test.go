@@ -0,0 +1,32 @@
+package lambdashadow_test
+
+import (
+	"testing"
+
+	"github.com/goplus/xgo/x/analysis/analysistest"
+	"github.com/goplus/xgo/x/analysis/passes/lambdashadow"
+)
+
+func TestLambdaShadow(t *testing.T) {
+	analysistest.Run(t, lambdashadow.Analyzer, "example.com/a", analysistest.File{Name: "a.xgo", Src: `package a
+
+var global int
+
+func apply(fn func(int) int) {}
+
+func applyBlock(fn func(int, int) int) {}
+
+func Demo(x int) {
+	n := 0
+	apply n => n + 1 // want "lambda parameter n shadows declaration at line 10"
+	apply x => x * 2 // want "lambda parameter x shadows declaration at line 9"
+	applyBlock (a, n) => { // want "lambda parameter n shadows"
+		return a + n
+	}
+	apply global => global
+	apply v => v + n
+	apply _ => 0
+	echo n
+}
+`})
+}
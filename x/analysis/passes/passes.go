@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package passes lists the analyzers run by `xgo vet`. Each analyzer is
+// defined in a subpackage of passes.
+package passes
+
+import (
+	"github.com/goplus/xgo/x/analysis"
+	"github.com/goplus/xgo/x/analysis/passes/bang"
+	"github.com/goplus/xgo/x/analysis/passes/deadoverload"
+	"github.com/goplus/xgo/x/analysis/passes/ignorederr"
+	"github.com/goplus/xgo/x/analysis/passes/lambdashadow"
+	"github.com/goplus/xgo/x/analysis/passes/unusedresult"
+)
+
+// All is the list of analyzers run by `xgo vet` by default.
+var All = []*analysis.Analyzer{
+	bang.Analyzer,
+	deadoverload.Analyzer,
+	ignorederr.Analyzer,
+	lambdashadow.Analyzer,
+	unusedresult.Analyzer,
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package unusedresult defines an Analyzer that checks for unused results
+// of calls to certain pure functions.
+package unusedresult
+
+import (
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/x/analysis"
+	"github.com/goplus/xgo/x/analysis/passes/internal/analysisutil"
+)
+
+const Doc = `check for unused results of calls to some functions
+
+A command-style call, such as
+
+	strings.toUpper s
+
+reads like a statement that does something, but strings.ToUpper only
+returns a new string which is silently discarded. This checker reports
+calls used as statements, with or without parentheses, to functions
+whose only effect is their result. The set of functions may be changed
+by the -unusedresult.funcs flag, where pkg.* stands for all functions
+of package pkg.`
+
+// Analyzer checks for unused results of calls to certain pure functions.
+var Analyzer = &analysis.Analyzer{
+	Name: "unusedresult",
+	Doc:  Doc,
+	Run:  run,
+}
+
+var funcs = stringSet{}
+
+func init() {
+	funcs.Set("errors.New,fmt.Errorf,fmt.Sprint,fmt.Sprintf,fmt.Sprintln," +
+		"strings.*,bytes.*,strconv.*,unicode.*,math.*,path.*,slices.*,maps.*," +
+		"path/filepath.Abs,path/filepath.Base,path/filepath.Clean,path/filepath.Dir," +
+		"path/filepath.Ext,path/filepath.Join,path/filepath.Rel,path/filepath.Split")
+	Analyzer.Flags.Var(&funcs, "funcs",
+		"comma-separated list of functions whose results must be used (pkg.* matches all functions of pkg)")
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	info := pass.TypesInfo
+	for _, f := range pass.Files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			stmt, ok := n.(*ast.ExprStmt)
+			if !ok {
+				return true
+			}
+			call, ok := analysisutil.Unparen(stmt.X).(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			fn := analysisutil.Callee(info, call)
+			if fn == nil || fn.Type().(*types.Signature).Results().Len() == 0 || !funcs.match(fn) {
+				return true
+			}
+			if call.IsCommand() {
+				pass.ReportRangef(call, "result of %s call not used: a command-style call discards its result", fn.FullName())
+			} else {
+				pass.ReportRangef(call, "result of %s call not used", fn.FullName())
+			}
+			return true
+		})
+	}
+	return nil, nil
+}
+
+// stringSet is a set of function names which implements flag.Value.
+type stringSet map[string]bool
+
+func (ss stringSet) String() string {
+	items := make([]string, 0, len(ss))
+	for item := range ss {
+		items = append(items, item)
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+func (ss stringSet) Set(s string) error {
+	clear(ss)
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			ss[name] = true
+		}
+	}
+	return nil
+}
+
+func (ss stringSet) match(fn *types.Func) bool {
+	if ss[fn.FullName()] {
+		return true
+	}
+	if fn.Pkg() == nil || fn.Type().(*types.Signature).Recv() != nil {
+		return false
+	}
+	return ss[fn.Pkg().Path()+".*"]
+}
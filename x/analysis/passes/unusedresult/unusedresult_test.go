@@ -0,0 +1,43 @@
+package unusedresult_test
+
+import (
+	"testing"
+
+	"github.com/goplus/xgo/x/analysis/analysistest"
+	"github.com/goplus/xgo/x/analysis/passes/unusedresult"
+)
+
+func TestUnusedResult(t *testing.T) {
+	analysistest.Run(t, unusedresult.Analyzer, "example.com/a", analysistest.File{Name: "a.xgo", Src: `
+import (
+	"fmt"
+	"strings"
+)
+
+s := "Hello"
+strings.toUpper s // want "result of strings.ToUpper call not used: a command-style call"
+strings.ToUpper(s) // want "result of strings.ToUpper call not used$"
+fmt.sprintf "%s!", s // want "fmt.Sprintf"
+s = strings.toLower(s)
+echo s
+fmt.println s
+`})
+}
+
+func TestFuncsFlag(t *testing.T) {
+	fs := &unusedresult.Analyzer.Flags
+	old := fs.Lookup("funcs").Value.String()
+	defer fs.Set("funcs", old)
+	if err := fs.Set("funcs", "fmt.Sprint, strings.ToUpper"); err != nil {
+		t.Fatal(err)
+	}
+	if v := fs.Lookup("funcs").Value.String(); v != "fmt.Sprint,strings.ToUpper" {
+		t.Fatal("funcs:", v)
+	}
+	analysistest.Run(t, unusedresult.Analyzer, "example.com/a", analysistest.File{Name: "a.xgo", Src: `
+import "strings"
+
+strings.toUpper "a" // want "strings.ToUpper"
+strings.toLower "a"
+`})
+}
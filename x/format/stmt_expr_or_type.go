@@ -123,6 +123,10 @@ func formatExpr(ctx *formatCtx, expr ast.Expr, ref *ast.Expr) {
 	case *ast.ErrWrapExpr:
 		formatExpr(ctx, v.X, &v.X)
 		formatExpr(ctx, v.Default, &v.Default)
+	case *ast.OptChainExpr:
+		formatExpr(ctx, v.X, &v.X)
+	case *ast.GoExpr:
+		formatExpr(ctx, v.X, &v.X)
 	case *ast.ParenExpr:
 		formatExpr(ctx, v.X, &v.X)
 	case *ast.Ellipsis:
@@ -231,6 +235,12 @@ func formatStmt(ctx *formatCtx, stmt ast.Stmt) {
 		formatSwitchStmt(ctx, v)
 	case *ast.TypeSwitchStmt:
 		formatTypeSwitchStmt(ctx, v)
+	case *ast.MatchClause:
+		formatExprs(ctx, v.List)
+		formatExpr(ctx, v.Guard, &v.Guard)
+		formatStmts(ctx, v.Body)
+	case *ast.MatchStmt:
+		formatMatchStmt(ctx, v)
 	case *ast.CommClause:
 		formatStmt(ctx, v.Comm)
 		formatStmts(ctx, v.Body)
@@ -281,6 +291,14 @@ func formatSwitchStmt(ctx *formatCtx, v *ast.SwitchStmt) {
 	formatBlockStmt(ctx, v.Body)
 }
 
+func formatMatchStmt(ctx *formatCtx, v *ast.MatchStmt) {
+	old := ctx.enterBlock()
+	defer ctx.leaveBlock(old)
+
+	formatExpr(ctx, v.X, &v.X)
+	formatBlockStmt(ctx, v.Body)
+}
+
 func formatTypeSwitchStmt(ctx *formatCtx, v *ast.TypeSwitchStmt) {
 	old := ctx.enterBlock()
 	defer ctx.leaveBlock(old)
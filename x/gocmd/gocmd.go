@@ -31,6 +31,7 @@ type Config struct {
 	XGo   *XGoEnv
 	GoCmd string
 	Flags []string
+	Env   []string // additional environment variables of the go command
 	Run   func(cmd *exec.Cmd) error
 }
 
@@ -51,6 +52,9 @@ func doWithArgs(dir, op string, conf *Config, args ...string) (err error) {
 	exargs = append(exargs, args...)
 	cmd := exec.Command(goCmd, exargs...)
 	cmd.Dir = dir
+	if len(conf.Env) > 0 {
+		cmd.Env = append(os.Environ(), conf.Env...)
+	}
 	run := conf.Run
 	if run == nil {
 		run = runCmd
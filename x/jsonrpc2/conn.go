@@ -211,6 +211,27 @@ type incomingRequest struct {
 	*Request // the request being processed
 	ctx      context.Context
 	cancel   context.CancelFunc
+	batch    *incomingBatch // the batch the request belongs to, if any
+}
+
+// incomingBatch collects the responses to the calls of an incoming batch,
+// which are sent back together once all requests of the batch are processed.
+type incomingBatch struct {
+	mutex     sync.Mutex
+	pending   int // # of requests that have not yet processed a result
+	responses Batch
+}
+
+// done records resp (nil for a notification) of a request of the batch. It
+// returns the responses to send once the last request of the batch is done.
+func (b *incomingBatch) done(resp *Response) (responses Batch, last bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if resp != nil {
+		b.responses = append(b.responses, resp)
+	}
+	b.pending--
+	return b.responses, b.pending == 0
 }
 
 // newConnection creates a new connection and runs it.
@@ -381,6 +402,84 @@ func (c *Connection) Call(ctx context.Context, method string, params any) *Async
 	return ac
 }
 
+// BatchCall is a call or a notification sent in a batch by Connection.Batch.
+type BatchCall struct {
+	Method string
+	Params any
+	Notify bool // true for a notification, which gets no response
+}
+
+// Batch sends calls as a single batch message and returns the objects used to
+// await their responses, which are nil for notifications.
+// If sending the batch failed, the responses of its calls will be ready and
+// have the error in them.
+func (c *Connection) Batch(ctx context.Context, calls ...BatchCall) ([]*AsyncCall, error) {
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("%w: empty batch", ErrInvalidRequest)
+	}
+	batch := make(Batch, len(calls))
+	acs := make([]*AsyncCall, len(calls))
+	notifications := 0
+	for i, call := range calls {
+		if debugCall {
+			log.Println("Batch", call.Method, "params:", call.Params, "notify:", call.Notify)
+		}
+		if call.Notify {
+			notify, err := NewNotification(call.Method, call.Params)
+			if err != nil {
+				return nil, fmt.Errorf("marshaling notify parameters: %v", err)
+			}
+			batch[i] = notify
+			notifications++
+			continue
+		}
+		id := Int64ID(atomic.AddInt64(&c.seq, 1))
+		req, err := NewCall(id, call.Method, call.Params)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling call parameters: %w", err)
+		}
+		batch[i] = req
+		acs[i] = &AsyncCall{id: id, ready: make(chan struct{})}
+	}
+
+	var err error
+	c.updateInFlight(func(s *inFlightState) {
+		err = s.shuttingDown(ErrClientClosing)
+		if err != nil {
+			return
+		}
+		if s.outgoingCalls == nil {
+			s.outgoingCalls = make(map[ID]*AsyncCall)
+		}
+		for _, ac := range acs {
+			if ac != nil {
+				s.outgoingCalls[ac.id] = ac
+			}
+		}
+		s.outgoingNotifications += notifications
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.write(ctx, batch)
+	c.updateInFlight(func(s *inFlightState) {
+		s.outgoingNotifications -= notifications
+		if err == nil {
+			return
+		}
+		// Sending failed. Deliver fake responses to the calls which weren't
+		// already retired by the connection breaking.
+		for _, ac := range acs {
+			if ac != nil && s.outgoingCalls[ac.id] == ac {
+				delete(s.outgoingCalls, ac.id)
+				ac.retire(&Response{ID: ac.id, Error: err})
+			}
+		}
+	})
+	return acs, err
+}
+
 type AsyncCall struct {
 	id       ID
 	ready    chan struct{} // closed after response has been set
@@ -527,24 +626,13 @@ func (c *Connection) readIncoming(ctx context.Context, reader Reader, preempter
 
 		switch msg := msg.(type) {
 		case *Request:
-			c.acceptRequest(ctx, msg, n, preempter)
+			c.acceptRequest(ctx, msg, n, preempter, nil)
 
 		case *Response:
-			if Verbose {
-				log.Println("==> readIncoming Response:", msg.ID)
-			}
-			c.updateInFlight(func(s *inFlightState) {
-				if ac, ok := s.outgoingCalls[msg.ID]; ok {
-					delete(s.outgoingCalls, msg.ID)
-					ac.retire(msg)
-				} else {
-					// TODO: How should we report unexpected responses?
-					_ = 0
-				}
-			})
-			if Verbose {
-				log.Println("==> readIncoming: updateInFlight -", msg.ID)
-			}
+			c.acceptResponse(msg)
+
+		case Batch:
+			c.acceptBatch(ctx, msg, n, preempter)
 
 		default:
 			c.internalErrorf("Read returned an unexpected message of type %T", msg)
@@ -567,9 +655,62 @@ func (c *Connection) readIncoming(ctx context.Context, reader Reader, preempter
 	})
 }
 
+// acceptResponse retires the outgoing call msg responds to.
+func (c *Connection) acceptResponse(msg *Response) {
+	if Verbose {
+		log.Println("==> readIncoming Response:", msg.ID)
+	}
+	c.updateInFlight(func(s *inFlightState) {
+		if ac, ok := s.outgoingCalls[msg.ID]; ok {
+			delete(s.outgoingCalls, msg.ID)
+			ac.retire(msg)
+		} else {
+			// TODO: How should we report unexpected responses?
+			_ = 0
+		}
+	})
+	if Verbose {
+		log.Println("==> readIncoming: updateInFlight -", msg.ID)
+	}
+}
+
+// acceptBatch accepts all requests and responses of a batch. The responses to
+// the calls of the batch are sent back as a batch too. Invalid elements of the
+// batch are answered with errors, and an empty batch with a single error.
+func (c *Connection) acceptBatch(ctx context.Context, msg Batch, msgBytes int64, preempter Preempter) {
+	if len(msg) == 0 {
+		c.write(ctx, &Response{Error: fmt.Errorf("%w: empty batch", ErrInvalidRequest)})
+		return
+	}
+	var batch *incomingBatch
+	for _, m := range msg {
+		switch m.(type) {
+		case *Request, *invalidRequest:
+			if batch == nil {
+				batch = new(incomingBatch)
+			}
+			batch.pending++
+		}
+	}
+	for _, m := range msg {
+		switch m := m.(type) {
+		case *Request:
+			c.acceptRequest(ctx, m, msgBytes, preempter, batch)
+		case *Response:
+			c.acceptResponse(m)
+		case *invalidRequest:
+			if responses, last := batch.done(&Response{Error: m.err}); last {
+				c.write(ctx, responses)
+			}
+		default:
+			c.internalErrorf("Read returned an unexpected message of type %T in a batch", m)
+		}
+	}
+}
+
 // acceptRequest either handles msg synchronously or enqueues it to be handled
 // asynchronously.
-func (c *Connection) acceptRequest(ctx context.Context, msg *Request, msgBytes int64, preempter Preempter) {
+func (c *Connection) acceptRequest(ctx context.Context, msg *Request, msgBytes int64, preempter Preempter, batch *incomingBatch) {
 	// In theory notifications cannot be cancelled, but we build them a cancel
 	// context anyway.
 	ctx, cancel := context.WithCancel(ctx)
@@ -577,6 +718,7 @@ func (c *Connection) acceptRequest(ctx context.Context, msg *Request, msgBytes i
 		Request: msg,
 		ctx:     ctx,
 		cancel:  cancel,
+		batch:   batch,
 	}
 
 	// If the request is a call, add it to the incoming map so it can be
@@ -722,6 +864,7 @@ func (c *Connection) processResult(from any, req *incomingRequest, result any, e
 		result = nil // Discard the spurious result and respond with err.
 	}
 
+	var batchResp *Response
 	if req.IsCall() {
 		response, respErr := NewResponse(req.ID, result, err)
 		if debugCall {
@@ -735,9 +878,13 @@ func (c *Connection) processResult(from any, req *incomingRequest, result any, e
 			delete(s.incomingByID, req.ID)
 		})
 		if respErr == nil {
-			writeErr := c.write(notDone{req.ctx}, response)
-			if err == nil {
-				err = writeErr
+			if req.batch != nil {
+				batchResp = response // sent when the whole batch is done
+			} else {
+				writeErr := c.write(notDone{req.ctx}, response)
+				if err == nil {
+					err = writeErr
+				}
 			}
 		} else {
 			err = c.internalErrorf("%#v returned a malformed result for %q: %w", from, req.Method, respErr)
@@ -749,6 +896,14 @@ func (c *Connection) processResult(from any, req *incomingRequest, result any, e
 			err = fmt.Errorf("%w: %q notification failed: %v", ErrInternal, req.Method, err)
 		}
 	}
+	if req.batch != nil {
+		if responses, last := req.batch.done(batchResp); last && len(responses) > 0 {
+			writeErr := c.write(notDone{req.ctx}, responses)
+			if err == nil {
+				err = writeErr
+			}
+		}
+	}
 	_ = err
 
 	// Cancel the request and finalize the event span to free any associated resources.
@@ -22,7 +22,9 @@ package jsonrpc2
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"strconv"
@@ -151,3 +153,105 @@ func (w *headerWriter) Write(ctx context.Context, msg Message) (int64, error) {
 	}
 	return total, err
 }
+
+// NDJSONFramer returns a new Framer.
+// The messages are sent as newline-delimited JSON, one message per line.
+// This is the format used by MCP over stdio and others.
+func NDJSONFramer() Framer { return ndjsonFramer{} }
+
+type ndjsonFramer struct{}
+type ndjsonReader struct{ in *bufio.Reader }
+type ndjsonWriter struct{ out io.Writer }
+
+func (ndjsonFramer) Reader(rw io.Reader) Reader {
+	return &ndjsonReader{in: bufio.NewReader(rw)}
+}
+
+func (ndjsonFramer) Writer(rw io.Writer) Writer {
+	return &ndjsonWriter{out: rw}
+}
+
+func (r *ndjsonReader) Read(ctx context.Context) (Message, int64, error) {
+	select {
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	default:
+	}
+	var total int64
+	for {
+		line, err := r.in.ReadBytes('\n')
+		total += int64(len(line))
+		if err != nil && err != io.EOF {
+			return nil, total, fmt.Errorf("failed reading line: %w", err)
+		}
+		// skip empty lines between messages
+		if line = bytes.TrimSpace(line); len(line) == 0 {
+			if err == io.EOF {
+				return nil, total, io.EOF
+			}
+			continue
+		}
+		// the last line may have no trailing newline
+		msg, err := DecodeMessage(line)
+		return msg, total, err
+	}
+}
+
+func (w *ndjsonWriter) Write(ctx context.Context, msg Message) (int64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+	data, err := EncodeMessage(msg)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling message: %v", err)
+	}
+	n, err := w.out.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// RawFramer returns a new Framer.
+// The messages are sent as concatenated JSON values with no framing, so that
+// each message is delimited by the end of its JSON value.
+func RawFramer() Framer { return rawFramer{} }
+
+type rawFramer struct{}
+type rawReader struct{ in *json.Decoder }
+type rawWriter struct{ out io.Writer }
+
+func (rawFramer) Reader(rw io.Reader) Reader {
+	return &rawReader{in: json.NewDecoder(rw)}
+}
+
+func (rawFramer) Writer(rw io.Writer) Writer {
+	return &rawWriter{out: rw}
+}
+
+func (r *rawReader) Read(ctx context.Context) (Message, int64, error) {
+	select {
+	case <-ctx.Done():
+		return nil, 0, ctx.Err()
+	default:
+	}
+	var raw json.RawMessage
+	if err := r.in.Decode(&raw); err != nil {
+		return nil, 0, err
+	}
+	msg, err := DecodeMessage(raw)
+	return msg, int64(len(raw)), err
+}
+
+func (w *rawWriter) Write(ctx context.Context, msg Message) (int64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+	data, err := EncodeMessage(msg)
+	if err != nil {
+		return 0, fmt.Errorf("marshaling message: %v", err)
+	}
+	n, err := w.out.Write(data)
+	return int64(n), err
+}
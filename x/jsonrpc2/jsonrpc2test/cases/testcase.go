@@ -68,6 +68,20 @@ var callTests = []invoker{
 		collect{"a", true, false},
 		collect{"b", true, false},
 	}},
+	batch{"batch", []jsonrpc2.BatchCall{
+		{Method: "set", Params: 2, Notify: true},
+		{Method: "one_string", Params: "fish"},
+		{Method: "add", Params: 3, Notify: true},
+		{Method: "get"},
+		{Method: "join", Params: []string{"a", "b"}},
+	}, []any{nil, "got:fish", nil, 5, "a/b"}},
+	sequence{"batch notify", []invoker{
+		batch{"notify", []jsonrpc2.BatchCall{
+			{Method: "set", Params: 4, Notify: true},
+			{Method: "add", Params: 6, Notify: true},
+		}, []any{nil, nil}},
+		call{"get", nil, 10},
+	}},
 }
 
 type binder struct {
@@ -117,6 +131,12 @@ type cancel struct {
 	name string
 }
 
+type batch struct {
+	name   string
+	calls  []jsonrpc2.BatchCall
+	expect []any // expected results of calls, nil for notifications
+}
+
 type sequence struct {
 	name  string
 	tests []invoker
@@ -208,6 +228,25 @@ func (test cancel) Invoke(t *testing.T, ctx context.Context, h *handler) {
 	}
 }
 
+func (test batch) Name() string { return test.name }
+func (test batch) Invoke(t *testing.T, ctx context.Context, h *handler) {
+	calls, err := h.conn.Batch(ctx, test.calls...)
+	if err != nil {
+		t.Fatalf("%v:Batch failed: %v", test.name, err)
+	}
+	for i, call := range calls {
+		if call == nil {
+			continue
+		}
+		method := test.calls[i].Method
+		results := newResults(test.expect[i])
+		if err := call.Await(ctx, results); err != nil {
+			t.Fatalf("%v:Batch call failed: %v", method, err)
+		}
+		verifyResults(t, method, results, test.expect[i])
+	}
+}
+
 func (test sequence) Name() string { return test.name }
 func (test sequence) Invoke(t *testing.T, ctx context.Context, h *handler) {
 	for _, child := range test.tests {
@@ -6,6 +6,12 @@ package jsonrpc2test_test
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/goplus/xgo/x/jsonrpc2"
@@ -19,3 +25,141 @@ func TestNetPipe(t *testing.T) {
 	listener := jsonrpc2test.NetPipeListener()
 	cases.Test(t, ctx, listener, jsonrpc2.HeaderFramer(), true)
 }
+
+func TestNDJSONFramer(t *testing.T) {
+	ctx := context.Background()
+	listener := jsonrpc2test.NetPipeListener()
+	cases.Test(t, ctx, listener, jsonrpc2.NDJSONFramer(), true)
+}
+
+func TestRawFramer(t *testing.T) {
+	ctx := context.Background()
+	listener := jsonrpc2test.NetPipeListener()
+	cases.Test(t, ctx, listener, jsonrpc2.RawFramer(), true)
+}
+
+func TestNetListener(t *testing.T) {
+	ctx := context.Background()
+	addr := filepath.Join(t.TempDir(), "test.sock")
+	listener, err := jsonrpc2.NetListener(ctx, "unix", addr, jsonrpc2.NetListenOptions{})
+	if err != nil {
+		t.Skip("NetListener:", err)
+	}
+	cases.Test(t, ctx, listener, jsonrpc2.HeaderFramer(), true)
+	if _, err := os.Stat(addr); !os.IsNotExist(err) {
+		t.Fatal("socket file not removed:", err)
+	}
+}
+
+func TestBatchMessage(t *testing.T) {
+	call, _ := jsonrpc2.NewCall(jsonrpc2.Int64ID(1), "get", nil)
+	notify, _ := jsonrpc2.NewNotification("set", 3)
+	data, err := jsonrpc2.EncodeMessage(jsonrpc2.Batch{call, notify})
+	if err != nil {
+		t.Fatal("EncodeMessage:", err)
+	}
+	const expect = `[{"jsonrpc":"2.0","id":1,"method":"get"},{"jsonrpc":"2.0","method":"set","params":3}]`
+	if string(data) != expect {
+		t.Fatalf("EncodeMessage: got %s", data)
+	}
+	msg, err := jsonrpc2.DecodeMessage(data)
+	if err != nil {
+		t.Fatal("DecodeMessage:", err)
+	}
+	if batch, ok := msg.(jsonrpc2.Batch); !ok || len(batch) != 2 || batch[0].(*jsonrpc2.Request).Method != "get" {
+		t.Fatalf("DecodeMessage: got %#v", msg)
+	}
+	if msg, err = jsonrpc2.DecodeMessage([]byte(" []")); err != nil || len(msg.(jsonrpc2.Batch)) != 0 {
+		t.Fatal("DecodeMessage empty batch:", msg, err)
+	}
+	if _, err = jsonrpc2.DecodeMessage([]byte("[1")); err == nil {
+		t.Fatal("DecodeMessage invalid JSON: no error")
+	}
+	if _, err = jsonrpc2.EncodeMessage(jsonrpc2.Batch{jsonrpc2.Batch{call}}); err == nil {
+		t.Fatal("EncodeMessage nested batch: no error")
+	}
+}
+
+func TestNDJSONReader(t *testing.T) {
+	ctx := context.Background()
+	in := "\n" + `{"jsonrpc":"2.0","id":1,"method":"a"}` + "\r\n\n" +
+		`[{"jsonrpc":"2.0","method":"b"},{"jsonrpc":"2.0","id":"x","result":1}]` + "\n" +
+		`{"jsonrpc":"2.0","method":"c"}`
+	r := jsonrpc2.NDJSONFramer().Reader(strings.NewReader(in))
+	var got []string
+	for {
+		msg, _, err := r.Read(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal("Read:", err)
+		}
+		got = append(got, fmt.Sprintf("%T", msg))
+	}
+	if ret := strings.Join(got, " "); ret != "*jsonrpc2.Request jsonrpc2.Batch *jsonrpc2.Request" {
+		t.Fatal("Read:", ret)
+	}
+}
+
+func TestInvalidBatch(t *testing.T) {
+	ctx := context.Background()
+	mux := jsonrpc2.NewMux()
+	jsonrpc2.RegisterFunc(mux, "add", func(ctx context.Context, p [2]int) (int, error) {
+		return p[0] + p[1], nil
+	})
+	listener := jsonrpc2test.NetPipeListener()
+	server := jsonrpc2.NewServer(ctx, listener, jsonrpc2.BinderFunc(
+		func(ctx context.Context, c *jsonrpc2.Connection) jsonrpc2.ConnectionOptions {
+			return jsonrpc2.ConnectionOptions{Handler: mux}
+		}))
+	defer func() {
+		listener.Close()
+		server.Wait()
+	}()
+	rwc, err := listener.Dialer().Dial(ctx)
+	if err != nil {
+		t.Fatal("Dial:", err)
+	}
+	defer rwc.Close()
+	r := jsonrpc2.HeaderFramer().Reader(rwc)
+	call := func(req string) jsonrpc2.Message {
+		t.Helper()
+		if _, err := fmt.Fprintf(rwc, "Content-Length: %d\r\n\r\n%s", len(req), req); err != nil {
+			t.Fatal("Write:", err)
+		}
+		msg, _, err := r.Read(ctx)
+		if err != nil {
+			t.Fatal("Read:", err)
+		}
+		return msg
+	}
+	isInvalid := func(msg jsonrpc2.Message) bool {
+		resp, ok := msg.(*jsonrpc2.Response)
+		return ok && !resp.ID.IsValid() && errors.Is(resp.Error, jsonrpc2.ErrInvalidRequest)
+	}
+
+	if msg := call(`[]`); !isInvalid(msg) {
+		t.Fatalf("empty batch: %#v", msg)
+	}
+	msg := call(`[1, {"jsonrpc":"2.0","id":1,"method":"add","params":[1,2]}, [], {"jsonrpc":"2.0","method":"add"}]`)
+	batch, ok := msg.(jsonrpc2.Batch)
+	if !ok || len(batch) != 3 {
+		t.Fatalf("invalid batch: %#v", msg)
+	}
+	var invalid int
+	for _, m := range batch {
+		if isInvalid(m) {
+			invalid++
+		} else if resp, ok := m.(*jsonrpc2.Response); !ok || resp.ID != jsonrpc2.Int64ID(1) || string(resp.Result) != "3" {
+			t.Fatalf("invalid batch: %#v", m)
+		}
+	}
+	if invalid != 2 {
+		t.Fatal("invalid batch: errors", invalid)
+	}
+	msg = call(`{"jsonrpc":"2.0","id":2,"method":"add","params":[3,4]}`)
+	if resp, ok := msg.(*jsonrpc2.Response); !ok || string(resp.Result) != "7" {
+		t.Fatalf("call after invalid batches: %#v", msg)
+	}
+}
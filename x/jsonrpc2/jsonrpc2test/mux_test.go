@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonrpc2test_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/goplus/xgo/x/jsonrpc2"
+	"github.com/goplus/xgo/x/jsonrpc2/jsonrpc2test"
+)
+
+type point struct {
+	X, Y int
+}
+
+func newMux(logger *log.Logger, notified chan int) *jsonrpc2.Mux {
+	mux := jsonrpc2.NewMux()
+	mux.Use(jsonrpc2.Logging(logger), jsonrpc2.Recover)
+	jsonrpc2.RegisterFunc(mux, "add", func(ctx context.Context, p point) (int, error) {
+		return p.X + p.Y, nil
+	})
+	jsonrpc2.RegisterFunc(mux, "panic", func(ctx context.Context, msg string) (any, error) {
+		panic(msg)
+	})
+	jsonrpc2.RegisterFunc(mux, "wait", func(ctx context.Context, _ struct{}) (bool, error) {
+		<-ctx.Done()
+		return false, ctx.Err()
+	})
+	jsonrpc2.RegisterNotify(mux, "notify", func(ctx context.Context, v int) error {
+		notified <- v
+		return nil
+	})
+	return mux
+}
+
+func TestMux(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	notified := make(chan int, 1)
+	mux := newMux(log.New(&buf, "", 0), notified)
+	listener := jsonrpc2test.NetPipeListener()
+	server := jsonrpc2.NewServer(ctx, listener, jsonrpc2.BinderFunc(
+		func(ctx context.Context, c *jsonrpc2.Connection) jsonrpc2.ConnectionOptions {
+			return jsonrpc2.ConnectionOptions{
+				Preempter: jsonrpc2.CancelPreempter(c),
+				Handler:   mux,
+			}
+		}))
+	defer func() {
+		listener.Close()
+		server.Wait()
+	}()
+	client, err := jsonrpc2.Dial(ctx, listener.Dialer(), jsonrpc2.BinderFunc(
+		func(ctx context.Context, c *jsonrpc2.Connection) (ret jsonrpc2.ConnectionOptions) {
+			return
+		}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var sum int
+	if err = client.Call(ctx, "add", point{1, 2}).Await(ctx, &sum); err != nil || sum != 3 {
+		t.Fatal("add:", sum, err)
+	}
+	err = client.Call(ctx, "add", "bad").Await(ctx, nil)
+	if !errors.Is(err, jsonrpc2.ErrInvalidParams) {
+		t.Fatal("add with invalid params:", err)
+	}
+	err = client.Call(ctx, "sub", point{1, 2}).Await(ctx, nil)
+	if !errors.Is(err, jsonrpc2.ErrMethodNotFound) {
+		t.Fatal("unknown method:", err)
+	}
+	err = client.Call(ctx, "panic", "boom").Await(ctx, nil)
+	if !errors.Is(err, jsonrpc2.ErrInternal) || !strings.Contains(err.Error(), "boom") {
+		t.Fatal("panic:", err)
+	}
+	if err = client.Notify(ctx, "notify", 5); err != nil {
+		t.Fatal("notify:", err)
+	}
+	if v := <-notified; v != 5 {
+		t.Fatal("notify:", v)
+	}
+
+	call := client.Call(ctx, "wait", nil)
+	if err = client.CancelCall(ctx, call.ID()); err != nil {
+		t.Fatal("CancelCall:", err)
+	}
+	if err = call.Await(ctx, nil); err == nil {
+		t.Fatal("wait: not canceled")
+	}
+
+	logs := buf.String()
+	for _, expect := range []string{"jsonrpc2: 1 add (", "jsonrpc2: - notify (", "\"panic\" panic: boom"} {
+		if !strings.Contains(logs, expect) {
+			t.Fatalf("logs without %q:\n%s", expect, logs)
+		}
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if e := recover(); e == nil {
+			t.Fatal("no panic")
+		}
+	}()
+	mux := jsonrpc2.NewMux()
+	jsonrpc2.RegisterNotify(mux, "a", func(ctx context.Context, v int) error { return nil })
+	jsonrpc2.RegisterNotify(mux, "a", func(ctx context.Context, v int) error { return nil })
+}
+
+func TestMuxChainOnce(t *testing.T) {
+	mux := jsonrpc2.NewMux()
+	jsonrpc2.RegisterNotify(mux, "a", func(ctx context.Context, v int) error { return nil })
+	built := 0
+	mux.Use(func(next jsonrpc2.Handler) jsonrpc2.Handler {
+		built++
+		return next
+	})
+	req, err := jsonrpc2.NewNotification("a", 1)
+	if err != nil {
+		t.Fatal("NewNotification:", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err = mux.Handle(context.Background(), req); err != nil {
+			t.Fatal("Handle:", err)
+		}
+	}
+	if built != 1 {
+		t.Fatal("middleware built", built, "times")
+	}
+}
@@ -21,6 +21,7 @@
 package jsonrpc2
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -33,8 +34,8 @@ type ID struct {
 
 // Message is the interface to all jsonrpc2 message types.
 // They share no common functionality, but are a closed set of concrete types
-// that are allowed to implement this interface. The message types are *Request
-// and *Response.
+// that are allowed to implement this interface. The message types are *Request,
+// *Response and Batch.
 type Message interface {
 	// marshal builds the wire form from the API form.
 	// It is private, which makes the set of Message implementations closed.
@@ -64,6 +65,18 @@ type Response struct {
 	ID ID
 }
 
+// Batch is a Message that contains several requests or responses, which are
+// sent as a JSON array. A batch can't contain another batch.
+//
+// A batch decoded by DecodeMessage may be empty, and invalid elements of it
+// are decoded to placeholders that Connection answers with ErrInvalidRequest.
+type Batch []Message
+
+// invalidRequest is an invalid element of a decoded batch.
+type invalidRequest struct {
+	err error
+}
+
 // StringID creates a new string request identifier.
 func StringID(s string) ID { return ID{value: s} }
 
@@ -108,10 +121,21 @@ func NewResponse(id ID, result any, rerr error) (*Response, error) {
 
 func (msg *Response) marshal(to *wireCombined) {
 	to.ID = msg.ID.value
+	if to.ID == nil { // error of an invalid request, whose id is null
+		to.ID = json.RawMessage("null")
+	}
 	to.Error = toWireError(msg.Error)
 	to.Result = msg.Result
 }
 
+func (msg Batch) marshal(to *wireCombined) {
+	// a batch is encoded as an array by EncodeMessage
+}
+
+func (msg *invalidRequest) marshal(to *wireCombined) {
+	// never sent
+}
+
 func toWireError(err error) *wireError {
 	if err == nil {
 		// no error, the response is complete
@@ -132,6 +156,9 @@ func toWireError(err error) *wireError {
 }
 
 func EncodeMessage(msg Message) ([]byte, error) {
+	if batch, ok := msg.(Batch); ok {
+		return encodeBatch(batch)
+	}
 	wire := wireCombined{VersionTag: wireVersion}
 	msg.marshal(&wire)
 	data, err := json.Marshal(&wire)
@@ -141,7 +168,32 @@ func EncodeMessage(msg Message) ([]byte, error) {
 	return data, nil
 }
 
+func encodeBatch(batch Batch) ([]byte, error) {
+	if len(batch) == 0 {
+		return nil, fmt.Errorf("marshaling jsonrpc message: empty batch")
+	}
+	msgs := make([]json.RawMessage, len(batch))
+	for i, msg := range batch {
+		if _, ok := msg.(Batch); ok {
+			return nil, fmt.Errorf("marshaling jsonrpc message: nested batch")
+		}
+		data, err := EncodeMessage(msg)
+		if err != nil {
+			return nil, err
+		}
+		msgs[i] = data
+	}
+	data, err := json.Marshal(msgs)
+	if err != nil {
+		return data, fmt.Errorf("marshaling jsonrpc message: %w", err)
+	}
+	return data, nil
+}
+
 func DecodeMessage(data []byte) (Message, error) {
+	if data = bytes.TrimLeft(data, " \t\r\n"); len(data) > 0 && data[0] == '[' {
+		return decodeBatch(data)
+	}
 	msg := wireCombined{}
 	if err := json.Unmarshal(data, &msg); err != nil {
 		return nil, fmt.Errorf("unmarshaling jsonrpc message: %w", err)
@@ -149,18 +201,9 @@ func DecodeMessage(data []byte) (Message, error) {
 	if msg.VersionTag != wireVersion {
 		return nil, fmt.Errorf("invalid message version tag %s expected %s", msg.VersionTag, wireVersion)
 	}
-	id := ID{}
-	switch v := msg.ID.(type) {
-	case nil:
-	case float64:
-		// coerce the id type to int64 if it is float64, the spec does not allow fractional parts
-		id = Int64ID(int64(v))
-	case int64:
-		id = Int64ID(v)
-	case string:
-		id = StringID(v)
-	default:
-		return nil, fmt.Errorf("invalid message id type <%T>%v", v, v)
+	id, err := makeID(msg.ID)
+	if err != nil {
+		return nil, err
 	}
 	if msg.Method != "" {
 		// has a method, must be a call
@@ -171,7 +214,7 @@ func DecodeMessage(data []byte) (Message, error) {
 		}, nil
 	}
 	// no method, should be a response
-	if !id.IsValid() {
+	if !id.IsValid() && msg.Error == nil {
 		return nil, ErrInvalidRequest
 	}
 	resp := &Response{
@@ -185,6 +228,48 @@ func DecodeMessage(data []byte) (Message, error) {
 	return resp, nil
 }
 
+// decodeBatch decodes a batch. It doesn't fail if the batch is empty or some
+// of its elements are invalid, so that they can be answered with errors.
+func decodeBatch(data []byte) (Message, error) {
+	var msgs []json.RawMessage
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return nil, fmt.Errorf("unmarshaling jsonrpc message: %w", err)
+	}
+	batch := make(Batch, len(msgs))
+	for i, data := range msgs {
+		msg, err := DecodeMessage(data)
+		if err == nil {
+			if _, ok := msg.(Batch); ok {
+				err = fmt.Errorf("%w: nested batch", ErrInvalidRequest)
+			}
+		} else if !errors.Is(err, ErrInvalidRequest) {
+			err = fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+		}
+		if err != nil {
+			msg = &invalidRequest{err: err}
+		}
+		batch[i] = msg
+	}
+	return batch, nil
+}
+
+// makeID makes an ID from its value decoded from JSON.
+func makeID(v any) (id ID, err error) {
+	switch v := v.(type) {
+	case nil:
+	case float64:
+		// coerce the id type to int64 if it is float64, the spec does not allow fractional parts
+		id = Int64ID(int64(v))
+	case int64:
+		id = Int64ID(v)
+	case string:
+		id = StringID(v)
+	default:
+		err = fmt.Errorf("invalid message id type <%T>%v", v, v)
+	}
+	return
+}
+
 func marshalToRaw(obj any) (json.RawMessage, error) {
 	if obj == nil {
 		return nil, nil
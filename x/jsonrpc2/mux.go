@@ -0,0 +1,216 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsonrpc2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+
+// Mux is a Handler that dispatches requests to the handlers registered for
+// their methods, through a chain of middlewares.
+//
+// Methods and middlewares must be registered before the Mux is used.
+type Mux struct {
+	handlers    map[string]Handler
+	middlewares []Middleware
+
+	chainOnce sync.Once
+	chain     Handler // dispatch wrapped by middlewares, built on first use
+}
+
+// NewMux creates a new Mux.
+func NewMux() *Mux {
+	return &Mux{handlers: make(map[string]Handler)}
+}
+
+// Register registers h as the handler of method.
+func (m *Mux) Register(method string, h Handler) {
+	if _, ok := m.handlers[method]; ok {
+		panic("jsonrpc2: multiple registrations for method " + method)
+	}
+	m.handlers[method] = h
+}
+
+// Use appends middlewares to the chain of m. The first middleware is the
+// outermost one, which sees a request first.
+func (m *Mux) Use(middlewares ...Middleware) {
+	m.middlewares = append(m.middlewares, middlewares...)
+}
+
+// Handle dispatches req to the handler registered for its method. It returns
+// ErrNotHandled if there is no such handler, which results in ErrMethodNotFound.
+func (m *Mux) Handle(ctx context.Context, req *Request) (any, error) {
+	m.chainOnce.Do(func() {
+		m.chain = Chain(HandlerFunc(m.dispatch), m.middlewares...)
+	})
+	return m.chain.Handle(ctx, req)
+}
+
+func (m *Mux) dispatch(ctx context.Context, req *Request) (any, error) {
+	if h, ok := m.handlers[req.Method]; ok {
+		return h.Handle(ctx, req)
+	}
+	return nil, ErrNotHandled
+}
+
+// RegisterFunc registers fn as the handler of method on m. Params of requests
+// are decoded into P, and an ErrInvalidParams error is returned if it fails.
+// The result of fn is dropped if the request is a notification.
+func RegisterFunc[P, R any](m *Mux, method string, fn func(ctx context.Context, params P) (R, error)) {
+	m.Register(method, HandlerFunc(func(ctx context.Context, req *Request) (any, error) {
+		params, err := decodeParams[P](req)
+		if err != nil {
+			return nil, err
+		}
+		ret, err := fn(ctx, params)
+		if err != nil || !req.IsCall() {
+			return nil, err
+		}
+		return ret, nil
+	}))
+}
+
+// RegisterNotify registers fn as the handler of the notification method on m.
+// Params of requests are decoded into P, and an ErrInvalidParams error is
+// returned if it fails.
+func RegisterNotify[P any](m *Mux, method string, fn func(ctx context.Context, params P) error) {
+	m.Register(method, HandlerFunc(func(ctx context.Context, req *Request) (any, error) {
+		params, err := decodeParams[P](req)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fn(ctx, params)
+	}))
+}
+
+func decodeParams[P any](req *Request) (params P, err error) {
+	if len(req.Params) == 0 { // no params
+		return
+	}
+	if e := json.Unmarshal(req.Params, &params); e != nil {
+		err = fmt.Errorf("%w: %q: %v", ErrInvalidParams, req.Method, e)
+	}
+	return
+}
+
+// -----------------------------------------------------------------------------
+
+// Middleware wraps a Handler to add behavior, eg. logging, around it.
+type Middleware func(next Handler) Handler
+
+// Chain wraps h with middlewares. The first middleware is the outermost one.
+// It can be used to build the Handler of both server and client Connections.
+func Chain(h Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// Recover is a Middleware that turns a panic of the next handler into an
+// ErrInternal error instead of crashing the process.
+func Recover(next Handler) Handler {
+	return HandlerFunc(func(ctx context.Context, req *Request) (ret any, err error) {
+		defer func() {
+			if e := recover(); e != nil {
+				ret, err = nil, fmt.Errorf("%w: %q panic: %v", ErrInternal, req.Method, e)
+			}
+		}()
+		return next.Handle(ctx, req)
+	})
+}
+
+// Timing returns a Middleware that calls observe with the time spent by the
+// next handler on each request. For a request responded asynchronously, it is
+// the time until ErrAsyncResponse was returned.
+func Timing(observe func(req *Request, d time.Duration, err error)) Middleware {
+	return func(next Handler) Handler {
+		return HandlerFunc(func(ctx context.Context, req *Request) (any, error) {
+			start := time.Now()
+			ret, err := next.Handle(ctx, req)
+			observe(req, time.Since(start), err)
+			return ret, err
+		})
+	}
+}
+
+// Logging returns a Middleware that logs each request, the time spent on it
+// and its error, if any, to logger. If logger is nil, the standard logger is
+// used.
+func Logging(logger *log.Logger) Middleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return Timing(func(req *Request, d time.Duration, err error) {
+		id := req.ID.Raw()
+		if id == nil {
+			id = "-"
+		}
+		if err != nil {
+			logger.Printf("jsonrpc2: %v %s (%v): %v", id, req.Method, d, err)
+		} else {
+			logger.Printf("jsonrpc2: %v %s (%v)", id, req.Method, d)
+		}
+	})
+}
+
+// -----------------------------------------------------------------------------
+
+// MethodCancelRequest is the method of notifications that cancel calls.
+const MethodCancelRequest = "$/cancelRequest"
+
+// CancelParams is the params of a $/cancelRequest notification.
+type CancelParams struct {
+	ID any `json:"id"` // ID of the call to cancel
+}
+
+// CancelPreempter returns a Preempter of the connection c that handles
+// $/cancelRequest notifications by canceling the Context passed to the
+// handler of the call. It must be a Preempter rather than a Middleware,
+// because the handler of c handles requests one by one.
+func CancelPreempter(c *Connection) Preempter {
+	return PreempterFunc(func(ctx context.Context, req *Request) (any, error) {
+		if req.Method != MethodCancelRequest {
+			return nil, ErrNotHandled
+		}
+		var params CancelParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidParams, req.Method, err)
+		}
+		id, err := makeID(params.ID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidParams, req.Method, err)
+		}
+		c.Cancel(id)
+		return nil, nil
+	})
+}
+
+// CancelCall asks the peer to cancel the outgoing call with the given ID by
+// sending a $/cancelRequest notification. The call still receives a response.
+func (c *Connection) CancelCall(ctx context.Context, id ID) error {
+	return c.Notify(ctx, MethodCancelRequest, &CancelParams{ID: id.Raw()})
+}
+
+// -----------------------------------------------------------------------------
@@ -18,6 +18,10 @@ package langserver
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/goplus/xgo/x/jsonrpc2"
 )
@@ -27,8 +31,18 @@ import (
 const (
 	methodGenGo   = "gengo"
 	methodChanged = "changed"
+	methodStatus  = "status"
 )
 
+// Status represents the status of a LangServer.
+type Status struct {
+	Pid     int       `json:"pid"`
+	Socket  string    `json:"socket,omitempty"` // Unix socket of the daemon
+	Clients int       `json:"clients"`          // number of connected clients of the daemon
+	Modules []string  `json:"modules"`          // modules whose importer caches are loaded
+	Started time.Time `json:"started"`
+}
+
 // -----------------------------------------------------------------------------
 
 // Dialer is used by clients to dial a server.
@@ -60,7 +74,50 @@ func (p Client) Close() error {
 }
 
 func (p Client) AsyncGenGo(ctx context.Context, pattern ...string) *AsyncCall {
-	return p.conn.Call(ctx, methodGenGo, pattern)
+	dir, _ := os.Getwd()
+	return p.conn.Call(ctx, methodGenGo, &genGoParams{Dir: dir, Pattern: absPatterns(pattern)})
+}
+
+// genGoParams represents parameters of the gengo method.
+type genGoParams struct {
+	Dir     string   `json:"dir"` // working directory of the client
+	Pattern []string `json:"pattern"`
+}
+
+func (p *genGoParams) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' { // pattern only, sent by old clients
+		return json.Unmarshal(data, &p.Pattern)
+	}
+	type params genGoParams
+	return json.Unmarshal(data, (*params)(p))
+}
+
+// absPatterns converts relative directories and files in pattern to absolute
+// ones, because a daemon doesn't share the working directory of its clients.
+func absPatterns(pattern []string) []string {
+	ret := make([]string, len(pattern))
+	for i, v := range pattern {
+		if v != "" && v[0] == '.' || isFile(v) {
+			v = absPath(v)
+		}
+		ret[i] = v
+	}
+	return ret
+}
+
+func isFile(fname string) bool {
+	if filepath.Ext(fname) == "" {
+		return false
+	}
+	fi, err := os.Stat(fname)
+	return err == nil && !fi.IsDir()
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
 }
 
 func (p Client) GenGo(ctx context.Context, pattern ...string) (err error) {
@@ -68,7 +125,18 @@ func (p Client) GenGo(ctx context.Context, pattern ...string) (err error) {
 }
 
 func (p Client) Changed(ctx context.Context, files ...string) (err error) {
-	return p.conn.Notify(ctx, methodChanged, files)
+	abs := make([]string, len(files))
+	for i, file := range files {
+		abs[i] = absPath(file)
+	}
+	return p.conn.Notify(ctx, methodChanged, abs)
+}
+
+// Status returns the status of the LangServer.
+func (p Client) Status(ctx context.Context) (ret *Status, err error) {
+	ret = new(Status)
+	err = p.conn.Call(ctx, methodStatus, nil).Await(ctx, ret)
+	return
 }
 
 // -----------------------------------------------------------------------------
@@ -0,0 +1,221 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package langserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/goplus/xgo/x/jsonrpc2"
+)
+
+// -----------------------------------------------------------------------------
+
+const (
+	daemonSocket = "serve.sock"
+	daemonLog    = "serve.log"
+
+	defaultIdleTimeout = 10 * time.Minute
+)
+
+var (
+	// ErrDaemonRunning is returned by ServeDaemon if another daemon is already
+	// listening on the socket.
+	ErrDaemonRunning = errors.New("langserver: daemon is already running")
+)
+
+// DaemonConfig represents the configuration of a LangServer daemon.
+type DaemonConfig struct {
+	// Dir is where the socket and the log file of the daemon are located.
+	// If empty, ~/.xgo/ will be used.
+	Dir string
+
+	// IdleTimeout is how long the daemon waits for new clients after its
+	// last client disconnected. If zero, 10 minutes will be used.
+	IdleTimeout time.Duration
+
+	// UseCache enables the importer cache file and the code generation cache,
+	// see Config.UseCache.
+	UseCache bool
+}
+
+func (p *DaemonConfig) dir() (dir string, err error) {
+	if p != nil && p.Dir != "" {
+		dir = p.Dir
+	} else {
+		home, e := os.UserHomeDir()
+		if e != nil {
+			return "", e
+		}
+		dir = filepath.Join(home, ".xgo")
+	}
+	err = os.MkdirAll(dir, 0755)
+	return
+}
+
+func (p *DaemonConfig) idleTimeout() time.Duration {
+	if p != nil && p.IdleTimeout > 0 {
+		return p.IdleTimeout
+	}
+	return defaultIdleTimeout
+}
+
+// SocketFile returns the Unix socket file the daemon listens on.
+func (p *DaemonConfig) SocketFile() (string, error) {
+	dir, err := p.dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, daemonSocket), nil
+}
+
+// ServeDaemon serves as a LangServer daemon on a Unix socket, which can be
+// shared by multiple clients. It returns nil when the daemon shuts down after
+// being idle for conf.IdleTimeout.
+func ServeDaemon(ctx context.Context, conf *DaemonConfig) (err error) {
+	socket, err := conf.SocketFile()
+	if err != nil {
+		return
+	}
+	if _, e := os.Stat(socket); e == nil {
+		if c, e := net.Dial("unix", socket); e == nil {
+			c.Close()
+			return ErrDaemonRunning
+		}
+		os.Remove(socket) // stale socket left by a crashed daemon
+	}
+	l, err := jsonrpc2.NetListener(ctx, "unix", socket, jsonrpc2.NetListenOptions{})
+	if err != nil {
+		return
+	}
+	h := newHandle(conf != nil && conf.UseCache)
+	h.socket = socket
+	listener := jsonrpc2.NewIdleListener(conf.idleTimeout(), &clientsListener{l, h})
+	server := newServer(ctx, listener, nil, h)
+	err = server.Wait()
+	close(h.notify)
+	h.mutex.Lock()
+	roots := make([]string, 0, len(h.mods))
+	for root := range h.mods {
+		roots = append(roots, root)
+	}
+	h.mutex.Unlock()
+	for _, root := range roots {
+		h.invalidate(root)
+	}
+	if err == jsonrpc2.ErrIdleTimeout {
+		err = nil
+	}
+	return
+}
+
+// clientsListener counts clients of a daemon.
+type clientsListener struct {
+	Listener
+	h *handler
+}
+
+func (p *clientsListener) Accept(ctx context.Context) (io.ReadWriteCloser, error) {
+	rwc, err := p.Listener.Accept(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.h.clients.Add(1)
+	return &clientConn{ReadWriteCloser: rwc, h: p.h}, nil
+}
+
+type clientConn struct {
+	io.ReadWriteCloser
+	h    *handler
+	once sync.Once
+}
+
+func (p *clientConn) Close() error {
+	p.once.Do(func() {
+		p.h.clients.Add(-1)
+	})
+	return p.ReadWriteCloser.Close()
+}
+
+// -----------------------------------------------------------------------------
+
+// DialDaemon connects to the LangServer daemon and returns a client of it. If
+// the daemon isn't running, it executes `xgoCmd args...` in background to start
+// one first. The daemon writes its log to serve.log next to its socket.
+func DialDaemon(ctx context.Context, conf *DaemonConfig, xgoCmd string, args ...string) (ret Client, err error) {
+	socket, err := conf.SocketFile()
+	if err != nil {
+		return
+	}
+	dialer := jsonrpc2.NetDialer("unix", socket, net.Dialer{})
+	if ret, err = Open(ctx, dialer, nil); err == nil {
+		return
+	}
+
+	f, err := os.OpenFile(filepath.Join(filepath.Dir(socket), daemonLog), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	cmd := exec.Command(xgoCmd, args...)
+	cmd.Stderr = f
+	cmd.SysProcAttr = daemonSysProcAttr()
+	if err = cmd.Start(); err != nil {
+		return
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait() // reap the daemon if it exits early
+	}()
+
+	// wait for the daemon to listen on the socket
+	delay := 10 * time.Millisecond
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if ret, err = Open(ctx, dialer, nil); err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return ret, ctx.Err()
+		case e := <-done: // failed, or another daemon won the race
+			if ret, err = Open(ctx, dialer, nil); err == nil {
+				return
+			}
+			if e == nil {
+				e = err
+			}
+			return ret, fmt.Errorf("langserver: daemon exited: %w (see %s)", e, f.Name())
+		case <-time.After(delay):
+		}
+		if delay < 500*time.Millisecond {
+			delay *= 2
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
@@ -0,0 +1,231 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package langserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goplus/xgo/x/jsonrpc2"
+)
+
+const envTestDaemon = "XGO_LANGSERVER_TEST_DAEMON"
+
+// TestMain lets the test binary act as `xgo serve -daemon`, which is executed
+// by DialDaemon.
+func TestMain(m *testing.M) {
+	if dir := os.Getenv(envTestDaemon); dir != "" {
+		if dir == "fail" {
+			os.Exit(1)
+		}
+		conf := &DaemonConfig{Dir: dir, IdleTimeout: 200 * time.Millisecond}
+		if err := ServeDaemon(context.Background(), conf); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// shortTempDir returns a temporary directory whose path is short enough for
+// Unix sockets.
+func shortTempDir(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "ls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+func setXGoRoot(t *testing.T) {
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XGOROOT", root)
+}
+
+func writeModule(t *testing.T) string {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":       "module example.com/foo\n\ngo 1.21\n",
+		"main.xgo":     "echo \"Hello\"\n",
+		"sub/sub.xgo":  "package sub\n\nfunc Hello() string {\n\treturn \"Hello\"\n}\n",
+		"sub2/sub.xgo": "package sub2\n\nfunc Hi() string {\n\treturn \"Hi\"\n}\n",
+		"bad/bad.xgo":  "package bad\n\nfunc Bad() {\n\tundefinedFoo()\n}\n",
+	}
+	for name, data := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(file), 0755)
+		if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func hasAutogen(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "xgo_autogen.go"))
+	return err == nil
+}
+
+func dialTestDaemon(t *testing.T, ctx context.Context, socket string) Client {
+	t.Helper()
+	dialer := jsonrpc2.NetDialer("unix", socket, net.Dialer{})
+	for i := 0; ; i++ {
+		c, err := Open(ctx, dialer, nil)
+		if err == nil {
+			return c
+		}
+		if i == 100 {
+			t.Fatal("Open:", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestDaemon(t *testing.T) {
+	setXGoRoot(t)
+	ctx := context.Background()
+	conf := &DaemonConfig{Dir: shortTempDir(t), IdleTimeout: 200 * time.Millisecond}
+	socket, err := conf.SocketFile()
+	if err != nil {
+		t.Fatal("SocketFile:", err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- ServeDaemon(ctx, conf)
+	}()
+
+	c1 := dialTestDaemon(t, ctx, socket)
+	st, err := c1.Status(ctx)
+	if err != nil {
+		t.Fatal("Status:", err)
+	}
+	if st.Pid != os.Getpid() || st.Socket != socket || st.Clients != 1 || len(st.Modules) != 0 || st.Started.IsZero() {
+		t.Fatalf("Status: %+v", st)
+	}
+	if err = ServeDaemon(ctx, conf); err != ErrDaemonRunning {
+		t.Fatal("ServeDaemon again:", err)
+	}
+
+	// clients working in the same module share the importer cache of it
+	mod := writeModule(t)
+	c2 := dialTestDaemon(t, ctx, socket)
+	if err = c1.GenGo(ctx, mod); err != nil {
+		t.Fatal("GenGo:", err)
+	}
+	if !hasAutogen(mod) {
+		t.Fatal("GenGo: no xgo_autogen.go")
+	}
+	// relative paths are resolved in the working directory of the client
+	t.Chdir(mod)
+	if err = c2.GenGo(ctx, "example.com/foo/sub", "./sub2"); err != nil {
+		t.Fatal("GenGo:", err)
+	}
+	if !hasAutogen(filepath.Join(mod, "sub")) || !hasAutogen(filepath.Join(mod, "sub2")) {
+		t.Fatal("GenGo: no xgo_autogen.go in sub or sub2")
+	}
+	// errors of generating Go code are returned to the client
+	if err = c2.GenGo(ctx, "./sub", "./bad"); err == nil || !strings.Contains(err.Error(), "undefined: undefinedFoo") {
+		t.Fatal("GenGo bad:", err)
+	}
+	if st, err = c2.Status(ctx); err != nil || st.Clients != 2 || len(st.Modules) != 1 || st.Modules[0] != mod {
+		t.Fatalf("Status: %+v %v", st, err)
+	}
+
+	c1.Close()
+	c2.Close()
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Fatal("ServeDaemon:", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeDaemon: not shut down after being idle")
+	}
+}
+
+func TestSharedModConf(t *testing.T) {
+	setXGoRoot(t)
+	mod := writeModule(t)
+	h := newHandle(false)
+	a, err := h.modConfOf(mod)
+	if err != nil {
+		t.Fatal("modConfOf:", err)
+	}
+	if b, _ := h.modConfOf(mod); a != b {
+		t.Fatal("modConfOf: configuration isn't shared")
+	}
+	if st := h.Status(); len(st.Modules) != 1 || st.Modules[0] != mod || st.Socket != "" {
+		t.Fatalf("Status: %+v", st)
+	}
+	h.invalidate(mod)
+	if st := h.Status(); len(st.Modules) != 0 {
+		t.Fatalf("Status after invalidate: %+v", st)
+	}
+	if b, _ := h.modConfOf(mod); a == b {
+		t.Fatal("modConfOf: configuration isn't reloaded after invalidate")
+	}
+}
+
+func TestDialDaemon(t *testing.T) {
+	ctx := context.Background()
+	conf := &DaemonConfig{Dir: shortTempDir(t)}
+	t.Setenv(envTestDaemon, conf.Dir)
+	c, err := DialDaemon(ctx, conf, os.Args[0])
+	if err != nil {
+		t.Fatal("DialDaemon:", err)
+	}
+	st, err := c.Status(ctx)
+	if err != nil || st.Pid == os.Getpid() || st.Clients != 1 {
+		t.Fatalf("Status: %+v %v", st, err)
+	}
+
+	// ServeAndDial shares the running daemon
+	c2 := ServeAndDial(&ServeAndDialConfig{Daemon: conf}, os.Args[0], "serve")
+	st2, err := c2.Status(ctx)
+	if err != nil || st2.Pid != st.Pid || st2.Clients != 2 {
+		t.Fatalf("Status: %+v %v", st2, err)
+	}
+	c.Close()
+	c2.Close()
+}
+
+func TestDialDaemonFail(t *testing.T) {
+	conf := &DaemonConfig{Dir: shortTempDir(t)}
+	t.Setenv(envTestDaemon, "fail")
+	start := time.Now()
+	_, err := DialDaemon(context.Background(), conf, os.Args[0])
+	if err == nil || !strings.Contains(err.Error(), "daemon exited") {
+		t.Fatal("DialDaemon:", err)
+	}
+	if d := time.Since(start); d > 3*time.Second {
+		t.Fatal("DialDaemon: took", d)
+	}
+	var exitErr interface{ ExitCode() int }
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatal("DialDaemon: unexpected error", err)
+	}
+}
@@ -41,6 +41,13 @@ type ServeAndDialConfig struct {
 	// OnError is to customize how to process errors (optional).
 	// It should panic in any case.
 	OnError func(err error)
+
+	// NoDaemon disables sharing a LangServer daemon among clients (see
+	// DialDaemon), so that a dedicated LangServer is always executed.
+	NoDaemon bool
+
+	// Daemon is the configuration of the shared daemon (optional).
+	Daemon *DaemonConfig
 }
 
 const (
@@ -81,8 +88,10 @@ func tooOld(d fs.DirEntry) bool {
 	return false
 }
 
-// ServeAndDial executes a command as a LangServer, makes a new connection to it
-// and returns a client of the LangServer based on the connection.
+// ServeAndDial connects to the LangServer daemon shared by clients, which is
+// started by executing `xgoCmd args... -daemon` if it isn't running. If that
+// fails (or conf.NoDaemon is set), it executes `xgoCmd args...` as a dedicated
+// LangServer instead. It returns a client of the LangServer.
 func ServeAndDial(conf *ServeAndDialConfig, xgoCmd string, args ...string) Client {
 	if conf == nil {
 		conf = new(ServeAndDialConfig)
@@ -91,6 +100,20 @@ func ServeAndDial(conf *ServeAndDialConfig, xgoCmd string, args ...string) Clien
 	if onErr == nil {
 		onErr = fatal
 	}
+	if !conf.NoDaemon {
+		daemonArgs := append(args[:len(args):len(args)], "-daemon")
+		c, err := DialDaemon(context.Background(), conf.Daemon, xgoCmd, daemonArgs...)
+		if err == nil {
+			return c
+		}
+		log.Println("==> ServeAndDial: daemon unavailable, serve alone:", err)
+	}
+	return serveAndDial(onErr, xgoCmd, args...)
+}
+
+// serveAndDial executes a command as a dedicated LangServer, makes a new
+// connection to it and returns a client of the LangServer.
+func serveAndDial(onErr func(err error), xgoCmd string, args ...string) Client {
 
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -18,14 +18,18 @@ package langserver
 
 import (
 	"context"
-	"encoding/json"
+	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/goplus/xgo/tool"
 	"github.com/goplus/xgo/x/jsonrpc2"
 	"github.com/goplus/xgo/x/xgoprojs"
+	"github.com/qiniu/x/errors"
 )
 
 // -----------------------------------------------------------------------------
@@ -41,17 +45,26 @@ type Config struct {
 	// Framer allows control over the message framing and encoding.
 	// If nil, HeaderFramer will be used.
 	Framer jsonrpc2.Framer
+
+	// UseCache enables the importer cache file and the code generation cache
+	// (see tool.NewDefaultConf). They are disabled by default because they
+	// write to the user cache directory.
+	UseCache bool
 }
 
 // NewServer creates a new LangServer and returns it.
 func NewServer(ctx context.Context, listener Listener, conf *Config) (ret *Server) {
-	h := newHandle()
+	return newServer(ctx, listener, conf, newHandle(conf != nil && conf.UseCache))
+}
+
+func newServer(ctx context.Context, listener Listener, conf *Config, h *handler) (ret *Server) {
 	ret = jsonrpc2.NewServer(ctx, listener, jsonrpc2.BinderFunc(
 		func(ctx context.Context, c *jsonrpc2.Connection) (ret jsonrpc2.ConnectionOptions) {
 			if conf != nil {
 				ret.Framer = conf.Framer
 			}
-			ret.Handler = h
+			ret.Handler = h.mux
+			ret.Preempter = jsonrpc2.CancelPreempter(c)
 			// ret.OnInternalError = h.OnInternalError
 			return
 		}))
@@ -65,15 +78,157 @@ func NewServer(ctx context.Context, listener Listener, conf *Config) (ret *Serve
 type none = struct{}
 
 type handler struct {
+	mutex     sync.Mutex
+	dirty     map[string]none
+	mods      map[string]*modConf // module root => shared configuration
+	notify    chan none
+	mux       *jsonrpc2.Mux
+	confFlags tool.ConfFlags
+
+	server  *Server
+	socket  string       // Unix socket the daemon listens on
+	clients atomic.Int32 // number of connected clients of the daemon
+	started time.Time
+}
+
+// modConf is a configuration shared by all clients working in a module, so
+// that they share the same tool.Importer cache.
+type modConf struct {
 	mutex sync.Mutex
-	dirty map[string]none
+	conf  *tool.Config
+}
+
+func newHandle(useCache bool) *handler {
+	p := &handler{
+		dirty:   make(map[string]none),
+		mods:    make(map[string]*modConf),
+		notify:  make(chan none, 1),
+		mux:     jsonrpc2.NewMux(),
+		started: time.Now(),
+	}
+	if !useCache {
+		p.confFlags = tool.ConfFlagNoCacheFile | tool.ConfFlagNoGenCache
+	}
+	p.mux.Use(jsonrpc2.Recover)
+	jsonrpc2.RegisterNotify(p.mux, methodChanged, func(ctx context.Context, files []string) error {
+		p.Changed(files)
+		return nil
+	})
+	jsonrpc2.RegisterFunc(p.mux, methodGenGo, func(ctx context.Context, params *genGoParams) (any, error) {
+		return nil, p.genGo(params.Dir, params.Pattern...)
+	})
+	jsonrpc2.RegisterFunc(p.mux, methodStatus, func(ctx context.Context, _ none) (*Status, error) {
+		return p.Status(), nil
+	})
+	return p
+}
 
-	server *Server
+// modConfOf returns the shared configuration of the module rooted at root.
+func (p *handler) modConfOf(root string) (ret *modConf, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if ret = p.mods[root]; ret != nil {
+		return
+	}
+	conf, err := tool.NewDefaultConf(root, p.confFlags)
+	if err != nil {
+		return
+	}
+	conf.Parallel = runtime.GOMAXPROCS(0)
+	ret = &modConf{conf: conf}
+	p.mods[root] = ret
+	return
 }
 
-func newHandle() *handler {
-	return &handler{
-		dirty: make(map[string]none),
+// invalidate drops the shared configuration of the module rooted at root,
+// because some of its source files have been changed.
+func (p *handler) invalidate(root string) {
+	p.mutex.Lock()
+	mod := p.mods[root]
+	delete(p.mods, root)
+	p.mutex.Unlock()
+	if mod != nil {
+		mod.mutex.Lock()
+		defer mod.mutex.Unlock()
+		mod.conf.UpdateCache()
+	}
+}
+
+// genGo generates Go code for the projects specified by pattern, using the
+// shared configuration of the module which they belong to. Relative
+// directories and package paths in pattern are resolved in workDir, the
+// working directory of the client (a daemon doesn't share it).
+func (p *handler) genGo(workDir string, pattern ...string) (err error) {
+	projs, err := xgoprojs.ParseAll(pattern...)
+	if err != nil {
+		return
+	}
+	if workDir == "" {
+		if workDir, err = os.Getwd(); err != nil {
+			return
+		}
+	}
+	var errs errors.List
+	for _, proj := range projs {
+		var dir, pkgPath string
+		switch v := proj.(type) {
+		case *xgoprojs.DirProj:
+			dir = v.Dir
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(workDir, dir)
+			}
+		case *xgoprojs.PkgPathProj:
+			if v.Path == "builtin" {
+				continue
+			}
+			dir, pkgPath = workDir, v.Path
+		default:
+			continue
+		}
+		root := modRoot(dir)
+		if root == "" { // not in a module
+			root = workDir
+		}
+		mod, e := p.modConfOf(root)
+		if e != nil {
+			e = genGoProj(workDir, dir, pkgPath, nil)
+		} else {
+			mod.mutex.Lock()
+			e = genGoProj(workDir, dir, pkgPath, mod.conf)
+			mod.conf.UpdateCache()
+			mod.mutex.Unlock()
+		}
+		if e != nil {
+			errs.Add(e)
+		}
+	}
+	return errs.ToError()
+}
+
+func genGoProj(workDir, dir, pkgPath string, conf *tool.Config) (err error) {
+	if pkgPath != "" {
+		_, _, err = tool.GenGoPkgPathEx(workDir, pkgPath, conf, true, 0)
+	} else {
+		_, _, err = tool.GenGoEx(dir, conf, true, 0)
+	}
+	return
+}
+
+// Status returns the status of the LangServer.
+func (p *handler) Status() *Status {
+	p.mutex.Lock()
+	mods := make([]string, 0, len(p.mods))
+	for root := range p.mods {
+		mods = append(mods, root)
+	}
+	p.mutex.Unlock()
+	sort.Strings(mods)
+	return &Status{
+		Pid:     os.Getpid(),
+		Socket:  p.socket,
+		Clients: int(p.clients.Load()),
+		Modules: mods,
+		Started: p.started,
 	}
 }
 
@@ -84,22 +239,60 @@ func (p *handler) OnInternalError(err error) {
 */
 
 func (p *handler) runLoop() {
-	const (
-		duration = time.Second / 100
-	)
-	for {
-		var dir string
+	for range p.notify {
 		p.mutex.Lock()
-		for dir = range p.dirty {
-			delete(p.dirty, dir)
-			break
+		dirs := make([]string, 0, len(p.dirty))
+		for dir := range p.dirty {
+			dirs = append(dirs, dir)
 		}
+		clear(p.dirty)
 		p.mutex.Unlock()
-		if dir == "" {
-			time.Sleep(duration)
+		if len(dirs) == 0 {
 			continue
 		}
-		tool.GenGoEx(dir, nil, true, tool.GenFlagPrompt)
+		sort.Strings(dirs)
+		mods := make(map[string][]string)
+		for _, dir := range dirs {
+			root := modRoot(dir)
+			mods[root] = append(mods[root], dir)
+		}
+		for root, dirs := range mods {
+			p.genGoDirs(root, dirs)
+		}
+	}
+}
+
+func (p *handler) genGoDirs(root string, dirs []string) {
+	if root == "" { // not in a module
+		tool.GenGoDirs(dirs, nil, true, tool.GenFlagPrompt)
+		return
+	}
+	p.invalidate(root)
+	mod, err := p.modConfOf(root)
+	if err != nil {
+		return
+	}
+	mod.mutex.Lock()
+	defer mod.mutex.Unlock()
+	defer mod.conf.UpdateCache()
+	tool.GenGoDirs(dirs, mod.conf, true, tool.GenFlagPrompt)
+}
+
+// modRoot returns the root directory of the module which dir belongs to.
+func modRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if fi, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && !fi.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
 	}
 }
 
@@ -111,26 +304,10 @@ func (p *handler) Changed(files []string) {
 		dir := filepath.Dir(file)
 		p.dirty[dir] = none{}
 	}
-}
-
-func (p *handler) Handle(ctx context.Context, req *jsonrpc2.Request) (result any, err error) {
-	switch req.Method {
-	case methodChanged:
-		var files []string
-		err = json.Unmarshal(req.Params, &files)
-		if err != nil {
-			return
-		}
-		p.Changed(files)
-	case methodGenGo:
-		var pattern []string
-		err = json.Unmarshal(req.Params, &pattern)
-		if err != nil {
-			return
-		}
-		err = GenGo(pattern...)
+	select {
+	case p.notify <- none{}:
+	default: // runLoop is already notified
 	}
-	return
 }
 
 func GenGo(pattern ...string) (err error) {
@@ -0,0 +1,746 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package repl implements an interactive XGo session, see `xgo repl`.
+//
+// A session is an incremental main package: imports and declarations entered
+// so far, plus statements that define or update variables. Each input is
+// appended to the session, compiled by cl + gogen and run as a whole program.
+// Statements that don't change the session state (eg. `echo x`) only run
+// once, so their side effects are not repeated by later inputs.
+//
+// After each run, values of session variables are saved by encoding/gob, and
+// later runs restore them instead of replaying the statements which computed
+// them. So `t := time.Now()` keeps its value and initializers doing I/O run
+// only once. Values gob can't save without loss (pointers, interfaces, funcs,
+// channels, structs with unexported fields, ...) are still computed by
+// replaying their statements. Restored variables don't share memory, eg. two
+// slices aliasing the same array become independent.
+package repl
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/cl/outline"
+	"github.com/goplus/xgo/parser"
+	"github.com/goplus/xgo/token"
+	"github.com/goplus/xgo/tool"
+	"github.com/goplus/xgo/x/gocmd"
+	"github.com/goplus/xgo/x/typesutil"
+)
+
+// -----------------------------------------------------------------------------
+
+var (
+	// ErrNotFound is returned by Doc if the name is not found.
+	ErrNotFound = errors.New("not found")
+)
+
+// REPL represents an interactive XGo session.
+type REPL struct {
+	conf *tool.Config
+	run  *gocmd.RunConfig
+	dir  string
+
+	imports []string   // import declarations
+	decls   []string   // top-level declarations
+	vars    []savedVar // variables whose values are saved in state
+	stmts   []stmt     // statements which define or update variables
+	names   []string   // variables of the session
+	state   string     // file of saved variable values
+	nstate  int        // number of state files created
+}
+
+// A savedVar is a session variable whose value is saved after each run.
+type savedVar struct {
+	name string
+	typ  string // type of the variable in XGo syntax
+}
+
+// New creates an XGo session. conf specifies the module and importer used to
+// compile the session, and run specifies how to run the generated program.
+func New(conf *tool.Config, run *gocmd.RunConfig) (*REPL, error) {
+	dir, err := os.MkdirTemp("", "xgo-repl")
+	if err != nil {
+		return nil, err
+	}
+	return &REPL{conf: conf, run: run, dir: dir}, nil
+}
+
+// Close releases resources of the session.
+func (p *REPL) Close() error {
+	return os.RemoveAll(p.dir)
+}
+
+// Source returns the XGo source code of the session.
+func (p *REPL) Source() string {
+	return p.source(nil)
+}
+
+// Eval compiles and runs src in the session. If the last statement of src is
+// an expression with a value, its value is printed with its type.
+func (p *REPL) Eval(src string) error {
+	in, err := parseInput("input.xgo", src)
+	if err != nil {
+		return err
+	}
+	if n := len(in.stmts); n > 0 && in.stmts[n-1].expr {
+		last := &in.stmts[n-1]
+		if typ, ok := p.typeOf(in); ok {
+			last.src = fmt.Sprintf("printf \"%%v (%%s)\\n\", (%s), %s", last.src, strconv.Quote(typ))
+		}
+	}
+	return p.exec(in)
+}
+
+// Load loads imports, declarations and statements of an XGo file into the
+// session and runs the statements.
+func (p *REPL) Load(file string) error {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	in, err := parseInput(file, string(b))
+	if err != nil {
+		return err
+	}
+	return p.exec(in)
+}
+
+// TypeOf returns the type of the expression expr in the session, formatted as
+// `expr: type`.
+func (p *REPL) TypeOf(expr string) (string, error) {
+	x, err := parser.ParseExpr(expr)
+	if err != nil {
+		return "", err
+	}
+	in, err := parseInput("input.xgo", expr)
+	if err != nil {
+		return "", err
+	}
+	typ, ok := p.typeOf(in)
+	if !ok {
+		return "", fmt.Errorf("%s is not an expression with a value", typesutil.ExprString(x))
+	}
+	return typesutil.ExprString(x) + ": " + typ, nil
+}
+
+// Doc returns documentation of name declared in the session. name can also be
+// pkg.Name, where pkg is an imported package.
+func (p *REPL) Doc(name string) (string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.xgo", p.source(nil), parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+	if pkgName, sel, ok := strings.Cut(name, "."); ok {
+		return p.importDoc(f, pkgName, sel)
+	}
+	conf := p.conf
+	pkg, err := outline.NewPackage("main", &ast.Package{
+		Name:  "main",
+		Files: map[string]*ast.File{"main.xgo": f},
+	}, &outline.Config{
+		Fset:        fset,
+		LookupClass: conf.Mod.LookupClass,
+		Importer:    conf.Importer,
+	})
+	if err != nil {
+		return "", err
+	}
+	all := pkg.Outline(true)
+	qualifier := types.RelativeTo(pkg.Pkg())
+	format := func(obj types.Object, doc string) string {
+		return types.ObjectString(obj, qualifier) + "\n" + doc
+	}
+	for _, o := range all.Consts {
+		if o.Obj().Name() == name {
+			return format(o.Obj(), o.Doc()), nil
+		}
+	}
+	for _, o := range all.Vars {
+		if o.Obj().Name() == name {
+			return format(o.Obj(), o.Doc()), nil
+		}
+	}
+	for _, o := range all.Funcs {
+		if o.Obj().Name() == name {
+			return format(o.Obj(), o.Doc()), nil
+		}
+	}
+	for _, o := range all.Types {
+		if o.Obj().Name() == name {
+			return format(o.ObjWith(true), o.Doc()), nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+func (p *REPL) importDoc(f *ast.File, pkgName, name string) (string, error) {
+	for _, spec := range f.Imports {
+		pkgPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if spec.Name != nil {
+			if spec.Name.Name != pkgName {
+				continue
+			}
+		} else if pkgPath != pkgName && !strings.HasSuffix(pkgPath, "/"+pkgName) {
+			continue
+		}
+		pkg, err := p.conf.Importer.Import(pkgPath)
+		if err != nil {
+			return "", err
+		}
+		if obj := pkg.Scope().Lookup(name); obj != nil {
+			return types.ObjectString(obj, qualifierOf(pkg)), nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+// typeOf returns the type of the last statement of in, if it's an expression
+// with a single value.
+func (p *REPL) typeOf(in *input) (string, bool) {
+	if typ, ok := p.checkLast(in, func(info *typesutil.Info, last ast.Stmt) types.Type {
+		if stmt, ok := last.(*ast.ExprStmt); ok {
+			if tv, ok := info.Types[stmt.X]; ok && tv.IsValue() {
+				return tv.Type
+			}
+		}
+		return nil
+	}); ok {
+		return typ, true
+	}
+	// types of some expressions (eg. list comprehensions) aren't recorded, so
+	// we try to assign the expression to a variable
+	n := len(in.stmts)
+	assign := *in
+	assign.stmts = append(in.stmts[:n-1:n-1], stmt{src: replVar + " := (" + in.stmts[n-1].src + ")"})
+	return p.checkLast(&assign, func(info *typesutil.Info, last ast.Stmt) types.Type {
+		if stmt, ok := last.(*ast.AssignStmt); ok && len(stmt.Lhs) == 1 {
+			if obj := info.Defs[stmt.Lhs[0].(*ast.Ident)]; obj != nil {
+				return obj.Type()
+			}
+		}
+		return nil
+	})
+}
+
+const replVar = "__xgo_repl_v"
+
+// checkLast type checks the session with input in, and calls typeOf to get
+// the type of the last statement.
+func (p *REPL) checkLast(in *input, typeOf func(info *typesutil.Info, last ast.Stmt) types.Type) (string, bool) {
+	_, body, pkg, info, ok := p.check(in)
+	if !ok || len(body) == 0 {
+		return "", false
+	}
+	typ := typeOf(info, body[len(body)-1])
+	if typ == nil || typ == types.Typ[types.Invalid] {
+		return "", false
+	}
+	if _, ok := typ.(*types.Tuple); ok {
+		return "", false
+	}
+	return types.TypeString(typ, qualifierOf(pkg)), true
+}
+
+// check type checks the session with input in. It returns statements of the
+// main function of the session.
+func (p *REPL) check(in *input) (f *ast.File, body []ast.Stmt, pkg *types.Package, info *typesutil.Info, ok bool) {
+	conf := p.conf
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "main.xgo", p.source(in), parser.ParseComments)
+	if err != nil {
+		return
+	}
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Shadow {
+			body = fn.Body.List
+		}
+	}
+	pkg = types.NewPackage("main", "main")
+	info = &typesutil.Info{
+		Types: make(map[ast.Expr]types.TypeAndValue),
+		Defs:  make(map[*ast.Ident]types.Object),
+		Uses:  make(map[*ast.Ident]types.Object),
+	}
+	chk := typesutil.NewChecker(&types.Config{
+		Importer: conf.Importer,
+		Error:    func(err error) {}, // errors are reported when the session runs
+	}, &typesutil.Config{
+		Types: pkg,
+		Fset:  fset,
+		Mod:   conf.Mod,
+	}, nil, info)
+	chk.Files(nil, []*ast.File{f})
+	return f, body, pkg, info, true
+}
+
+// varsToSave returns session variables, including the ones defined by in,
+// whose values can be saved and restored.
+func (p *REPL) varsToSave(in *input) (vars []savedVar) {
+	f, body, pkg, info, ok := p.check(in)
+	if !ok {
+		return
+	}
+	idx := make(map[string]int)
+	define := func(id *ast.Ident) {
+		v, ok := info.Defs[id].(*types.Var)
+		if !ok {
+			return
+		}
+		typ, ok := typeString(v.Type(), pkg, f)
+		if !ok || !persistable(v.Type(), pkg, make(map[types.Type]bool)) {
+			typ = ""
+		}
+		if i, ok := idx[id.Name]; ok {
+			vars[i].typ = typ
+			return
+		}
+		idx[id.Name] = len(vars)
+		vars = append(vars, savedVar{id.Name, typ})
+	}
+	// restored variables are declared at package level
+	for _, v := range p.vars {
+		for _, decl := range f.Decls {
+			if d, ok := decl.(*ast.GenDecl); ok && d.Tok == token.VAR && len(d.Specs) == 1 {
+				if spec := d.Specs[0].(*ast.ValueSpec); spec.Names[0].Name == v.name {
+					define(spec.Names[0])
+				}
+			}
+		}
+	}
+	for _, s := range body {
+		switch v := s.(type) {
+		case *ast.AssignStmt:
+			if v.Tok == token.DEFINE {
+				for _, lhs := range v.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok {
+						define(id)
+					}
+				}
+			}
+		case *ast.DeclStmt:
+			if d, ok := v.Decl.(*ast.GenDecl); ok && d.Tok == token.VAR {
+				for _, spec := range d.Specs {
+					for _, id := range spec.(*ast.ValueSpec).Names {
+						define(id)
+					}
+				}
+			}
+		}
+	}
+	n := 0
+	for _, v := range vars {
+		if v.typ != "" {
+			vars[n] = v
+			n++
+		}
+	}
+	return vars[:n]
+}
+
+// typeString returns typ in XGo syntax. It fails if typ refers to a package
+// not imported by the session.
+func typeString(typ types.Type, pkg *types.Package, f *ast.File) (string, bool) {
+	ok := true
+	ret := types.TypeString(typ, func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		for _, spec := range f.Imports {
+			if path, err := strconv.Unquote(spec.Path.Value); err != nil || path != other.Path() {
+				continue
+			}
+			if spec.Name == nil {
+				return other.Name()
+			}
+			if name := spec.Name.Name; name != "_" && name != "." {
+				return name
+			}
+		}
+		ok = false
+		return other.Name()
+	})
+	return ret, ok
+}
+
+// persistable reports whether values of typ can be saved and restored by
+// encoding/gob without loss.
+func persistable(typ types.Type, pkg *types.Package, seen map[types.Type]bool) bool {
+	if seen[typ] {
+		return true
+	}
+	if t, ok := typ.(*types.Named); ok {
+		if obj := t.Obj(); obj.Pkg() == pkg && obj.Parent() != pkg.Scope() {
+			return false // types declared in the main function
+		}
+		if hasMethods(typ, "GobEncode", "GobDecode") || hasMethods(typ, "MarshalBinary", "UnmarshalBinary") {
+			return true
+		}
+		seen[typ] = true
+	}
+	switch t := typ.Underlying().(type) {
+	case *types.Basic:
+		return t.Info()&types.IsUntyped == 0 && t.Kind() != types.UnsafePointer && t.Kind() != types.Invalid
+	case *types.Array:
+		return persistable(t.Elem(), pkg, seen)
+	case *types.Slice:
+		return persistable(t.Elem(), pkg, seen)
+	case *types.Map:
+		return persistable(t.Key(), pkg, seen) && persistable(t.Elem(), pkg, seen)
+	case *types.Struct:
+		for i, n := 0, t.NumFields(); i < n; i++ {
+			if fld := t.Field(i); !fld.Exported() || !persistable(fld.Type(), pkg, seen) {
+				return false
+			}
+		}
+		return t.NumFields() > 0
+	}
+	return false
+}
+
+func hasMethods(typ types.Type, names ...string) bool {
+	mset := types.NewMethodSet(types.NewPointer(typ))
+	for _, name := range names {
+		if mset.Lookup(nil, name) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func qualifierOf(pkg *types.Package) types.Qualifier {
+	return func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+}
+
+// exec runs the session with input in, and commits in if it succeeds.
+func (p *REPL) exec(in *input) error {
+	vars := p.varsToSave(in)
+	state := filepath.Join(p.dir, "state"+strconv.Itoa(p.nstate+1)+".gob")
+	file := filepath.Join(p.dir, "main.xgo")
+	if err := os.WriteFile(file, []byte(p.program(in, vars, state)), 0644); err != nil {
+		return err
+	}
+	autogen := filepath.Join(p.dir, "xgo_autogen.go")
+	if err := tool.RunFiles(autogen, []string{file}, nil, p.conf, p.run); err != nil {
+		return err
+	}
+	p.nstate++
+	p.commit(in)
+	if _, err := os.Stat(state); err == nil { // the program may exit before saving
+		p.saved(vars, state)
+	}
+	return nil
+}
+
+func (p *REPL) commit(in *input) {
+	p.imports = append(p.imports, in.imports...)
+	p.decls = append(p.decls, in.decls...)
+	for _, stmt := range in.stmts {
+		if stmt.keep {
+			p.stmts = append(p.stmts, stmt)
+			p.names = append(p.names, stmt.names...)
+		}
+	}
+}
+
+// saved records that values of vars are saved in state, so statements which
+// only update them needn't run again.
+func (p *REPL) saved(vars []savedVar, state string) {
+	if p.state != "" {
+		os.Remove(p.state)
+	}
+	p.vars, p.state = vars, state
+	isSaved := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		isSaved[v.name] = true
+	}
+	stmts := p.stmts[:0]
+	for _, stmt := range p.stmts {
+		if stmt.fixed || !allSaved(stmt.targets, isSaved) {
+			stmts = append(stmts, stmt)
+		}
+	}
+	p.stmts = stmts
+}
+
+func allSaved(names []string, isSaved map[string]bool) bool {
+	for _, name := range names {
+		if !isSaved[name] {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	replGob = "__xgo_repl_gob"
+	replOS  = "__xgo_repl_os"
+)
+
+// replFuncs loads and saves values of session variables.
+const replFuncs = `func __xgo_repl_load(file string, vars ...any) {
+	f, err := ` + replOS + `.Open(file)
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	dec := ` + replGob + `.NewDecoder(f)
+	for _, v := range vars {
+		if err := dec.Decode(v); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func __xgo_repl_save(file string, vars ...any) {
+	f, err := ` + replOS + `.Create(file)
+	if err != nil {
+		panic(err)
+	}
+	enc := ` + replGob + `.NewEncoder(f)
+	for _, v := range vars {
+		if err := enc.Encode(v); err != nil {
+			panic(err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		panic(err)
+	}
+}
+
+`
+
+// source returns the session source with input in.
+func (p *REPL) source(in *input) string {
+	return p.program(in, nil, "")
+}
+
+// program returns the session source with input in. If state isn't empty, the
+// program restores saved variables first, and saves values of vars to state
+// at last.
+func (p *REPL) program(in *input, vars []savedVar, state string) string {
+	var b strings.Builder
+	imports, decls := p.imports, p.decls
+	if in != nil {
+		imports = append(imports[:len(imports):len(imports)], in.imports...)
+		decls = append(decls[:len(decls):len(decls)], in.decls...)
+	}
+	for _, imp := range imports {
+		b.WriteString(imp)
+		b.WriteByte('\n')
+	}
+	if state != "" {
+		fmt.Fprintf(&b, "import (\n\t%s \"encoding/gob\"\n\t%s \"os\"\n)\n", replGob, replOS)
+	}
+	for _, decl := range decls {
+		b.WriteString(decl)
+		b.WriteString("\n\n")
+	}
+	if state != "" {
+		b.WriteString(replFuncs)
+	}
+	for _, v := range p.vars {
+		fmt.Fprintf(&b, "var %s %s\n", v.name, v.typ)
+	}
+	if state != "" && len(p.vars) > 0 {
+		fmt.Fprintf(&b, "__xgo_repl_load(%s", strconv.Quote(p.state))
+		refs(&b, p.vars)
+	}
+	for _, stmt := range p.stmts {
+		b.WriteString(stmt.src)
+		b.WriteByte('\n')
+	}
+	blankUses(&b, p.names)
+	if in != nil {
+		for _, stmt := range in.stmts {
+			b.WriteString(stmt.src)
+			b.WriteByte('\n')
+			blankUses(&b, stmt.names)
+		}
+	}
+	if state != "" {
+		fmt.Fprintf(&b, "__xgo_repl_save(%s", strconv.Quote(state))
+		refs(&b, vars)
+	}
+	return b.String()
+}
+
+// refs writes the remaining arguments `, &v1, &v2, ...)` of a load or save
+// call.
+func refs(b *strings.Builder, vars []savedVar) {
+	for _, v := range vars {
+		b.WriteString(", &")
+		b.WriteString(v.name)
+	}
+	b.WriteString(")\n")
+}
+
+// blankUses avoids `declared and not used` errors of session variables.
+func blankUses(b *strings.Builder, names []string) {
+	for _, name := range names {
+		b.WriteString("_ = ")
+		b.WriteString(name)
+		b.WriteByte('\n')
+	}
+}
+
+// -----------------------------------------------------------------------------
+
+type input struct {
+	imports []string
+	decls   []string
+	stmts   []stmt
+}
+
+type stmt struct {
+	src     string
+	names   []string // variables defined by the statement
+	targets []string // variables defined or updated by the statement
+	keep    bool     // keep the statement in the session
+	fixed   bool     // statement is kept even if targets are saved
+	expr    bool     // is an expression statement
+}
+
+func parseInput(filename, src string) (in *input, err error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return
+	}
+	tf := fset.File(f.Pos())
+	text := func(from, to token.Pos) string {
+		return src[tf.Offset(from):tf.Offset(to)]
+	}
+	in = new(input)
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			from := d.Pos()
+			if d.Doc != nil {
+				from = d.Doc.Pos()
+			}
+			if d.Tok == token.IMPORT {
+				in.imports = append(in.imports, text(from, d.End()))
+			} else {
+				in.decls = append(in.decls, text(from, d.End()))
+			}
+		case *ast.FuncDecl:
+			if d.Shadow || (d.Recv == nil && d.Name.Name == "main") {
+				for _, s := range d.Body.List {
+					in.stmts = append(in.stmts, newStmt(s, text))
+				}
+				continue
+			}
+			from := d.Pos()
+			if d.Doc != nil {
+				from = d.Doc.Pos()
+			}
+			in.decls = append(in.decls, text(from, d.End()))
+		}
+	}
+	return
+}
+
+func newStmt(s ast.Stmt, text func(from, to token.Pos) string) stmt {
+	ret := stmt{src: text(s.Pos(), s.End())}
+	switch v := s.(type) {
+	case *ast.AssignStmt:
+		ret.keep = true
+		if v.Tok == token.DEFINE {
+			for _, lhs := range v.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok && id.Name != "_" {
+					ret.names = append(ret.names, id.Name)
+				}
+			}
+		}
+		for _, lhs := range v.Lhs {
+			ret.addTarget(lhs)
+		}
+	case *ast.DeclStmt:
+		ret.keep = true
+		if d, ok := v.Decl.(*ast.GenDecl); ok && d.Tok == token.VAR {
+			for _, spec := range d.Specs {
+				for _, id := range spec.(*ast.ValueSpec).Names {
+					if id.Name != "_" {
+						ret.names = append(ret.names, id.Name)
+					}
+				}
+			}
+			ret.targets = ret.names
+		} else {
+			ret.fixed = true
+		}
+	case *ast.IncDecStmt:
+		ret.keep = true
+		ret.addTarget(v.X)
+	case *ast.ExprStmt:
+		ret.expr = true
+		if call, ok := v.X.(*ast.CallExpr); ok && call.NoParenEnd != token.NoPos {
+			// use `f(args)` instead of the command style `f args` so that
+			// the statement can be used as an expression
+			args := ""
+			if n := len(call.Args); n > 0 {
+				args = text(call.Args[0].Pos(), call.Args[n-1].End())
+			}
+			ret.src = text(call.Fun.Pos(), call.Fun.End()) + "(" + args + ")"
+		}
+	}
+	return ret
+}
+
+// addTarget adds the variable updated by assigning to lhs, eg. `a` of
+// `a.b[i] = v`.
+func (p *stmt) addTarget(lhs ast.Expr) {
+	for {
+		switch v := lhs.(type) {
+		case *ast.Ident:
+			if v.Name != "_" {
+				p.targets = append(p.targets, v.Name)
+			}
+			return
+		case *ast.SelectorExpr:
+			lhs = v.X
+		case *ast.IndexExpr:
+			lhs = v.X
+		case *ast.StarExpr:
+			lhs = v.X
+		case *ast.ParenExpr:
+			lhs = v.X
+		default:
+			p.fixed = true
+			return
+		}
+	}
+}
+
+// -----------------------------------------------------------------------------
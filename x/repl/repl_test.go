@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2025 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package repl
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goplus/xgo/tool"
+)
+
+func newTestREPL(t *testing.T) *REPL {
+	root, _ := filepath.Abs("../..")
+	t.Setenv("XGOROOT", root)
+	conf, err := tool.NewDefaultConf(".", tool.ConfFlagNoTestFiles|tool.ConfFlagNoCacheFile|tool.ConfFlagNoGenCache)
+	if err != nil {
+		t.Fatal("NewDefaultConf:", err)
+	}
+	return &REPL{conf: conf}
+}
+
+func TestParseInput(t *testing.T) {
+	in, err := parseInput("input.xgo", `import "strings"
+
+// double returns 2*x.
+func double(x int) int {
+	return 2 * x
+}
+
+x, _ := 1, 2
+var y = "hi"
+x++
+echo strings.ToUpper(y)
+double 21
+`)
+	if err != nil {
+		t.Fatal("parseInput:", err)
+	}
+	if len(in.imports) != 1 || in.imports[0] != `import "strings"` {
+		t.Fatal("imports:", in.imports)
+	}
+	if len(in.decls) != 1 || in.decls[0] != "// double returns 2*x.\nfunc double(x int) int {\n\treturn 2 * x\n}" {
+		t.Fatalf("decls: %q\n", in.decls)
+	}
+	type stmtT struct {
+		src   string
+		names int
+		keep  bool
+		expr  bool
+	}
+	expected := []stmtT{
+		{"x, _ := 1, 2", 1, true, false},
+		{`var y = "hi"`, 1, true, false},
+		{"x++", 0, true, false},
+		{"echo(strings.ToUpper(y))", 0, false, true},
+		{"double(21)", 0, false, true},
+	}
+	if len(in.stmts) != len(expected) {
+		t.Fatal("stmts:", in.stmts)
+	}
+	for i, e := range expected {
+		s := in.stmts[i]
+		if s.src != e.src || len(s.names) != e.names || s.keep != e.keep || s.expr != e.expr {
+			t.Fatalf("stmts[%d]: %+v\n", i, s)
+		}
+	}
+}
+
+func TestTypeOf(t *testing.T) {
+	p := newTestREPL(t)
+	in, err := parseInput("input.xgo", "func double(x int) int { return 2 * x }\nx := 1.5\n")
+	if err != nil {
+		t.Fatal("parseInput:", err)
+	}
+	p.commit(in)
+	if p.Source() != "func double(x int) int { return 2 * x }\n\nx := 1.5\n_ = x\n" {
+		t.Fatalf("Source: %q\n", p.Source())
+	}
+	cases := []struct {
+		expr, typ string
+	}{
+		{"x", "x: float64"},
+		{"double", "double: func(x int) int"},
+		{"double(2)", "double(2): int"},
+		{"1 + 2", "1 + 2: untyped int"},
+	}
+	for _, c := range cases {
+		typ, err := p.TypeOf(c.expr)
+		if err != nil || typ != c.typ {
+			t.Fatal("TypeOf:", c.expr, typ, err)
+		}
+	}
+	in, _ = parseInput("input.xgo", "[v*2 for v in [1, 2, 3]]")
+	if typ, ok := p.typeOf(in); !ok || typ != "[]int" {
+		t.Fatal("typeOf(comprehension):", typ, ok)
+	}
+	if _, err := p.TypeOf("echo 1"); err == nil {
+		t.Fatal("TypeOf(echo 1): no error")
+	}
+}
+
+func TestDoc(t *testing.T) {
+	p := newTestREPL(t)
+	in, err := parseInput("input.xgo", `import "strings"
+
+// double returns 2*x.
+func double(x int) int {
+	return 2 * x
+}
+`)
+	if err != nil {
+		t.Fatal("parseInput:", err)
+	}
+	p.commit(in)
+	if doc, err := p.Doc("double"); err != nil || doc != "func double(x int) int\ndouble returns 2*x.\n" {
+		t.Fatalf("Doc(double): %q %v\n", doc, err)
+	}
+	if doc, err := p.Doc("strings.ToUpper"); err != nil || doc != "func ToUpper(s string) string" {
+		t.Fatalf("Doc(strings.ToUpper): %q %v\n", doc, err)
+	}
+	if _, err := p.Doc("unknown"); err != ErrNotFound {
+		t.Fatal("Doc(unknown):", err)
+	}
+}
+
+func TestEvalSavedVars(t *testing.T) {
+	p := newTestREPL(t)
+	p.dir = t.TempDir()
+	var out strings.Builder
+	p.run = p.conf.NewGoCmdConf()
+	p.run.Run = func(cmd *exec.Cmd) error {
+		cmd.Stdout = &out
+		return cmd.Run()
+	}
+	eval := func(src string) string {
+		t.Helper()
+		out.Reset()
+		if err := p.Eval(src); err != nil {
+			t.Fatalf("Eval(%q): %v\n", src, err)
+		}
+		return out.String()
+	}
+	eval("import \"time\"\n\nt := time.Now().UnixNano()\nn := 1\nf := func() int { return n }\necho \"init\"")
+	first := eval("t")
+	if strings.Contains(first, "init") || !strings.HasSuffix(first, " (int64)\n") {
+		t.Fatal("t:", first)
+	}
+	if second := eval("t"); second != first {
+		t.Fatal("t changed:", first, second)
+	}
+	if ret := eval("n++\nn"); ret != "2 (int)\n" {
+		t.Fatal("n:", ret)
+	}
+	if ret := eval("f()"); ret != "2 (int)\n" { // f refers to the restored n
+		t.Fatal("f():", ret)
+	}
+	if ret := eval("n"); ret != "2 (int)\n" {
+		t.Fatal("n:", ret)
+	}
+	src := p.Source()
+	if strings.Contains(src, "time.Now") || !strings.Contains(src, "var t int64\n") || !strings.Contains(src, "f := func") {
+		t.Fatalf("Source: %q\n", src)
+	}
+}
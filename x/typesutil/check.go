@@ -256,6 +256,16 @@ func DeleteObjects(scope *types.Scope, files []*goast.File) objMapT {
 	return objMap
 }
 
+// NewError converts a compile error reported by the XGo compiler, or a
+// types.Error reported by the Go type checker, to an Error. It returns false
+// if err is not such an error.
+func NewError(fset *token.FileSet, err error) (Error, bool) {
+	if _, ok := err.(types.Error); ok {
+		return convGoErr(err)
+	}
+	return convErr(fset, err)
+}
+
 func convErr(fset *token.FileSet, e error) (ret Error, ok bool) {
 	switch v := e.(type) {
 	case *gogen.CodeError:
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/goplus/mod/xgomod"
@@ -413,3 +414,84 @@ func gofunc() string {
 		t.Fatal("Go file functions not found in ginfo defs")
 	}
 }
+
+func TestCheckExhaustive(t *testing.T) {
+	fset := token.NewFileSet()
+	files, _, err := loadFiles(fset, "main.xgo", `
+enum Color {
+	Red
+	Green
+	Blue
+}
+
+enum Shape {
+	Circle(r float64)
+	Rect(w, h float64)
+	Empty
+}
+
+func area(s Shape) float64 {
+	switch s := s.(type) {
+	case Circle:
+		return s.r * s.r
+	}
+	return 0
+}
+
+func kind(s Shape) string {
+	switch s.(type) {
+	case Circle, Rect, Empty:
+		return "shape"
+	}
+	return ""
+}
+
+func name(c Color) string {
+	match c {
+	case Red:
+		return "red"
+	case Green if c > 0:
+		return "green"
+	}
+	return ""
+}
+
+func warm(c Color) (ok bool) {
+	match c {
+	case Red:
+		ok = true
+	case _:
+		ok = false
+	}
+	return
+}
+
+func yes(ok bool) string {
+	match ok {
+	case true:
+		return "yes"
+	}
+	return ""
+}
+`, "", nil, "", nil)
+	if err != nil {
+		t.Fatal("loadFiles failed:", err)
+	}
+	info, _, err := checkInfo(fset, files, nil, nil)
+	if err != nil {
+		t.Fatal("checkInfo failed:", err)
+	}
+	errs := typesutil.CheckExhaustive(fset, files, info)
+	var msgs []string
+	for _, e := range errs {
+		if !e.Soft {
+			t.Fatal("CheckExhaustive: not a soft error:", e)
+		}
+		msgs = append(msgs, e.Error())
+	}
+	if ret := strings.Join(msgs, "\n"); ret != `main.xgo:15:2: type switch is not exhaustive: missing case Empty, Rect
+main.xgo:31:2: match is not exhaustive: missing case Blue, Green
+main.xgo:51:2: match is not exhaustive: missing case false` {
+		t.Fatal("CheckExhaustive:", ret)
+	}
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2026 The XGo Authors (xgo.dev). All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package typesutil
+
+import (
+	"go/types"
+	"strings"
+
+	"github.com/goplus/xgo/ast"
+	"github.com/goplus/xgo/cl"
+	"github.com/goplus/xgo/token"
+)
+
+// -----------------------------------------------------------------------------
+
+// CheckExhaustive reports match statements and type switches in files that
+// have no default case and don't handle all values of a type with a finite
+// set of values: bool, a named type with constants (eg. an enum of constants)
+// or a sum type declared by enum. These are the warnings reported by the XGo
+// compiler by cl.Config.Warning. info must be populated by type checking files
+// (the Types, Defs and Uses maps are required). All returned errors are soft.
+func CheckExhaustive(fset *token.FileSet, files []*ast.File, info *Info) (errs []Error) {
+	report := func(v ast.Stmt, lbrace token.Pos, what string, typ types.Type, seen map[string]bool) {
+		if missing := cl.MissingCases(typ, seen); len(missing) > 0 {
+			errs = append(errs, Error{
+				Fset: fset, Pos: v.Pos(), End: lbrace, Soft: true,
+				Msg: what + " is not exhaustive: missing case " + strings.Join(missing, ", "),
+			})
+		}
+	}
+	for _, f := range files {
+		ast.Inspect(f, func(node ast.Node) bool {
+			switch v := node.(type) {
+			case *ast.TypeSwitchStmt:
+				if x := typeSwitchSubject(v); x != nil {
+					if seen, ok := typeSwitchCases(info, v); ok {
+						report(v, v.Body.Lbrace, "type switch", info.TypeOf(x), seen)
+					}
+				}
+			case *ast.MatchStmt:
+				if seen, ok := matchCases(info, v); ok {
+					report(v, v.Body.Lbrace, "match", info.TypeOf(v.X), seen)
+				}
+			}
+			return true
+		})
+	}
+	return
+}
+
+func typeSwitchSubject(v *ast.TypeSwitchStmt) ast.Expr {
+	var x ast.Expr
+	switch stmt := v.Assign.(type) {
+	case *ast.AssignStmt:
+		x = stmt.Rhs[0]
+	case *ast.ExprStmt:
+		x = stmt.X
+	}
+	if ta, ok := x.(*ast.TypeAssertExpr); ok {
+		return ta.X
+	}
+	return nil
+}
+
+// typeSwitchCases returns names of the named types handled by a type switch.
+// It returns false if the type switch has a default case.
+func typeSwitchCases(info *Info, v *ast.TypeSwitchStmt) (seen map[string]bool, ok bool) {
+	seen = make(map[string]bool)
+	for _, stmt := range v.Body.List {
+		clause := stmt.(*ast.CaseClause)
+		if clause.List == nil {
+			return nil, false
+		}
+		for _, typ := range clause.List {
+			if t, ok := info.TypeOf(typ).(*types.Named); ok {
+				seen[t.Obj().Name()] = true
+			}
+		}
+	}
+	return seen, true
+}
+
+// matchCases returns exact values of the constants handled by a match
+// statement. It returns false if the match statement has a default case or an
+// unguarded case that matches any value.
+func matchCases(info *Info, v *ast.MatchStmt) (seen map[string]bool, ok bool) {
+	seen = make(map[string]bool)
+	for _, stmt := range v.Body.List {
+		clause := stmt.(*ast.MatchClause)
+		if clause.List == nil {
+			return nil, false
+		}
+		if clause.Guard != nil {
+			continue
+		}
+		for _, pat := range clause.List {
+			if id, ok := pat.(*ast.Ident); ok {
+				if id.Name == "_" {
+					return nil, false
+				}
+				if _, ok := info.Defs[id].(*types.Var); ok {
+					return nil, false // binds the value
+				}
+			}
+			if tv, ok := info.Types[pat]; ok && tv.Value != nil {
+				seen[tv.Value.ExactString()] = true
+			} else if c, ok := info.ObjectOf(patIdent(pat)).(*types.Const); ok {
+				seen[c.Val().ExactString()] = true
+			}
+		}
+	}
+	return seen, true
+}
+
+func patIdent(pat ast.Expr) *ast.Ident {
+	switch v := pat.(type) {
+	case *ast.Ident:
+		return v
+	case *ast.SelectorExpr:
+		return v.Sel
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
@@ -71,6 +71,10 @@ func WriteExpr(buf *bytes.Buffer, x ast.Expr) {
 		buf.WriteByte('.')
 		buf.WriteString(x.Sel.Name)
 
+	case *ast.OptChainExpr:
+		WriteExpr(buf, x.X)
+		buf.WriteByte('?')
+
 	case *ast.IndexExpr, *ast.IndexListExpr:
 		ix := typeparams.UnpackIndexExpr(x)
 		WriteExpr(buf, ix.X)
@@ -54,6 +54,8 @@ var testExprs = []testEntry{
 	// non-type expressions
 	dup("(x)"),
 	dup("x.f"),
+	dup("x?.f?.g"),
+	dup("x ?? y"),
 	dup("a[i]"),
 
 	dup("s[:]"),
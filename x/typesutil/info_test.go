@@ -2458,3 +2458,94 @@ func main() {
 }
 `)
 }
+
+func TestCStringLit(t *testing.T) {
+	testXGoInfo(t, `
+import (
+	"c"
+	"py"
+)
+
+name := "XGo"
+c.printf c"Hello, ${name}!\n"
+c.printf c"100$$\n"
+s := py"Hi, ${name}"
+`, ``, `== types ==
+000:  7: 9 | "XGo"               *ast.BasicLit                  | value   : untyped string = "XGo" | constant
+001:  8: 1 | c.printf            *ast.SelectorExpr              | value   : func(format *github.com/goplus/lib/c.Char, __llgo_va_list ...any) github.com/goplus/lib/c.Int | value
+002:  8: 1 | c.printf c"Hello, ${name}!\n" *ast.CallExpr                  | value   : github.com/goplus/lib/c.Int | value
+003:  8:10 | c"Hello, ${name}!\n" *ast.BasicLit                  | value   : *github.com/goplus/lib/c.Char | value
+004:  8:21 | name                *ast.Ident                     | var     : string | variable
+005:  9: 1 | c.printf            *ast.SelectorExpr              | value   : func(format *github.com/goplus/lib/c.Char, __llgo_va_list ...any) github.com/goplus/lib/c.Int | value
+006:  9: 1 | c.printf c"100$$\n" *ast.CallExpr                  | value   : github.com/goplus/lib/c.Int | value
+007:  9:10 | c"100$$\n"          *ast.BasicLit                  | value   : *github.com/goplus/lib/c.Char | value
+008: 10: 6 | py"Hi, ${name}"     *ast.BasicLit                  | value   : *github.com/goplus/lib/py.Object | value
+009: 10:15 | name                *ast.Ident                     | var     : string | variable
+== defs ==
+000:  7: 1 | main                | func main.main()
+001:  7: 1 | name                | var name string
+002: 10: 1 | s                   | var s *github.com/goplus/lib/py.Object
+== uses ==
+000:  8: 1 | c                   | package c ("github.com/goplus/lib/c")
+001:  8: 3 | printf              | func github.com/goplus/lib/c.Printf(format *github.com/goplus/lib/c.Char, __llgo_va_list ...any) github.com/goplus/lib/c.Int
+002:  8:21 | name                | var name string
+003:  9: 1 | c                   | package c ("github.com/goplus/lib/c")
+004:  9: 3 | printf              | func github.com/goplus/lib/c.Printf(format *github.com/goplus/lib/c.Char, __llgo_va_list ...any) github.com/goplus/lib/c.Int
+005: 10:15 | name                | var name string`)
+}
+
+func TestOptChain(t *testing.T) {
+	testXGoInfo(t, `
+type T struct{ next *T; m map[string]int }
+var p *T
+a := p?.next?.m["x"]
+b := p?.next ?? p
+_, _ = a, b
+`, ``, `== types ==
+000:  2: 8 | struct {
+	next *T
+	m    map[string]int
+} *ast.StructType                | type    : struct{next *main.T; m map[string]int} | type
+001:  2:21 | *T                  *ast.StarExpr                  | type    : *main.T | type
+002:  2:22 | T                   *ast.Ident                     | type    : main.T | type
+003:  2:27 | map[string]int      *ast.MapType                   | type    : map[string]int | type
+004:  2:31 | string              *ast.Ident                     | type    : string | type
+005:  2:38 | int                 *ast.Ident                     | type    : int | type
+006:  3: 7 | *T                  *ast.StarExpr                  | type    : *main.T | type
+007:  3: 8 | T                   *ast.Ident                     | type    : main.T | type
+008:  4: 6 | p                   *ast.Ident                     | var     : *main.T | variable
+009:  4: 6 | p?                  *ast.OptChainExpr              | var     : *main.T | variable
+010:  4: 6 | p?.next             *ast.SelectorExpr              | var     : *main.T | variable
+011:  4: 6 | p?.next?            *ast.OptChainExpr              | var     : *main.T | variable
+012:  4: 6 | p?.next?.m          *ast.SelectorExpr              | var     : map[string]int | variable
+013:  4: 6 | p?.next?.m["x"]     *ast.IndexExpr                 | mapindex : int | map index expression
+014:  4:17 | "x"                 *ast.BasicLit                  | value   : untyped string = "x" | constant
+015:  5: 6 | p                   *ast.Ident                     | var     : *main.T | variable
+016:  5: 6 | p?                  *ast.OptChainExpr              | var     : *main.T | variable
+017:  5: 6 | p?.next             *ast.SelectorExpr              | var     : *main.T | variable
+018:  5: 6 | p?.next ?? p        *ast.BinaryExpr                | value   : *main.T | value
+019:  5:17 | p                   *ast.Ident                     | var     : *main.T | variable
+020:  6: 8 | a                   *ast.Ident                     | var     : int | variable
+021:  6:11 | b                   *ast.Ident                     | var     : *main.T | variable
+== defs ==
+000:  2: 6 | T                   | type main.T struct{next *main.T; m map[string]int}
+001:  2:16 | next                | field next *main.T
+002:  2:25 | m                   | field m map[string]int
+003:  3: 5 | p                   | var main.p *main.T
+004:  4: 1 | a                   | var a int
+005:  4: 1 | main                | func main.main()
+006:  5: 1 | b                   | var b *main.T
+== uses ==
+000:  2:22 | T                   | type main.T struct{next *main.T; m map[string]int}
+001:  2:31 | string              | type string
+002:  2:38 | int                 | type int
+003:  3: 8 | T                   | type main.T struct{next *main.T; m map[string]int}
+004:  4: 6 | p                   | var main.p *main.T
+005:  4: 9 | next                | field next *main.T
+006:  4:15 | m                   | field m map[string]int
+007:  5: 6 | p                   | var main.p *main.T
+008:  5: 9 | next                | field next *main.T
+009:  5:17 | p                   | var main.p *main.T
+010:  6: 8 | a                   | var a int
+011:  6:11 | b                   | var b *main.T`)
+}